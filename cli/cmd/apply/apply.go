@@ -4,6 +4,7 @@ package apply
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -11,6 +12,7 @@ import (
 	"github.com/peter-evans/kdef/cli/config"
 	"github.com/peter-evans/kdef/cli/ctl/apply"
 	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/helpers/selector"
 	"github.com/peter-evans/kdef/core/model/opt"
 )
 
@@ -27,11 +29,19 @@ func Command(cOpts *config.Options) *cobra.Command {
 Accepts one or more glob patterns matching the paths of definitions to apply.
 Directories matching patterns are ignored.
 
+Alternatively, a plan created by "kdef plan" can be applied with "--plan".
+Exactly the planned operations are executed, and the apply is refused if the
+cluster differs or the remote state of any resource has drifted.
+
 The minimum Kafka version required to apply definitions:
 acl (Kafka 0.11.0+)
 broker (Kafka 0.11.0+)
+brokerLogger (Kafka 2.4.0+)
 brokers (Kafka 0.11.0+)
+consumerGroup (Kafka 0.10.2+)
+quota (Kafka 2.6.0+)
 topic (Kafka 2.4.0+)
+user (Kafka 2.7.0+)
 
 Manual: https://peter-evans.github.io/kdef`,
 		Example: `# apply all definitions in directory "topics" (dry-run)
@@ -41,19 +51,67 @@ kdef apply "topics/*.yml" --dry-run
 kdef apply "resources/**/*.yml" --dry-run
 
 # apply a topic definition from stdin (dry-run)
-cat topics/my_topic.yml | kdef apply - --dry-run`,
+cat topics/my_topic.yml | kdef apply - --dry-run
+
+# apply all definitions and prune undeclared topics and acls prefixed with "store." (dry-run)
+kdef apply "resources/**/*.yml" --prune --prune-match "^store\." --dry-run
+
+# apply all definitions with the patches of the production overlay (dry-run)
+kdef apply "resources/**/*.yml" --overlay envs/prod --dry-run
+
+# apply the definitions labelled with team "payments", except test resources (dry-run)
+kdef apply "resources/**/*.yml" --selector team=payments,tier!=test --dry-run
+
+# apply a plan created by "kdef plan"
+kdef apply --plan plan.json`,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
-		Args:                  cobra.MinimumNArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(opts.PlanPath) > 0 {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		PreRunE: func(_ *cobra.Command, _ []string) error {
 			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			opts.LenientAPIVersions = config.LenientAPIVersions()
 			if opts.DefinitionFormat == opt.UnsupportedFormat {
 				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
 			}
+			if opts.Parallelism < 1 {
+				return fmt.Errorf("\"parallelism\" must be greater or equal to 1")
+			}
 			if opts.ReassAwaitTimeout < 0 {
 				return fmt.Errorf("\"reass-await-timeout\" must be greater or equal to 0")
 			}
+			if len(opts.PlanPath) > 0 {
+				if opts.Prune || len(opts.PropertyOverrides) > 0 {
+					return fmt.Errorf("\"prune\" and \"prop-override\" cannot be used with \"plan\"")
+				}
+				if len(opts.VarsFile) > 0 || opts.Substitute || opts.Template || len(opts.Overlay) > 0 || len(opts.Selector) > 0 {
+					return fmt.Errorf(
+						"\"vars-file\", \"substitute\", \"template\", \"overlay\" and \"selector\" cannot be used with \"plan\"",
+					)
+				}
+			}
+			if opts.Prune {
+				if len(opts.Selector) > 0 {
+					// Resources of unselected definitions would be undeclared and pruned.
+					return fmt.Errorf("\"prune\" cannot be used with \"selector\"")
+				}
+				if len(opts.PruneMatch) == 0 {
+					return fmt.Errorf("\"prune-match\" must be supplied when \"prune\" is enabled")
+				}
+				if _, err := regexp.Compile(opts.PruneMatch); err != nil {
+					return fmt.Errorf("\"prune-match\" must be a valid regular expression: %v", err)
+				}
+			}
+			if len(opts.Selector) > 0 {
+				if _, err := selector.Parse(opts.Selector); err != nil {
+					return fmt.Errorf("\"selector\" must be a valid label selector: %v", err)
+				}
+			}
 			return nil
 		},
 		RunE: func(_ *cobra.Command, args []string) error {
@@ -108,6 +166,30 @@ cat topics/my_topic.yml | kdef apply - --dry-run`,
 		0,
 		"time in seconds to wait for topic partition reassignments to complete before timing out",
 	)
+	cmd.Flags().BoolVar(
+		&opts.Prune,
+		"prune",
+		false,
+		"delete undeclared topics and acl resources matching --prune-match (internal topics are excluded)",
+	)
+	cmd.Flags().StringVar(
+		&opts.PruneMatch,
+		"prune-match",
+		"",
+		"regular expression matching the names of undeclared resources to prune (required with --prune)",
+	)
+	cmd.Flags().StringVar(
+		&opts.PlanPath,
+		"plan",
+		"",
+		"path of a plan file created by \"kdef plan\" to apply instead of definitions",
+	)
+	cmd.Flags().IntVar(
+		&opts.Parallelism,
+		"parallelism",
+		1,
+		"maximum number of definitions to apply concurrently",
+	)
 	cmd.Flags().StringArrayVarP(
 		&opts.PropertyOverrides,
 		"prop-override",
@@ -115,6 +197,37 @@ cat topics/my_topic.yml | kdef apply - --dry-run`,
 		nil,
 		"definition property override for overridable properties (e.g. -P topic.spec.managedAssignments.balance=all)",
 	)
+	cmd.Flags().StringVar(
+		&opts.VarsFile,
+		"vars-file",
+		"",
+		"path of a file of variables referenced by definitions",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Substitute,
+		"substitute",
+		false,
+		"substitute references to variables in definitions, enabled by \"--vars-file\"",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Template,
+		"template",
+		false,
+		"execute definitions as templates before substituting variables",
+	)
+	cmd.Flags().StringVar(
+		&opts.Overlay,
+		"overlay",
+		"",
+		"path of an overlay file or directory of patches to apply to definitions",
+	)
+	cmd.Flags().StringVarP(
+		&opts.Selector,
+		"selector",
+		"l",
+		"",
+		"label selector of the definitions to apply (e.g. team=payments,tier!=test)",
+	)
 
 	return cmd
 }
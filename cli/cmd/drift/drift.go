@@ -0,0 +1,161 @@
+// Package drift implements the drift command and executes the controller.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/apply"
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// Command creates the drift command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := apply.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "drift <definitions>... [options]",
+		Short: "Report drift of cluster resources from definitions",
+		Long: `Report drift of cluster resources from definitions.
+
+Accepts one or more glob patterns matching the paths of definitions to compare.
+Directories matching patterns are ignored.
+
+Definitions are compared with the remote state of their resources in read-only
+mode and a drift report is output for each resource. The report includes
+changed config keys with the source of the remote value, partition and
+replication factor deltas, assignment and leader drift, and ACL entries that
+are missing or extra.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# report drift of all definitions in directory "topics"
+kdef drift "topics/*.yml"
+
+# output a JSON drift report and exit with 1 if any resource has drifted
+kdef drift "resources/**/*.yml" -o json --exit-code
+
+# report drift of the definitions labelled with team "payments"
+kdef drift "resources/**/*.yml" --selector team=payments`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			opts.LenientAPIVersions = config.LenientAPIVersions()
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			if !str.Contains(opts.DriftOutput, apply.DriftOutputValidValues) {
+				return fmt.Errorf("\"output\" must be one of %q", strings.Join(apply.DriftOutputValidValues, "|"))
+			}
+			if opts.Parallelism < 1 {
+				return fmt.Errorf("\"parallelism\" must be greater or equal to 1")
+			}
+			if len(opts.Selector) > 0 {
+				if _, err := selector.Parse(opts.Selector); err != nil {
+					return fmt.Errorf("\"selector\" must be a valid label selector: %v", err)
+				}
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if opts.DriftOutput == apply.DriftOutputJSON {
+				log.Quiet = true
+			}
+			opts.DryRun = true
+
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			ctl := apply.NewApplyController(cl, args, opts)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&opts.DriftOutput,
+		"output",
+		"o",
+		apply.DriftOutputTable,
+		fmt.Sprintf("drift report output [%s] (json implies --quiet)", strings.Join(apply.DriftOutputValidValues, "|")),
+	)
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().BoolVarP(
+		&opts.ExitCode,
+		"exit-code",
+		"e",
+		false,
+		"causes the program to exit with 1 if any resource has drifted and 0 otherwise",
+	)
+	cmd.Flags().BoolVarP(
+		&opts.ContinueOnError,
+		"continue-on-error",
+		"c",
+		false,
+		"comparing resource definitions is not interrupted if there are errors",
+	)
+	cmd.Flags().IntVar(
+		&opts.Parallelism,
+		"parallelism",
+		1,
+		"maximum number of definitions to compare concurrently",
+	)
+	cmd.Flags().StringArrayVarP(
+		&opts.PropertyOverrides,
+		"prop-override",
+		"P",
+		nil,
+		"definition property override for overridable properties (e.g. -P topic.spec.managedAssignments.balance=all)",
+	)
+	cmd.Flags().StringVar(
+		&opts.VarsFile,
+		"vars-file",
+		"",
+		"path of a file of variables referenced by definitions",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Substitute,
+		"substitute",
+		false,
+		"substitute references to variables in definitions, enabled by \"--vars-file\"",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Template,
+		"template",
+		false,
+		"execute definitions as templates before substituting variables",
+	)
+	cmd.Flags().StringVar(
+		&opts.Overlay,
+		"overlay",
+		"",
+		"path of an overlay file or directory of patches to apply to definitions",
+	)
+	cmd.Flags().StringVarP(
+		&opts.Selector,
+		"selector",
+		"l",
+		"",
+		"label selector of the definitions to compare (e.g. team=payments,tier!=test)",
+	)
+
+	return cmd
+}
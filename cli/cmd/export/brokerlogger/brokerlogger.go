@@ -0,0 +1,77 @@
+// Package brokerlogger implements the export brokerlogger command and executes the controller.
+package brokerlogger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/export"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the export brokerlogger command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := export.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "brokerlogger [options]",
+		Short: "Export broker loggers to definitions",
+		Long: `Export broker loggers to definitions (Kafka 2.4.0+).
+
+Exports the root logger and loggers with a level that differs from the root logger.
+
+Exports to stdout by default. Supply the --output-dir option to create definition files.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# export broker logger definitions to the directory "brokerlogger"
+kdef export brokerlogger --output-dir "brokerlogger"
+
+# export broker logger definitions to stdout
+kdef export brokerlogger --quiet`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			ctl := export.NewExportController(cl, opts, def.KindBrokerLogger)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().StringVarP(
+		&opts.OutputDir,
+		"output-dir",
+		"o",
+		"",
+		"output directory path for definition files; non-existent directories will be created",
+	)
+	cmd.Flags().BoolVarP(&opts.Overwrite, "overwrite", "w", false, "overwrite existing files in output directory")
+
+	return cmd
+}
@@ -0,0 +1,80 @@
+// Package consumergroup implements the export consumergroup command and executes the controller.
+package consumergroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/export"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the export consumergroup command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := export.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "consumergroup [options]",
+		Short: "Export consumer group offsets to definitions",
+		Long: `Export the committed offsets of consumer groups to definitions (Kafka 0.10.2+).
+
+Exports to stdout by default. Supply the --output-dir option to create definition files.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# export all consumer groups to the directory "consumergroups"
+kdef export consumergroup --output-dir "consumergroups"
+
+# export all consumer groups to stdout
+kdef export consumergroup --quiet
+
+# export all consumer groups starting with "myapp"
+kdef export consumergroup --match "myapp.*"`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			ctl := export.NewExportController(cl, opts, def.KindConsumerGroup)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().StringVarP(
+		&opts.OutputDir,
+		"output-dir",
+		"o",
+		"",
+		"output directory path for definition files; non-existent directories will be created",
+	)
+	cmd.Flags().BoolVarP(&opts.Overwrite, "overwrite", "w", false, "overwrite existing files in output directory")
+	cmd.Flags().StringVarP(&opts.Match, "match", "m", ".*", "regular expression matching group names to include")
+	cmd.Flags().StringVarP(&opts.Exclude, "exclude", "e", ".^", "regular expression matching group names to exclude")
+
+	return cmd
+}
@@ -6,8 +6,12 @@ import (
 
 	"github.com/peter-evans/kdef/cli/cmd/export/acl"
 	"github.com/peter-evans/kdef/cli/cmd/export/broker"
+	"github.com/peter-evans/kdef/cli/cmd/export/brokerlogger"
 	"github.com/peter-evans/kdef/cli/cmd/export/brokers"
+	"github.com/peter-evans/kdef/cli/cmd/export/consumergroup"
+	"github.com/peter-evans/kdef/cli/cmd/export/quota"
 	"github.com/peter-evans/kdef/cli/cmd/export/topic"
+	"github.com/peter-evans/kdef/cli/cmd/export/user"
 	"github.com/peter-evans/kdef/cli/config"
 )
 
@@ -23,8 +27,12 @@ func Command(cOpts *config.Options) *cobra.Command {
 	cmd.AddCommand(
 		acl.Command(cOpts),
 		broker.Command(cOpts),
+		brokerlogger.Command(cOpts),
 		brokers.Command(cOpts),
+		consumergroup.Command(cOpts),
+		quota.Command(cOpts),
 		topic.Command(cOpts),
+		user.Command(cOpts),
 	)
 
 	return cmd
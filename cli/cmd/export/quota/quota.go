@@ -0,0 +1,75 @@
+// Package quota implements the export quota command and executes the controller.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/export"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the export quota command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := export.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "quota [options]",
+		Short: "Export client quotas to definitions",
+		Long: `Export client quotas to definitions (Kafka 2.6.0+).
+
+Exports to stdout by default. Supply the --output-dir option to create definition files.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# export quota definitions to the directory "quota"
+kdef export quota --output-dir "quota"
+
+# export quota definitions to stdout
+kdef export quota --quiet`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			ctl := export.NewExportController(cl, opts, def.KindQuota)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().StringVarP(
+		&opts.OutputDir,
+		"output-dir",
+		"o",
+		"",
+		"output directory path for definition files; non-existent directories will be created",
+	)
+	cmd.Flags().BoolVarP(&opts.Overwrite, "overwrite", "w", false, "overwrite existing files in output directory")
+
+	return cmd
+}
@@ -35,7 +35,10 @@ kdef export topic --output-dir "topics"
 kdef export topic --quiet
 
 # export all topics starting with "myapp"
-kdef export topic --match "myapp.*"`,
+kdef export topic --match "myapp.*"
+
+# export all topics and factor configs shared by topics out to topic profiles
+kdef export topic --output-dir "topics" --infer-profiles`,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
@@ -88,6 +91,12 @@ kdef export topic --match "myapp.*"`,
 		"none",
 		fmt.Sprintf("partition assignments to include in topic definitions [%s]", strings.Join(opt.AssignmentsValidValues, "|")),
 	)
+	cmd.Flags().BoolVar(
+		&opts.TopicInferProfiles,
+		"infer-profiles",
+		false,
+		"factor configs shared by topics out to topic profile definitions",
+	)
 
 	return cmd
 }
@@ -0,0 +1,161 @@
+// Package plan implements the plan command and executes the controller.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/apply"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the plan command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := apply.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "plan <definitions>... [options]",
+		Short: "Create a plan to apply definitions to cluster",
+		Long: `Create a plan to apply definitions to cluster.
+
+Accepts one or more glob patterns matching the paths of definitions to plan.
+Directories matching patterns are ignored.
+
+Definitions are applied in dry-run mode and the resulting operations are saved
+to a plan file, along with a fingerprint of the remote state of each resource
+and the ID of the cluster. The plan can be reviewed and later applied with
+"kdef apply --plan". Applying a plan executes exactly the planned operations
+and is refused if the remote state of any resource has drifted.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# create a plan for all definitions in directory "topics"
+kdef plan "topics/*.yml" -o plan.json
+
+# create a plan for all definitions and prune undeclared topics and acls prefixed with "store."
+kdef plan "resources/**/*.yml" --prune --prune-match "^store\." -o plan.json
+
+# apply the plan
+kdef apply --plan plan.json`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			opts.LenientAPIVersions = config.LenientAPIVersions()
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			if opts.Parallelism < 1 {
+				return fmt.Errorf("\"parallelism\" must be greater or equal to 1")
+			}
+			if len(opts.PlanOutput) == 0 {
+				return fmt.Errorf("\"output\" must be supplied")
+			}
+			if opts.Prune {
+				if len(opts.Selector) > 0 {
+					// Resources of unselected definitions would be undeclared and pruned.
+					return fmt.Errorf("\"prune\" cannot be used with \"selector\"")
+				}
+				if len(opts.PruneMatch) == 0 {
+					return fmt.Errorf("\"prune-match\" must be supplied when \"prune\" is enabled")
+				}
+				if _, err := regexp.Compile(opts.PruneMatch); err != nil {
+					return fmt.Errorf("\"prune-match\" must be a valid regular expression: %v", err)
+				}
+			}
+			if len(opts.Selector) > 0 {
+				if _, err := selector.Parse(opts.Selector); err != nil {
+					return fmt.Errorf("\"selector\" must be a valid label selector: %v", err)
+				}
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			opts.DryRun = true
+
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			ctl := apply.NewApplyController(cl, args, opts)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.PlanOutput, "output", "o", "", "path of the plan file to create")
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().BoolVar(
+		&opts.Prune,
+		"prune",
+		false,
+		"delete undeclared topics and acl resources matching --prune-match (internal topics are excluded)",
+	)
+	cmd.Flags().StringVar(
+		&opts.PruneMatch,
+		"prune-match",
+		"",
+		"regular expression matching the names of undeclared resources to prune (required with --prune)",
+	)
+	cmd.Flags().IntVar(
+		&opts.Parallelism,
+		"parallelism",
+		1,
+		"maximum number of definitions to plan concurrently",
+	)
+	cmd.Flags().StringArrayVarP(
+		&opts.PropertyOverrides,
+		"prop-override",
+		"P",
+		nil,
+		"definition property override for overridable properties (e.g. -P topic.spec.managedAssignments.balance=all)",
+	)
+	cmd.Flags().StringVar(
+		&opts.VarsFile,
+		"vars-file",
+		"",
+		"path of a file of variables referenced by definitions",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Substitute,
+		"substitute",
+		false,
+		"substitute references to variables in definitions, enabled by \"--vars-file\"",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Template,
+		"template",
+		false,
+		"execute definitions as templates before substituting variables",
+	)
+	cmd.Flags().StringVar(
+		&opts.Overlay,
+		"overlay",
+		"",
+		"path of an overlay file or directory of patches to apply to definitions",
+	)
+	cmd.Flags().StringVarP(
+		&opts.Selector,
+		"selector",
+		"l",
+		"",
+		"label selector of the definitions to plan (e.g. team=payments,tier!=test)",
+	)
+
+	return cmd
+}
@@ -0,0 +1,182 @@
+// Package reconcile implements the reconcile command and executes the controller.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/reconcile"
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the reconcile command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := reconcile.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "reconcile <definitions>... [options]",
+		Short: "Continuously apply definitions to cluster",
+		Long: `Continuously apply definitions to cluster.
+
+Accepts one or more glob patterns matching the paths of definitions to apply.
+Directories matching patterns are ignored.
+
+Runs as a long-lived process that applies definitions at a fixed interval.
+Directories matching the patterns are watched, and definitions are re-read and
+applied when they change. Definitions are otherwise read once and reused.
+After consecutive failures, the interval is doubled up to a maximum backoff.
+
+The status of the last reconcile and each resource is served as JSON at
+"/status" on the status address.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# reconcile all definitions under "resources" every 5 minutes
+kdef reconcile "resources/**/*.yml" --interval 5m
+
+# review changes every minute without applying them
+kdef reconcile "resources/**/*.yml" --dry-run`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			for _, arg := range args {
+				if arg == "-" {
+					return fmt.Errorf("definitions cannot be read from stdin")
+				}
+			}
+			opts.Apply.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			opts.Apply.LenientAPIVersions = config.LenientAPIVersions()
+			if opts.Apply.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			if opts.Apply.Parallelism < 1 {
+				return fmt.Errorf("\"parallelism\" must be greater or equal to 1")
+			}
+			if opts.Apply.ReassAwaitTimeout < 0 {
+				return fmt.Errorf("\"reass-await-timeout\" must be greater or equal to 0")
+			}
+			if opts.Interval <= 0 {
+				return fmt.Errorf("\"interval\" must be greater than 0")
+			}
+			if opts.MaxBackoff < opts.Interval {
+				return fmt.Errorf("\"max-backoff\" must be greater or equal to \"interval\"")
+			}
+			if len(opts.Apply.Selector) > 0 {
+				if _, err := selector.Parse(opts.Apply.Selector); err != nil {
+					return fmt.Errorf("\"selector\" must be a valid label selector: %v", err)
+				}
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if opts.Apply.DryRun {
+				log.InfoWithKeyf("dry-run", "Enabled")
+			}
+
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			ctl := reconcile.NewReconcileController(cl, args, opts)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.Interval, "interval", time.Minute, "interval between reconciles")
+	cmd.Flags().DurationVar(
+		&opts.MaxBackoff,
+		"max-backoff",
+		15*time.Minute,
+		"maximum interval between reconciles after consecutive failures",
+	)
+	cmd.Flags().StringVar(
+		&opts.StatusAddress,
+		"status-address",
+		":8080",
+		"address to serve the status endpoint on (disabled if empty)",
+	)
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().BoolVarP(&opts.Apply.DryRun, "dry-run", "d", false, "validate and review the operation only")
+	cmd.Flags().BoolVarP(
+		&opts.Apply.ContinueOnError,
+		"continue-on-error",
+		"c",
+		false,
+		"applying resource definitions is not interrupted if there are errors",
+	)
+	cmd.Flags().IntVar(
+		&opts.Apply.Parallelism,
+		"parallelism",
+		1,
+		"maximum number of definitions to apply concurrently",
+	)
+	cmd.Flags().IntVarP(
+		&opts.Apply.ReassAwaitTimeout,
+		"reass-await-timeout",
+		"r",
+		0,
+		"time in seconds to wait for topic partition reassignments to complete before timing out",
+	)
+	cmd.Flags().StringArrayVarP(
+		&opts.Apply.PropertyOverrides,
+		"prop-override",
+		"P",
+		nil,
+		"definition property override for overridable properties (e.g. -P topic.spec.managedAssignments.balance=all)",
+	)
+	cmd.Flags().StringVar(
+		&opts.Apply.VarsFile,
+		"vars-file",
+		"",
+		"path of a file of variables referenced by definitions",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Apply.Substitute,
+		"substitute",
+		false,
+		"substitute references to variables in definitions, enabled by \"--vars-file\"",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Apply.Template,
+		"template",
+		false,
+		"execute definitions as templates before substituting variables",
+	)
+	cmd.Flags().StringVar(
+		&opts.Apply.Overlay,
+		"overlay",
+		"",
+		"path of an overlay file or directory of patches to apply to definitions",
+	)
+	cmd.Flags().StringVarP(
+		&opts.Apply.Selector,
+		"selector",
+		"l",
+		"",
+		"label selector of the definitions to apply (e.g. team=payments,tier!=test)",
+	)
+
+	return cmd
+}
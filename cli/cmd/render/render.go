@@ -0,0 +1,97 @@
+// Package render implements the render command and executes the controller.
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/render"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the render command.
+func Command() *cobra.Command {
+	opts := render.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "render <definitions>... [options]",
+		Short: "Render definitions with variables, templates and overlays",
+		Long: `Render definitions with variables, templates and overlays.
+
+Accepts one or more glob patterns matching the paths of definitions to render.
+Directories matching patterns are ignored.
+
+If "--substitute" or "--vars-file" is supplied, references to variables of the form
+${NAME} or ${NAME:-default} are substituted with the values of variables from
+"--vars-file" or environment variables.
+Definitions are first executed as Go templates if "--template" is enabled.
+The patches of "--overlay" are then applied to the definitions they target.
+
+The rendered definition documents are output with the position of their source,
+exactly as they would be loaded by apply, plan, drift and validate.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# render all definitions in directories under "resources" with production variables
+kdef render "resources/**/*.yml" --vars-file envs/prod.yml
+
+# render definitions with the patches of the production overlay
+kdef render "resources/**/*.yml" --overlay envs/prod
+
+# render templated topic definitions
+kdef render "topics/*.yml" --vars-file envs/prod.yml --template`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			opts.LenientAPIVersions = config.LenientAPIVersions()
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctl := render.NewRenderController(args, opts)
+			return ctl.Execute()
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().StringVar(
+		&opts.VarsFile,
+		"vars-file",
+		"",
+		"path of a file of variables referenced by definitions",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Substitute,
+		"substitute",
+		false,
+		"substitute references to variables in definitions, enabled by \"--vars-file\"",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Template,
+		"template",
+		false,
+		"execute definitions as templates before substituting variables",
+	)
+	cmd.Flags().StringVar(
+		&opts.Overlay,
+		"overlay",
+		"",
+		"path of an overlay file or directory of patches to apply to definitions",
+	)
+
+	return cmd
+}
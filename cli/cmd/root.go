@@ -9,7 +9,13 @@ import (
 
 	"github.com/peter-evans/kdef/cli/cmd/apply"
 	"github.com/peter-evans/kdef/cli/cmd/configure"
+	"github.com/peter-evans/kdef/cli/cmd/drift"
 	"github.com/peter-evans/kdef/cli/cmd/export"
+	"github.com/peter-evans/kdef/cli/cmd/plan"
+	"github.com/peter-evans/kdef/cli/cmd/reconcile"
+	"github.com/peter-evans/kdef/cli/cmd/render"
+	"github.com/peter-evans/kdef/cli/cmd/serve"
+	"github.com/peter-evans/kdef/cli/cmd/validate"
 	"github.com/peter-evans/kdef/cli/config"
 	"github.com/peter-evans/kdef/cli/log"
 )
@@ -61,7 +67,13 @@ Manual: https://peter-evans.github.io/kdef`,
 
 	cmd.AddCommand(
 		configure.Command(),
+		render.Command(),
+		validate.Command(cOpts),
+		plan.Command(cOpts),
 		apply.Command(cOpts),
+		drift.Command(cOpts),
+		reconcile.Command(cOpts),
+		serve.Command(cOpts),
 		export.Command(cOpts),
 	)
 
@@ -0,0 +1,87 @@
+// Package serve implements the serve command and executes the controller.
+package serve
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/serve"
+)
+
+// authTokenEnvVar is the environment variable the auth token is read from if not supplied as an option.
+const authTokenEnvVar = "KDEF_AUTH_TOKEN"
+
+// Command creates the serve command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := serve.ControllerOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve [options]",
+		Short: "Serve an HTTP API to apply definitions and export resources",
+		Long: `Serve an HTTP API to apply definitions and export resources.
+
+Endpoints:
+POST /v1/apply         apply definitions and return the apply results
+POST /v1/plan          apply definitions in dry-run mode and return the apply results
+GET  /v1/export/{kind} export resources of kind acl, broker, brokers or topic
+
+If an auth token is supplied, requests must present it as a bearer token.
+The auth token can also be supplied with the environment variable KDEF_AUTH_TOKEN.
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# serve the API on localhost
+KDEF_AUTH_TOKEN=secret kdef serve
+
+# apply a topic definition (dry-run)
+curl -H "Authorization: Bearer secret" localhost:8000/v1/plan \
+  -d '{"definitions": "apiVersion: v1\nkind: topic\nmetadata:\n  name: store.foo\nspec:\n  partitions: 3\n  replicationFactor: 2"}'`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if opts.MaxConcurrentRequests < 1 {
+				return fmt.Errorf("\"max-concurrent-requests\" must be greater or equal to 1")
+			}
+			if len(opts.AuthToken) == 0 {
+				opts.AuthToken = os.Getenv(authTokenEnvVar)
+			}
+			opts.LenientAPIVersions = config.LenientAPIVersions()
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cl, err := config.NewClient(cOpts)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			ctl := serve.NewServeController(cl, opts)
+			return ctl.Execute(ctx)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Address, "address", "localhost:8000", "address to serve the API on")
+	cmd.Flags().StringVar(
+		&opts.AuthToken,
+		"auth-token",
+		"",
+		fmt.Sprintf("token requests must present as a bearer token (defaults to $%s)", authTokenEnvVar),
+	)
+	cmd.Flags().IntVar(
+		&opts.MaxConcurrentRequests,
+		"max-concurrent-requests",
+		1,
+		"maximum number of requests to handle concurrently; further requests wait",
+	)
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+// Package validate implements the validate command and executes the controller.
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/peter-evans/kdef/cli/config"
+	"github.com/peter-evans/kdef/cli/ctl/validate"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// Command creates the validate command.
+func Command(cOpts *config.Options) *cobra.Command {
+	opts := validate.ControllerOptions{}
+	var defFormat string
+
+	cmd := &cobra.Command{
+		Use:   "validate <definitions>... [options]",
+		Short: "Validate definitions without a cluster connection",
+		Long: `Validate definitions without a cluster connection.
+
+Accepts one or more glob patterns matching the paths of definitions to validate.
+Directories matching patterns are ignored.
+
+Every definition document is parsed and validated by the rules of its kind.
+Unknown fields, resources defined more than once, and violations of the rules
+of the configured policy are reported with the file and document position.
+
+Definitions that are validated using cluster metadata during an apply can be
+validated offline with broker metadata supplied by "--brokers-file".
+
+Manual: https://peter-evans.github.io/kdef`,
+		Example: `# validate all definitions in directories under "resources"
+kdef validate "resources/**/*.yml"
+
+# validate topic definitions using broker metadata
+kdef validate "topics/*.yml" --brokers-file brokers.yml`,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			opts.DefinitionFormat = opt.ParseDefinitionFormat(defFormat)
+			opts.LenientAPIVersions = config.LenientAPIVersions()
+			if opts.DefinitionFormat == opt.UnsupportedFormat {
+				return fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+			}
+			if len(opts.Selector) > 0 {
+				if _, err := selector.Parse(opts.Selector); err != nil {
+					return fmt.Errorf("\"selector\" must be a valid label selector: %v", err)
+				}
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			cc, err := config.LoadConfig(cOpts)
+			if err != nil {
+				return err
+			}
+			opts.PolicyFile = cc.PolicyFile
+
+			ctl := validate.NewValidateController(args, opts)
+			return ctl.Execute()
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&defFormat,
+		"format",
+		"f",
+		"yaml",
+		fmt.Sprintf("resource definition format [%s]", strings.Join(opt.DefinitionFormatValidValues, "|")),
+	)
+	cmd.Flags().StringVar(
+		&opts.BrokersFile,
+		"brokers-file",
+		"",
+		"path of a file of broker ids and racks to validate definitions using broker metadata",
+	)
+	cmd.Flags().StringVar(
+		&opts.VarsFile,
+		"vars-file",
+		"",
+		"path of a file of variables referenced by definitions",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Substitute,
+		"substitute",
+		false,
+		"substitute references to variables in definitions, enabled by \"--vars-file\"",
+	)
+	cmd.Flags().BoolVar(
+		&opts.Template,
+		"template",
+		false,
+		"execute definitions as templates before substituting variables",
+	)
+	cmd.Flags().StringVar(
+		&opts.Overlay,
+		"overlay",
+		"",
+		"path of an overlay file or directory of patches to apply to definitions",
+	)
+	cmd.Flags().StringVarP(
+		&opts.Selector,
+		"selector",
+		"l",
+		"",
+		"label selector of the definitions to validate (e.g. team=payments,tier!=test)",
+	)
+
+	return cmd
+}
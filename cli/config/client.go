@@ -25,3 +25,8 @@ func NewClient(opts *Options) (*client.Client, error) {
 
 	return cl, nil
 }
+
+// LoadConfig loads configuration from several sources without creating a client.
+func LoadConfig(opts *Options) (*client.Config, error) {
+	return loadConfig(opts.ConfigPath, opts.ConfigOpts)
+}
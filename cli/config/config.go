@@ -23,6 +23,10 @@ const (
 	envVarPrefix          = "KDEF__"
 )
 
+// LenientAPIVersionsEnvVar is the environment variable of comma-separated apiVersions of definitions
+// that are accepted for every kind without rejecting unknown fields.
+const LenientAPIVersionsEnvVar = "KDEF_LENIENT_API_VERSIONS"
+
 var defaultClientConfig = map[string]interface{}{
 	"seedBrokers":        []string{"localhost:9092"},
 	"timeoutMs":          5000,
@@ -54,6 +58,18 @@ func DefaultConfigPath() string {
 	return path
 }
 
+// LenientAPIVersions returns the apiVersions of the lenient apiVersions environment variable.
+// Empty apiVersions are ignored.
+func LenientAPIVersions() []string {
+	var versions []string
+	for _, v := range strings.Split(os.Getenv(LenientAPIVersionsEnvVar), ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
 func loadConfig(configPath string, configOpts []string) (*client.Config, error) {
 	log.Debugf("Loading client config")
 
@@ -3,44 +3,55 @@ package apply
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 
 	"github.com/bmatcuk/doublestar/v4"
-	"github.com/ghodss/yaml"
-	"github.com/peter-evans/kdef/cli/ctl/apply/docparse"
 	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/audit"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/helpers/docparse"
+	"github.com/peter-evans/kdef/core/helpers/overlay"
+	"github.com/peter-evans/kdef/core/helpers/render"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/hooks"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/model/res"
-	"github.com/peter-evans/kdef/core/operators/acl"
-	"github.com/peter-evans/kdef/core/operators/broker"
-	"github.com/peter-evans/kdef/core/operators/brokers"
-	"github.com/peter-evans/kdef/core/operators/topic"
+	"github.com/peter-evans/kdef/core/policy"
+	"github.com/peter-evans/kdef/kdef"
 )
 
 const cannotContinueOnError = "cannot continue on error"
 
-type applier interface {
-	Execute(ctx context.Context) *res.ApplyResult
-}
-
 // ControllerOptions represents options to configure an apply controller.
 type ControllerOptions struct {
 	// Applier options.
-	DefinitionFormat  opt.DefinitionFormat
-	PropertyOverrides []string
-	DryRun            bool
-	ReassAwaitTimeout int
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	VarsFile           string
+	Substitute         bool
+	Template           bool
+	Overlay            string
+	Selector           string
+	DryRun             bool
+	ReassAwaitTimeout  int
+	Logger             *log.Logger
 
 	// Apply controller specific options.
+	Prune           bool
+	PruneMatch      string
 	ContinueOnError bool
 	ExitCode        bool
 	JSONOutput      bool
+	PlanOutput      string
+	PlanPath        string
+	Parallelism     int
+	DriftOutput     string
 }
 
 // NewApplyController creates a new apply controller.
@@ -49,67 +60,93 @@ func NewApplyController(
 	args []string,
 	opts ControllerOptions,
 ) *applyController { //revive:disable-line:unexported-return
-	return &applyController{
+	a := &applyController{
 		cl:   cl,
 		args: args,
 		opts: opts,
 	}
+	if !opts.DryRun && !a.driftMode() {
+		a.auditLog = audit.NewLog(cl)
+	}
+	return a
 }
 
 type applyController struct {
-	cl   *client.Client
-	args []string
-	opts ControllerOptions
+	cl       *client.Client
+	args     []string
+	opts     ControllerOptions
+	auditLog *audit.Log
+
+	// Internal fields.
+	policy     *policy.Policy
+	render     render.Options
+	overlay    *overlay.Overlay
+	loadedDefs []kdef.Definition
+	loaded     bool
 }
 
 // Execute implements the execution of the apply controller.
 func (a *applyController) Execute(ctx context.Context) error {
-	results := res.ApplyResults{}
-	var ctlErrors bool
+	if err := hooks.Validate(a.cl.Hooks()); err != nil {
+		return err
+	}
 
-	if a.args[0] == "-" {
-		// Apply definitions from stdin.
-		res, err := a.applyDefsFromStdin(ctx)
-		results = append(results, res...)
+	if err := a.loadPolicy(); err != nil {
+		return err
+	}
+
+	if err := a.loadRenderOptions(); err != nil {
+		return err
+	}
+
+	var defs []kdef.Definition
+	var loadErrors bool
+	if len(a.opts.PlanPath) > 0 {
+		var err error
+		defs, err = a.loadPlan(ctx)
 		if err != nil {
-			log.Error(err)
-			ctlErrors = true
+			return err
 		}
 	} else {
-		// Apply definitions from file.
-		for _, arg := range a.args {
-			basepath, pattern := doublestar.SplitPattern(arg)
-			fsys := os.DirFS(basepath)
-
-			err := doublestar.GlobWalk(fsys, pattern, func(p string, d fs.DirEntry) error {
-				if d.IsDir() {
-					return nil
-				}
+		defs, loadErrors = a.loadDefinitions()
+	}
 
-				res, err := a.applyDefsFromFile(ctx, filepath.Join(basepath, p))
-				results = append(results, res...)
-				if err != nil {
-					log.Error(err)
-					ctlErrors = true
-				}
-				if (err != nil || res.ContainsErr()) && !a.opts.ContinueOnError {
-					return fmt.Errorf("%s", cannotContinueOnError)
-				}
+	results, err := a.applyDefinitions(ctx, defs, a.policy)
+	if err != nil {
+		return err
+	}
+	ctlErrors := loadErrors
 
-				return nil
-			})
+	if a.driftMode() {
+		return a.reportDrift(defs, results, ctlErrors)
+	}
+
+	if a.opts.Prune {
+		if ctlErrors || results.ContainsErr() {
+			// Pruning with an incomplete set of declared resources could delete resources unintentionally.
+			log.Error(fmt.Errorf("prune skipped because apply completed with errors"))
+			ctlErrors = true
+		} else {
+			pruneDefs, pruneResults, err := a.prune(ctx, defs)
+			defs = append(defs, pruneDefs...)
+			results = append(results, pruneResults...)
 			if err != nil {
-				if err.Error() != cannotContinueOnError {
-					log.Error(err)
-					ctlErrors = true
-				}
-				if !a.opts.ContinueOnError {
-					break
-				}
+				log.Error(err)
+				ctlErrors = true
 			}
 		}
 	}
 
+	if len(a.opts.PlanOutput) > 0 && len(results) > 0 {
+		if ctlErrors || results.ContainsErr() {
+			log.Error(fmt.Errorf("plan not saved because planning completed with errors"))
+			ctlErrors = true
+		} else if err := a.savePlan(ctx, defs, results); err != nil {
+			log.Error(err)
+			ctlErrors = true
+		}
+	}
+
 	if a.opts.JSONOutput {
 		out, err := results.JSON()
 		if err != nil {
@@ -134,97 +171,217 @@ func (a *applyController) Execute(ctx context.Context) error {
 	return nil
 }
 
-func (a *applyController) applyDefsFromStdin(ctx context.Context) (res.ApplyResults, error) {
-	log.Infof("Reading definition(s) from stdin")
-	defDocs, err := docparse.FromStdin(docparse.Format(a.opts.DefinitionFormat))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read definition(s): %v", err)
+// loadPolicy loads the policy of the client configuration, if any.
+// Definitions are not checked against the policy in drift mode.
+func (a *applyController) loadPolicy() error {
+	a.policy = nil
+	if path := a.cl.PolicyFile(); len(path) > 0 && !a.driftMode() {
+		var err error
+		if a.policy, err = policy.Load(path); err != nil {
+			return err
+		}
 	}
-	return a.applyDefinitions(ctx, defDocs)
+	return nil
 }
 
-func (a *applyController) applyDefsFromFile(ctx context.Context, filepath string) (res.ApplyResults, error) {
-	log.Infof("Reading definition(s) from file %q", filepath)
-	defDocs, err := docparse.FromFile(filepath, docparse.Format(a.opts.DefinitionFormat))
+// loadRenderOptions loads the options to render definitions, and the overlay to patch them.
+func (a *applyController) loadRenderOptions() error {
+	a.render.Template = a.opts.Template
+	// Supplying variables enables substitution.
+	a.render.Substitute = a.opts.Substitute || len(a.opts.VarsFile) > 0
+	if len(a.opts.VarsFile) > 0 {
+		var err error
+		if a.render.Vars, err = render.LoadVars(a.opts.VarsFile); err != nil {
+			return err
+		}
+	}
+	if len(a.opts.Overlay) > 0 {
+		var err error
+		if a.overlay, err = overlay.Load(a.opts.Overlay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDefinitions loads definitions from stdin or files and returns true if there were errors.
+func (a *applyController) loadDefinitions() ([]kdef.Definition, bool) {
+	defs, ctlErrors := a.loadDefinitionArgs()
+	for _, p := range a.overlay.Unmatched() {
+		log.Warnf("Overlay %s did not match any definitions", p)
+	}
+	defs, profileErrors := a.resolveProfiles(defs)
+	defs, selectErrors := a.selectDefinitions(defs)
+	return defs, ctlErrors || profileErrors || selectErrors
+}
+
+// selectDefinitions selects the definitions with labels matching the label selector, if any,
+// and returns true if there were errors.
+func (a *applyController) selectDefinitions(defs []kdef.Definition) ([]kdef.Definition, bool) {
+	if len(a.opts.Selector) == 0 {
+		return defs, false
+	}
+
+	sel, err := selector.Parse(a.opts.Selector)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read definition(s): %v", err)
+		log.Error(err)
+		return nil, true
 	}
-	return a.applyDefinitions(ctx, defDocs)
+
+	selected := kdef.SelectDefinitions(defs, sel)
+	log.Infof("Selected %d of %d definition(s) matching selector %q", len(selected), len(defs), sel)
+	return selected, false
 }
 
-func (a *applyController) applyDefinitions(ctx context.Context, defDocs []string) (res.ApplyResults, error) {
-	resourceDefs, err := getResourceDefinitions(defDocs, a.opts.DefinitionFormat)
+// resolveProfiles resolves the topic profiles referenced by topic definitions and returns true if there were errors.
+// Topic profile definitions are not applied, and are not included in the resolved definitions.
+func (a *applyController) resolveProfiles(defs []kdef.Definition) ([]kdef.Definition, bool) {
+	profiles, err := kdef.TopicProfiles(defs)
 	if err != nil {
-		return nil, fmt.Errorf("invalid resource definition: %v", err)
-	}
-
-	var results res.ApplyResults
-	for i, resourceDef := range resourceDefs {
-		var applier applier
-
-		switch resourceDef.Kind {
-		case def.KindACL:
-			applier = acl.NewApplier(a.cl, defDocs[i], acl.ApplierOptions{
-				DefinitionFormat:  a.opts.DefinitionFormat,
-				PropertyOverrides: a.opts.PropertyOverrides,
-				DryRun:            a.opts.DryRun,
-			})
-		case def.KindBroker:
-			applier = broker.NewApplier(a.cl, defDocs[i], broker.ApplierOptions{
-				DefinitionFormat:  a.opts.DefinitionFormat,
-				PropertyOverrides: a.opts.PropertyOverrides,
-				DryRun:            a.opts.DryRun,
-			})
-		case def.KindBrokers:
-			applier = brokers.NewApplier(a.cl, defDocs[i], brokers.ApplierOptions{
-				DefinitionFormat:  a.opts.DefinitionFormat,
-				PropertyOverrides: a.opts.PropertyOverrides,
-				DryRun:            a.opts.DryRun,
-			})
-		case def.KindTopic:
-			applier = topic.NewApplier(a.cl, defDocs[i], topic.ApplierOptions{
-				DefinitionFormat:  a.opts.DefinitionFormat,
-				PropertyOverrides: a.opts.PropertyOverrides,
-				DryRun:            a.opts.DryRun,
-				ReassAwaitTimeout: a.opts.ReassAwaitTimeout,
-			})
+		log.Error(err)
+		return nil, true
+	}
+
+	var resolved []kdef.Definition
+	var ctlErrors bool
+	for _, d := range defs {
+		if d.Resource.Kind == def.KindTopicProfile {
+			continue
+		}
+		r, err := kdef.ResolveTopicProfiles(d, profiles)
+		if err != nil {
+			log.Error(d.Source.Errorf("%v", err))
+			ctlErrors = true
+			if !a.opts.ContinueOnError {
+				break
+			}
+			continue
 		}
+		resolved = append(resolved, r)
+	}
+	return resolved, ctlErrors
+}
 
-		res := applier.Execute(ctx)
-		results = append(results, res)
-		if res.GetErr() != nil && !a.opts.ContinueOnError {
-			return results, nil
+// loadDefinitionArgs loads definitions from stdin or the files matching args.
+func (a *applyController) loadDefinitionArgs() ([]kdef.Definition, bool) {
+	if a.args[0] == "-" {
+		// Load definitions from stdin.
+		defs, err := a.loadDefsFromStdin()
+		if err != nil {
+			log.Error(err)
+			return defs, true
 		}
+		return defs, false
 	}
 
-	return results, nil
-}
+	var defs []kdef.Definition
+	var ctlErrors bool
 
-func getResourceDefinitions(defDocs []string, format opt.DefinitionFormat) ([]def.ResourceDefinition, error) {
-	kinds := make([]def.ResourceDefinition, len(defDocs))
+	// Load definitions from file.
+	for _, arg := range a.args {
+		basepath, pattern := doublestar.SplitPattern(arg)
+		fsys := os.DirFS(basepath)
 
-	for i, defDoc := range defDocs {
-		var resourceDef def.ResourceDefinition
+		err := doublestar.GlobWalk(fsys, pattern, func(p string, d fs.DirEntry) error {
+			if d.IsDir() {
+				return nil
+			}
 
-		switch format {
-		case opt.YAMLFormat:
-			if err := yaml.Unmarshal([]byte(defDoc), &resourceDef); err != nil {
-				return nil, err
+			fileDefs, err := a.loadDefsFromFile(filepath.Join(basepath, p))
+			defs = append(defs, fileDefs...)
+			if err != nil {
+				log.Error(err)
+				ctlErrors = true
+				if !a.opts.ContinueOnError {
+					return fmt.Errorf("%s", cannotContinueOnError)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			if err.Error() != cannotContinueOnError {
+				log.Error(err)
+				ctlErrors = true
 			}
-		case opt.JSONFormat:
-			if err := json.Unmarshal([]byte(defDoc), &resourceDef); err != nil {
-				return nil, err
+			if !a.opts.ContinueOnError {
+				break
 			}
-		default:
-			return nil, fmt.Errorf("unsupported format")
 		}
+	}
+
+	return defs, ctlErrors
+}
+
+func (a *applyController) loadDefsFromStdin() ([]kdef.Definition, error) {
+	log.Infof("Reading definition(s) from stdin")
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition(s): %v", err)
+	}
+	return a.loadDefinitionBytes(b, "")
+}
 
-		if err := resourceDef.ValidateResource(); err != nil {
-			return nil, err
+func (a *applyController) loadDefsFromFile(filepath string) ([]kdef.Definition, error) {
+	log.Infof("Reading definition(s) from file %q", filepath)
+	b, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition(s): %v", err)
+	}
+	return a.loadDefinitionBytes(b, filepath)
+}
+
+func (a *applyController) loadDefinitionBytes(b []byte, file string) ([]kdef.Definition, error) {
+	b, err := render.Render(b, a.render)
+	if err != nil {
+		if len(file) > 0 {
+			return nil, fmt.Errorf("failed to render definition(s): %s: %v", file, err)
 		}
+		return nil, fmt.Errorf("failed to render definition(s): %v", err)
+	}
+	defDocs, err := docparse.FromBytes(b, docparse.Format(a.opts.DefinitionFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition(s): %v", err)
+	}
+	for i, defDoc := range defDocs {
+		if defDocs[i], err = a.overlay.Apply(defDoc, docparse.Format(a.opts.DefinitionFormat)); err != nil {
+			source := &def.Source{File: file, Doc: defDoc.Index + 1, Line: defDoc.Line}
+			return nil, source.Errorf("%v", err)
+		}
+	}
+	return kdef.DocumentDefinitions(
+		defDocs,
+		file,
+		a.opts.DefinitionFormat,
+		a.opts.PropertyOverrides,
+		a.opts.LenientAPIVersions,
+	)
+}
 
-		kinds[i] = resourceDef
+// applyDefinitions applies definitions with the options of the controller and a policy, if any.
+// Definitions failing to load are reported by the controller, so there may be none to apply.
+func (a *applyController) applyDefinitions(
+	ctx context.Context,
+	defs []kdef.Definition,
+	p *policy.Policy,
+) (res.ApplyResults, error) {
+	if len(defs) == 0 {
+		return nil, nil
+	}
+
+	opts := kdef.ApplyOptions{
+		DryRun:            a.opts.DryRun,
+		ReassAwaitTimeout: a.opts.ReassAwaitTimeout,
+		ContinueOnError:   a.opts.ContinueOnError,
+		ReadOnly:          a.driftMode(),
+		Parallelism:       a.opts.Parallelism,
+		Logger:            a.opts.Logger,
+		AuditLog:          a.auditLog,
+		Policy:            p,
+	}
+	if !a.driftMode() {
+		opts.Hooks = a.cl.Hooks()
 	}
 
-	return kinds, nil
+	return kdef.ApplyDefinitions(ctx, a.cl, defs, opts)
 }
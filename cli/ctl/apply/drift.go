@@ -0,0 +1,78 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"fmt"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+// Drift report outputs.
+const (
+	DriftOutputJSON  = "json"
+	DriftOutputTable = "table"
+)
+
+// DriftOutputValidValues is the list of valid drift report outputs.
+var DriftOutputValidValues = []string{DriftOutputJSON, DriftOutputTable}
+
+// driftMode determines if the controller reports drift instead of applying definitions.
+func (a *applyController) driftMode() bool {
+	return len(a.opts.DriftOutput) > 0
+}
+
+// reportDrift outputs the drift report of the definitions.
+func (a *applyController) reportDrift(defs []kdef.Definition, results res.ApplyResults, ctlErrors bool) error {
+	report := newDriftReport(defs, results)
+
+	switch a.opts.DriftOutput {
+	case DriftOutputJSON:
+		out, err := report.JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	case DriftOutputTable:
+		if len(report) > 0 {
+			fmt.Println(report.Table())
+		}
+	}
+
+	if len(results) == 0 {
+		log.Error(fmt.Errorf("no valid resource definitions found"))
+		ctlErrors = true
+	}
+
+	if ctlErrors || results.ContainsErr() {
+		return fmt.Errorf("drift detection completed with errors")
+	}
+
+	if a.opts.ExitCode && report.ContainsDrift() {
+		return fmt.Errorf("drift detected")
+	}
+
+	return nil
+}
+
+// newDriftReport creates a drift report from the apply results of definitions.
+func newDriftReport(defs []kdef.Definition, results res.ApplyResults) res.DriftReport {
+	report := make(res.DriftReport, len(results))
+	for i, result := range results {
+		if result.Drift != nil && result.GetErr() == nil {
+			report[i] = *result.Drift
+			continue
+		}
+		// The drift of a resource is unknown if its applier failed.
+		resourceDef := defs[i].Resource
+		report[i] = res.Drift{
+			Kind:   resourceDef.Kind,
+			Type:   resourceDef.Metadata.Type,
+			Name:   resourceDef.Metadata.Name,
+			Status: res.DriftStatusError,
+			Error:  result.Err,
+		}
+	}
+	return report
+}
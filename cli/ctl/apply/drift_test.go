@@ -0,0 +1,71 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+func Test_newDriftReport(t *testing.T) {
+	defs := []kdef.Definition{
+		{
+			Resource: def.ResourceDefinition{
+				Kind:     def.KindTopic,
+				Metadata: def.ResourceMetadataDefinition{Name: "store.foo"},
+			},
+		},
+		{
+			Resource: def.ResourceDefinition{
+				Kind:     def.KindACL,
+				Metadata: def.ResourceMetadataDefinition{Name: "store.bar", Type: "topic"},
+			},
+		},
+		{
+			Resource: def.ResourceDefinition{
+				Kind:     def.KindTopic,
+				Metadata: def.ResourceMetadataDefinition{Name: "store.baz"},
+			},
+		},
+	}
+	topicDrift := &res.Drift{
+		Kind:   def.KindTopic,
+		Name:   "store.foo",
+		Status: res.DriftStatusDrifted,
+		Partitions: &res.ValueDrift{
+			Name:   "partitions",
+			Local:  6,
+			Remote: 3,
+		},
+	}
+	results := res.ApplyResults{
+		{Drift: topicDrift},
+		{Err: "failed to describe acls"},
+	}
+
+	got := newDriftReport(defs, results)
+	want := res.DriftReport{
+		*topicDrift,
+		{
+			Kind:   def.KindACL,
+			Type:   "topic",
+			Name:   "store.bar",
+			Status: res.DriftStatusError,
+			Error:  "failed to describe acls",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newDriftReport() = %v, want %v", got, want)
+	}
+	if !got.ContainsDrift() {
+		t.Errorf("res.DriftReport.ContainsDrift() = %v, want %v", false, true)
+	}
+
+	wantDetails := []string{"partitions: remote 3, local 6"}
+	if details := got[0].Details(); !reflect.DeepEqual(details, wantDetails) {
+		t.Errorf("res.Drift.Details() = %v, want %v", details, wantDetails)
+	}
+}
@@ -0,0 +1,46 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+// savePlan saves the plan of definitions and their apply results to the plan output file.
+func (a *applyController) savePlan(ctx context.Context, defs []kdef.Definition, results res.ApplyResults) error {
+	plan, err := kdef.NewPlan(ctx, a.cl, defs, results)
+	if err != nil {
+		return err
+	}
+
+	if err := plan.Save(a.opts.PlanOutput); err != nil {
+		return fmt.Errorf("failed to save plan: %v", err)
+	}
+	log.Infof("Saved plan to file %q", a.opts.PlanOutput)
+
+	return nil
+}
+
+// loadPlan loads the definitions of a plan after verifying it targets the cluster.
+func (a *applyController) loadPlan(ctx context.Context) ([]kdef.Definition, error) {
+	log.Infof("Reading plan from file %q", a.opts.PlanPath)
+	plan, err := res.LoadPlan(a.opts.PlanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %v", err)
+	}
+
+	defs, err := kdef.PlanDefinitions(&plan, a.opts.PlanPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := kdef.VerifyPlanCluster(ctx, a.cl, &plan); err != nil {
+		return nil, err
+	}
+
+	return defs, nil
+}
@@ -0,0 +1,53 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func Test_applyController_loadPlan(t *testing.T) {
+	newPlan := func(defDoc string) string {
+		path := filepath.Join(t.TempDir(), "plan.json")
+		plan := res.Plan{
+			Version:   res.PlanVersion,
+			ClusterID: "foo",
+			Applies: []res.PlannedApply{
+				{Kind: "topicProfile", Name: "compacted", Format: "yaml", Definition: defDoc},
+			},
+		}
+		if err := plan.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		defDoc  string
+		wantErr string
+	}{
+		{
+			name:    "Tests a plan of a kind that cannot be applied",
+			defDoc:  "apiVersion: v1\nkind: topicProfile\nmetadata:\n  name: compacted\n",
+			wantErr: "invalid resource definition in plan: topicProfile definitions cannot be applied",
+		},
+		{
+			name:    "Tests a plan of an invalid kind",
+			defDoc:  "apiVersion: v1\nkind: foo\nmetadata:\n  name: compacted\n",
+			wantErr: "invalid resource definition in plan: invalid definition kind \"foo\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &applyController{opts: ControllerOptions{PlanPath: newPlan(tt.defDoc)}}
+			if _, err := a.loadPlan(context.Background()); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("applyController.loadPlan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
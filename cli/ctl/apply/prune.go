@@ -0,0 +1,185 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/util/str"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+// prune deletes undeclared topics and ACL resources matching the prune regular expression.
+// Returns the definitions of the pruned resources and their apply results.
+func (a *applyController) prune(ctx context.Context, defs []kdef.Definition) ([]kdef.Definition, res.ApplyResults, error) {
+	pruneRegExp, err := regexp.Compile(a.opts.PruneMatch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resourceDefs := make([]def.ResourceDefinition, len(defs))
+	for i, d := range defs {
+		resourceDefs[i] = d.Resource
+	}
+
+	srv := kafka.NewService(a.cl)
+
+	log.Infof("Fetching remote topics and acls for pruning...")
+	metadata, err := srv.DescribeMetadata(ctx, nil, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	resourceACLs, err := srv.DescribeAllResourceACLs(ctx, "any")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pruneDefs []kdef.Definition
+	for _, name := range undeclaredTopics(metadata.Topics, resourceDefs, pruneRegExp) {
+		d, err := newPruneTopicDef(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		pruneDefs = append(pruneDefs, d)
+	}
+	for _, metadata := range undeclaredACLResources(resourceACLs, resourceDefs, pruneRegExp) {
+		d, err := newPruneACLDef(metadata)
+		if err != nil {
+			return nil, nil, err
+		}
+		pruneDefs = append(pruneDefs, d)
+	}
+
+	if len(pruneDefs) == 0 {
+		log.Infof("No undeclared resources to prune")
+		return nil, nil, nil
+	}
+
+	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Pruning %d undeclared resource(s)", len(pruneDefs))
+
+	// The generated definitions of pruned resources are not checked against the policy.
+	results, err := a.applyDefinitions(ctx, pruneDefs, nil)
+	return pruneDefs, results, err
+}
+
+// systemTopics are the names of known system topics that are never pruned,
+// in addition to the topics Kafka reports as internal.
+var systemTopics = []string{
+	"__consumer_offsets",
+	"__transaction_state",
+	"__cluster_metadata",
+	"__remote_log_metadata",
+	"_schemas",
+}
+
+// undeclaredTopics returns the names of undeclared topics matching the prune regular expression.
+// Internal and known system topics are excluded.
+func undeclaredTopics(
+	topics []kafka.TopicMetadata,
+	resourceDefs []def.ResourceDefinition,
+	pruneRegExp *regexp.Regexp,
+) []string {
+	declared := map[string]bool{}
+	for _, resourceDef := range resourceDefs {
+		if resourceDef.Kind == def.KindTopic {
+			declared[resourceDef.Metadata.Name] = true
+		}
+	}
+
+	var names []string
+	for _, t := range topics {
+		if t.Internal || str.Contains(t.Topic, systemTopics) {
+			continue
+		}
+		if declared[t.Topic] || !pruneRegExp.MatchString(t.Topic) {
+			continue
+		}
+		names = append(names, t.Topic)
+	}
+
+	return names
+}
+
+// undeclaredACLResources returns the metadata of undeclared ACL resources matching the prune regular expression.
+func undeclaredACLResources(
+	resourceACLs []kafka.ResourceACLs,
+	resourceDefs []def.ResourceDefinition,
+	pruneRegExp *regexp.Regexp,
+) []def.ResourceMetadataDefinition {
+	aclKey := func(name string, resourceType string, resourcePatternType string) string {
+		return fmt.Sprintf("%s:%s:%s", resourceType, resourcePatternType, name)
+	}
+
+	declared := map[string]bool{}
+	for _, resourceDef := range resourceDefs {
+		if resourceDef.Kind == def.KindACL {
+			declared[aclKey(
+				resourceDef.Metadata.Name,
+				resourceDef.Metadata.Type,
+				resourceDef.Metadata.ResourcePatternType,
+			)] = true
+		}
+	}
+
+	var metadata []def.ResourceMetadataDefinition
+	for _, r := range resourceACLs {
+		if declared[aclKey(r.ResourceName, r.ResourceType, r.ResourcePatternType)] ||
+			!pruneRegExp.MatchString(r.ResourceName) {
+			continue
+		}
+		metadata = append(metadata, def.ResourceMetadataDefinition{
+			Name:                r.ResourceName,
+			Type:                r.ResourceType,
+			ResourcePatternType: r.ResourcePatternType,
+		})
+	}
+
+	return metadata
+}
+
+// newPruneTopicDef creates a definition declaring that a topic should not exist.
+func newPruneTopicDef(name string) (kdef.Definition, error) {
+	topicDef := def.TopicDefinition{
+		ResourceDefinition: def.ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       def.KindTopic,
+			Metadata: def.ResourceMetadataDefinition{
+				Name: name,
+			},
+		},
+		Spec: def.TopicSpecDefinition{
+			State: def.StateAbsent,
+		},
+	}
+
+	return newPruneDef(topicDef.ResourceDefinition, topicDef)
+}
+
+// newPruneACLDef creates a definition declaring that an ACL resource should have no ACLs.
+func newPruneACLDef(metadata def.ResourceMetadataDefinition) (kdef.Definition, error) {
+	aclDef := def.NewACLDefinition(metadata, nil)
+	aclDef.Spec.DeleteUndefinedACLs = true
+
+	return newPruneDef(aclDef.ResourceDefinition, aclDef)
+}
+
+// newPruneDef creates a definition with a JSON definition document.
+func newPruneDef(resourceDef def.ResourceDefinition, d interface{}) (kdef.Definition, error) {
+	j, err := json.Marshal(d)
+	if err != nil {
+		return kdef.Definition{}, fmt.Errorf("failed to create prune definition: %v", err)
+	}
+
+	return kdef.Definition{
+		Document: string(j),
+		Resource: resourceDef,
+		Format:   opt.JSONFormat,
+	}, nil
+}
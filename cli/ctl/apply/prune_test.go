@@ -0,0 +1,194 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+func Test_undeclaredTopics(t *testing.T) {
+	resourceDefs := []def.ResourceDefinition{
+		{
+			APIVersion: "v1",
+			Kind:       def.KindTopic,
+			Metadata: def.ResourceMetadataDefinition{
+				Name: "store.foo",
+			},
+		},
+		{
+			APIVersion: "v1",
+			Kind:       def.KindACL,
+			Metadata: def.ResourceMetadataDefinition{
+				Name:                "store.bar",
+				Type:                "topic",
+				ResourcePatternType: "literal",
+			},
+		},
+	}
+
+	type args struct {
+		topics       []kafka.TopicMetadata
+		resourceDefs []def.ResourceDefinition
+		pruneMatch   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "Tests undeclared topics matching the regular expression are returned",
+			args: args{
+				topics: []kafka.TopicMetadata{
+					{Topic: "store.foo", Exists: true},
+					{Topic: "store.bar", Exists: true},
+					{Topic: "store.baz", Exists: true},
+					{Topic: "other.qux", Exists: true},
+				},
+				resourceDefs: resourceDefs,
+				pruneMatch:   `^store\.`,
+			},
+			want: []string{"store.bar", "store.baz"},
+		},
+		{
+			name: "Tests internal topics are excluded",
+			args: args{
+				topics: []kafka.TopicMetadata{
+					{Topic: "__consumer_offsets", Exists: true, Internal: true},
+					{Topic: "_schemas", Exists: true},
+					{Topic: "store.baz", Exists: true},
+				},
+				resourceDefs: resourceDefs,
+				pruneMatch:   ".*",
+			},
+			want: []string{"store.baz"},
+		},
+		{
+			name: "Tests user topics prefixed with an underscore are not excluded",
+			args: args{
+				topics: []kafka.TopicMetadata{
+					{Topic: "__transaction_state", Exists: true},
+					{Topic: "_store.qux", Exists: true},
+				},
+				resourceDefs: resourceDefs,
+				pruneMatch:   ".*",
+			},
+			want: []string{"_store.qux"},
+		},
+		{
+			name: "Tests no undeclared topics",
+			args: args{
+				topics: []kafka.TopicMetadata{
+					{Topic: "store.foo", Exists: true},
+				},
+				resourceDefs: resourceDefs,
+				pruneMatch:   ".*",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := undeclaredTopics(tt.args.topics, tt.args.resourceDefs, regexp.MustCompile(tt.args.pruneMatch))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("undeclaredTopics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_undeclaredACLResources(t *testing.T) {
+	resourceDefs := []def.ResourceDefinition{
+		{
+			APIVersion: "v1",
+			Kind:       def.KindACL,
+			Metadata: def.ResourceMetadataDefinition{
+				Name:                "store.foo",
+				Type:                "topic",
+				ResourcePatternType: "literal",
+			},
+		},
+		{
+			APIVersion: "v1",
+			Kind:       def.KindTopic,
+			Metadata: def.ResourceMetadataDefinition{
+				Name: "store.bar",
+			},
+		},
+	}
+
+	type args struct {
+		resourceACLs []kafka.ResourceACLs
+		resourceDefs []def.ResourceDefinition
+		pruneMatch   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []def.ResourceMetadataDefinition
+	}{
+		{
+			name: "Tests undeclared acl resources matching the regular expression are returned",
+			args: args{
+				resourceACLs: []kafka.ResourceACLs{
+					{ResourceName: "store.foo", ResourceType: "topic", ResourcePatternType: "literal"},
+					{ResourceName: "store.foo", ResourceType: "topic", ResourcePatternType: "prefixed"},
+					{ResourceName: "store.foo", ResourceType: "group", ResourcePatternType: "literal"},
+					{ResourceName: "store.bar", ResourceType: "topic", ResourcePatternType: "literal"},
+					{ResourceName: "other.qux", ResourceType: "topic", ResourcePatternType: "literal"},
+				},
+				resourceDefs: resourceDefs,
+				pruneMatch:   `^store\.`,
+			},
+			want: []def.ResourceMetadataDefinition{
+				{Name: "store.foo", Type: "topic", ResourcePatternType: "prefixed"},
+				{Name: "store.foo", Type: "group", ResourcePatternType: "literal"},
+				{Name: "store.bar", Type: "topic", ResourcePatternType: "literal"},
+			},
+		},
+		{
+			name: "Tests no undeclared acl resources",
+			args: args{
+				resourceACLs: []kafka.ResourceACLs{
+					{ResourceName: "store.foo", ResourceType: "topic", ResourcePatternType: "literal"},
+				},
+				resourceDefs: resourceDefs,
+				pruneMatch:   ".*",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := undeclaredACLResources(tt.args.resourceACLs, tt.args.resourceDefs, regexp.MustCompile(tt.args.pruneMatch))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("undeclaredACLResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newPruneTopicDef(t *testing.T) {
+	got, err := newPruneTopicDef("store.foo")
+	if err != nil {
+		t.Errorf("newPruneTopicDef() error = %v", err)
+		return
+	}
+
+	topicDef, err := def.LoadTopicDefinition(got.Document, opt.JSONFormat, nil, nil)
+	if err != nil {
+		t.Errorf("def.LoadTopicDefinition() error = %v", err)
+		return
+	}
+	if err := topicDef.Validate(); err != nil {
+		t.Errorf("topicDef.Validate() error = %v", err)
+	}
+	if !topicDef.Spec.IsAbsent() {
+		t.Errorf("topicDef.Spec.State = %v, want %v", topicDef.Spec.State, def.StateAbsent)
+	}
+}
@@ -0,0 +1,73 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/hooks"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// DefinitionResult represents the apply result of a resource definition.
+type DefinitionResult struct {
+	Kind   string
+	Type   string
+	Name   string
+	Result *res.ApplyResult
+}
+
+// Reconcile applies definitions and returns the result of each definition applied.
+// Definitions are loaded by the first reconcile and reused until reload is true,
+// or until they are loaded without errors.
+func (a *applyController) Reconcile(ctx context.Context, reload bool) ([]DefinitionResult, error) {
+	if err := hooks.Validate(a.cl.Hooks()); err != nil {
+		return nil, err
+	}
+
+	if reload || !a.loaded {
+		// The policy, vars file and overlay are reloaded with the definitions in case they changed.
+		a.loaded = false
+		if err := a.loadPolicy(); err != nil {
+			return nil, err
+		}
+		if err := a.loadRenderOptions(); err != nil {
+			return nil, err
+		}
+		var loadErrors bool
+		a.loadedDefs, loadErrors = a.loadDefinitions()
+		a.loaded = !loadErrors
+	}
+
+	// Definitions are checked against the policy, and the cluster is verified, on every reconcile.
+	// The bootstrap servers may resolve to another cluster.
+	results, err := a.applyDefinitions(ctx, a.loadedDefs, a.policy)
+	if err != nil {
+		return nil, err
+	}
+
+	defResults := make([]DefinitionResult, len(results))
+	for i, result := range results {
+		resourceDef := a.loadedDefs[i].Resource
+		defResults[i] = DefinitionResult{
+			Kind:   resourceDef.Kind,
+			Type:   resourceDef.Metadata.Type,
+			Name:   resourceDef.Metadata.Name,
+			Result: result,
+		}
+	}
+
+	if !a.loaded {
+		return defResults, fmt.Errorf("failed to load definitions")
+	}
+
+	if len(results) == 0 {
+		return defResults, fmt.Errorf("no valid resource definitions found")
+	}
+
+	if results.ContainsErr() {
+		return defResults, fmt.Errorf("reconcile completed with errors")
+	}
+
+	return defResults, nil
+}
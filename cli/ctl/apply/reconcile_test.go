@@ -0,0 +1,79 @@
+// Package apply implements the apply controller.
+package apply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func Test_applyController_Reconcile_policy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	topics := write("topics.yml", `apiVersion: v1
+kind: topic
+metadata:
+  name: store.orders
+spec:
+  partitions: 3
+  replicationFactor: 1
+`)
+	policyFile := write("policy.yml", `rules:
+  - name: replication-factor
+    kinds: [topic]
+    assert:
+      - path: spec.replicationFactor
+        gte: 2
+`)
+
+	tests := []struct {
+		name       string
+		policyFile string
+		wantErr    string
+	}{
+		{
+			name:       "Tests definitions violating the policy",
+			policyFile: policyFile,
+			wantErr:    "policy violations",
+		},
+		{
+			name:       "Tests a policy file that cannot be read",
+			policyFile: filepath.Join(dir, "does-not-exist.yml"),
+			wantErr:    "failed to read policy file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := tutil.CreateClient(t, []string{
+				"seedBrokers=localhost:9092",
+				"policyFile=" + tt.policyFile,
+			})
+			a := NewApplyController(cl, []string{topics}, ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				DryRun:           true,
+				Parallelism:      1,
+			})
+			// Definitions are checked on every reconcile, including reconciles reusing loaded definitions.
+			for _, reload := range []bool{true, false} {
+				results, err := a.Reconcile(context.Background(), reload)
+				if !tutil.ErrorContains(err, tt.wantErr) {
+					t.Errorf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if len(results) > 0 {
+					t.Errorf("Reconcile() applied %d definition(s), want 0", len(results))
+				}
+			}
+		})
+	}
+}
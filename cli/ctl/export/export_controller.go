@@ -12,28 +12,21 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
-	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/model/res"
-	"github.com/peter-evans/kdef/core/operators/acl"
-	"github.com/peter-evans/kdef/core/operators/broker"
-	"github.com/peter-evans/kdef/core/operators/brokers"
-	"github.com/peter-evans/kdef/core/operators/topic"
+	"github.com/peter-evans/kdef/kdef"
 )
 
-type exporter interface {
-	Execute(ctx context.Context) (res.ExportResults, error)
-}
-
 // ControllerOptions represents options to configure an export controller.
 type ControllerOptions struct {
-	// ExporterOptions for topic/acl definitions.
+	// ExporterOptions for topic/acl/consumer group definitions.
 	Match   string
 	Exclude string
 
 	// ExporterOptions for topic definitions.
 	TopicIncludeInternal bool
 	TopicAssignments     opt.Assignments
+	TopicInferProfiles   bool
 
 	// ExporterOptions for acl definitions.
 	ACLResourceType string
@@ -66,7 +59,7 @@ type exportController struct {
 
 // Execute implements the execution of the export controller.
 func (e *exportController) Execute(ctx context.Context) error {
-	results, err := e.exportResources(ctx)
+	results, err := e.Export(ctx)
 	if err != nil {
 		return err
 	}
@@ -125,35 +118,17 @@ func (e *exportController) Execute(ctx context.Context) error {
 	return nil
 }
 
-func (e *exportController) exportResources(ctx context.Context) (res.ExportResults, error) {
-	var exporter exporter
-	switch e.kind {
-	case def.KindACL:
-		exporter = acl.NewExporter(e.cl, acl.ExporterOptions{
-			Match:        e.opts.Match,
-			Exclude:      e.opts.Exclude,
-			ResourceType: e.opts.ACLResourceType,
-			AutoGroup:    e.opts.ACLAutoGroup,
-		})
-	case def.KindBroker:
-		exporter = broker.NewExporter(e.cl)
-	case def.KindBrokers:
-		exporter = brokers.NewExporter(e.cl)
-	case def.KindTopic:
-		exporter = topic.NewExporter(e.cl, topic.ExporterOptions{
-			Match:           e.opts.Match,
-			Exclude:         e.opts.Exclude,
-			IncludeInternal: e.opts.TopicIncludeInternal,
-			Assignments:     e.opts.TopicAssignments,
-		})
-	}
-
-	results, err := exporter.Execute(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	return results, nil
+// Export exports resources and returns the results.
+func (e *exportController) Export(ctx context.Context) (res.ExportResults, error) {
+	return kdef.Export(ctx, e.cl, e.kind, kdef.ExporterOptions{
+		Match:                e.opts.Match,
+		Exclude:              e.opts.Exclude,
+		TopicIncludeInternal: e.opts.TopicIncludeInternal,
+		TopicAssignments:     e.opts.TopicAssignments,
+		TopicInferProfiles:   e.opts.TopicInferProfiles,
+		ACLResourceType:      e.opts.ACLResourceType,
+		ACLAutoGroup:         e.opts.ACLAutoGroup,
+	})
 }
 
 func getDefDocBytes(def interface{}, format opt.DefinitionFormat) ([]byte, error) {
@@ -0,0 +1,138 @@
+// Package reconcile implements the reconcile controller.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/ctl/apply"
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/client"
+)
+
+// ControllerOptions represents options to configure a reconcile controller.
+type ControllerOptions struct {
+	// Apply controller options.
+	Apply apply.ControllerOptions
+
+	// Reconcile controller specific options.
+	Interval      time.Duration
+	MaxBackoff    time.Duration
+	StatusAddress string
+}
+
+// NewReconcileController creates a new reconcile controller.
+func NewReconcileController(
+	cl *client.Client,
+	args []string,
+	opts ControllerOptions,
+) *reconcileController { //revive:disable-line:unexported-return
+	return &reconcileController{
+		cl:     cl,
+		args:   args,
+		opts:   opts,
+		status: newStatusTracker(),
+	}
+}
+
+type reconcileController struct {
+	cl     *client.Client
+	args   []string
+	opts   ControllerOptions
+	status *statusTracker
+}
+
+// Execute implements the execution of the reconcile controller.
+// Definitions are reconciled until the context is cancelled.
+func (r *reconcileController) Execute(ctx context.Context) error {
+	w, err := newWatcher(r.args)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if len(r.opts.StatusAddress) > 0 {
+		srv, err := r.serveStatus()
+		if err != nil {
+			return err
+		}
+		defer srv.Close()
+	}
+
+	// A single apply controller reuses the client and loaded definitions between reconciles.
+	applyCtl := apply.NewApplyController(r.cl, r.args, r.opts.Apply)
+
+	reload := false
+	failures := 0
+	for {
+		log.Infof("Reconciling definitions...")
+		results, err := applyCtl.Reconcile(ctx, reload)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			log.Error(err)
+			failures++
+		} else {
+			failures = 0
+		}
+
+		delay := nextDelay(r.opts.Interval, r.opts.MaxBackoff, failures)
+		now := time.Now()
+		r.status.update(results, err, now, now.Add(delay), failures)
+		log.Infof("Next reconcile in %s", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			reload = false
+		case <-w.Changes():
+			timer.Stop()
+			log.Infof("Definitions changed")
+			reload = true
+		}
+	}
+}
+
+// serveStatus starts serving the status endpoint.
+func (r *reconcileController) serveStatus() (*http.Server, error) {
+	ln, err := net.Listen("tcp", r.opts.StatusAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/status", r.status)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Infof("Serving status on %q", ln.Addr().String())
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(err)
+		}
+	}()
+
+	return srv, nil
+}
+
+// nextDelay returns the delay before the next reconcile.
+// The interval is doubled for each consecutive failure, up to the maximum backoff.
+func nextDelay(interval time.Duration, maxBackoff time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if failures > 0 && delay > maxBackoff && maxBackoff > interval {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,48 @@
+// Package reconcile implements the reconcile controller.
+package reconcile
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_nextDelay(t *testing.T) {
+	type args struct {
+		interval   time.Duration
+		maxBackoff time.Duration
+		failures   int
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{
+			name: "Tests no failures",
+			args: args{interval: time.Minute, maxBackoff: 15 * time.Minute, failures: 0},
+			want: time.Minute,
+		},
+		{
+			name: "Tests consecutive failures",
+			args: args{interval: time.Minute, maxBackoff: 15 * time.Minute, failures: 3},
+			want: 8 * time.Minute,
+		},
+		{
+			name: "Tests backoff capped at the maximum",
+			args: args{interval: time.Minute, maxBackoff: 15 * time.Minute, failures: 10},
+			want: 15 * time.Minute,
+		},
+		{
+			name: "Tests maximum backoff equal to the interval",
+			args: args{interval: time.Minute, maxBackoff: time.Minute, failures: 2},
+			want: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextDelay(tt.args.interval, tt.args.maxBackoff, tt.args.failures); got != tt.want {
+				t.Errorf("nextDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
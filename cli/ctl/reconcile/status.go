@@ -0,0 +1,116 @@
+// Package reconcile implements the reconcile controller.
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/ctl/apply"
+)
+
+// ResourceStatus represents the status of a resource from its last apply.
+type ResourceStatus struct {
+	Kind         string    `json:"kind"`
+	Type         string    `json:"type,omitempty"`
+	Name         string    `json:"name"`
+	Applied      bool      `json:"applied"`
+	Diff         string    `json:"diff"`
+	Err          string    `json:"error,omitempty"`
+	ReconciledAt time.Time `json:"reconciledAt"`
+}
+
+// Status represents the status of the reconcile loop.
+type Status struct {
+	LastReconcile       *time.Time       `json:"lastReconcile,omitempty"`
+	LastSuccess         *time.Time       `json:"lastSuccess,omitempty"`
+	NextReconcile       *time.Time       `json:"nextReconcile,omitempty"`
+	ConsecutiveFailures int              `json:"consecutiveFailures"`
+	Err                 string           `json:"error,omitempty"`
+	Resources           []ResourceStatus `json:"resources"`
+}
+
+// statusTracker tracks the status of the reconcile loop.
+type statusTracker struct {
+	mu        sync.Mutex
+	status    Status
+	resources map[string]ResourceStatus
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{
+		resources: map[string]ResourceStatus{},
+	}
+}
+
+// update updates the status from the results of a reconcile.
+// Resources not reached because of an error keep the status of their last apply.
+func (s *statusTracker) update(results []apply.DefinitionResult, err error, at time.Time, next time.Time, failures int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		// All definitions were applied, so resources no longer defined are removed.
+		s.resources = map[string]ResourceStatus{}
+	}
+	for _, r := range results {
+		s.resources[fmt.Sprintf("%s/%s/%s", r.Kind, r.Type, r.Name)] = ResourceStatus{
+			Kind:         r.Kind,
+			Type:         r.Type,
+			Name:         r.Name,
+			Applied:      r.Result.Applied,
+			Diff:         r.Result.Diff,
+			Err:          r.Result.Err,
+			ReconciledAt: at,
+		}
+	}
+
+	s.status.LastReconcile = &at
+	s.status.NextReconcile = &next
+	s.status.ConsecutiveFailures = failures
+	if err != nil {
+		s.status.Err = err.Error()
+	} else {
+		s.status.Err = ""
+		s.status.LastSuccess = &at
+	}
+}
+
+// snapshot returns a copy of the status with resources sorted by kind and name.
+func (s *statusTracker) snapshot() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Resources = make([]ResourceStatus, 0, len(s.resources))
+	for _, r := range s.resources {
+		status.Resources = append(status.Resources, r)
+	}
+	sort.Slice(status.Resources, func(i, j int) bool {
+		a, b := status.Resources[i], status.Resources[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+	return status
+}
+
+// ServeHTTP serves the status as JSON.
+func (s *statusTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
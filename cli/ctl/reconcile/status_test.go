@@ -0,0 +1,68 @@
+// Package reconcile implements the reconcile controller.
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/ctl/apply"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+func Test_statusTracker(t *testing.T) {
+	s := newStatusTracker()
+	first := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+
+	s.update([]apply.DefinitionResult{
+		{Kind: def.KindTopic, Name: "store.foo", Result: &res.ApplyResult{Diff: "+ partitions", Applied: true}},
+		{Kind: def.KindTopic, Name: "store.bar", Result: &res.ApplyResult{}},
+	}, nil, first, second, 0)
+
+	// An error stopped the reconcile before reaching the second definition.
+	s.update([]apply.DefinitionResult{
+		{Kind: def.KindTopic, Name: "store.foo", Result: &res.ApplyResult{Err: "foo"}},
+	}, fmt.Errorf("reconcile completed with errors"), second, second.Add(2*time.Minute), 1)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("statusTracker.ServeHTTP() code = %v, want %v", rec.Code, http.StatusOK)
+		return
+	}
+
+	var got Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Errorf("json.Unmarshal() error = %v", err)
+		return
+	}
+
+	if got.ConsecutiveFailures != 1 ||
+		got.Err != "reconcile completed with errors" ||
+		!got.LastSuccess.Equal(first) ||
+		!got.LastReconcile.Equal(second) {
+		t.Errorf("statusTracker.ServeHTTP() = %+v", got)
+	}
+
+	wantResources := []ResourceStatus{
+		{Kind: def.KindTopic, Name: "store.bar", ReconciledAt: first},
+		{Kind: def.KindTopic, Name: "store.foo", Err: "foo", ReconciledAt: second},
+	}
+	if !reflect.DeepEqual(got.Resources, wantResources) {
+		t.Errorf("statusTracker.ServeHTTP() resources = %+v, want %+v", got.Resources, wantResources)
+	}
+
+	// A successful reconcile removes resources that are no longer defined.
+	s.update([]apply.DefinitionResult{
+		{Kind: def.KindTopic, Name: "store.foo", Result: &res.ApplyResult{}},
+	}, nil, second, second, 0)
+	if resources := s.snapshot().Resources; len(resources) != 1 || resources[0].Name != "store.foo" {
+		t.Errorf("statusTracker.snapshot() resources = %+v, want %v", resources, "store.foo")
+	}
+}
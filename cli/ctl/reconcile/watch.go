@@ -0,0 +1,156 @@
+// Package reconcile implements the reconcile controller.
+package reconcile
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/peter-evans/kdef/cli/log"
+)
+
+// watchDebounce is the quiet period after a change before definitions are reloaded.
+// Editors and version control typically write several files in quick succession.
+const watchDebounce = 500 * time.Millisecond
+
+// watchPattern represents a glob pattern split into its base path and pattern.
+type watchPattern struct {
+	basepath string
+	pattern  string
+}
+
+// watcher watches the directories of glob patterns for changes to matching definitions.
+type watcher struct {
+	fsw      *fsnotify.Watcher
+	patterns []watchPattern
+	changes  chan struct{}
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// newWatcher creates a watcher for the directories of glob patterns.
+func newWatcher(args []string) (*watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		fsw:     fsw,
+		changes: make(chan struct{}, 1),
+	}
+	for _, arg := range args {
+		basepath, pattern := doublestar.SplitPattern(arg)
+		w.patterns = append(w.patterns, watchPattern{basepath: basepath, pattern: pattern})
+		if err := w.addDirs(basepath); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// Changes returns a channel that receives when matching definitions have changed.
+func (w *watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching for changes.
+func (w *watcher) Close() error {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	return w.fsw.Close()
+}
+
+// addDirs watches a directory and its subdirectories, because watches are not recursive.
+func (w *watcher) addDirs(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		log.Debugf("Watching directory %q for changes", path)
+		return w.fsw.Add(path)
+	})
+}
+
+func (w *watcher) run() {
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Warnf("Watching definitions failed: %v", err)
+		}
+	}
+}
+
+func (w *watcher) handle(event fsnotify.Event) {
+	if event.Op == fsnotify.Chmod {
+		return
+	}
+
+	changed := w.matches(event.Name)
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			// Definitions may have been moved into the directory with it.
+			if err := w.addDirs(event.Name); err != nil {
+				log.Warnf("Watching directory %q failed: %v", event.Name, err)
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	log.Debugf("Definitions changed: %s", event)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(watchDebounce, w.notify)
+}
+
+// notify notifies of changes without blocking, coalescing changes not yet received.
+func (w *watcher) notify() {
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
+
+// matches determines if a path matches any of the glob patterns.
+func (w *watcher) matches(path string) bool {
+	for _, p := range w.patterns {
+		rel, err := filepath.Rel(p.basepath, path)
+		if err != nil {
+			continue
+		}
+		if ok, _ := doublestar.PathMatch(p.pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
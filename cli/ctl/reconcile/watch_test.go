@@ -0,0 +1,50 @@
+// Package reconcile implements the reconcile controller.
+package reconcile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_watcher(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "topics"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newWatcher([]string{filepath.Join(dir, "**/*.yml")})
+	if err != nil {
+		t.Errorf("newWatcher() error = %v", err)
+		return
+	}
+	defer w.Close()
+
+	if !w.matches(filepath.Join(dir, "topics", "foo.yml")) {
+		t.Errorf("watcher.matches() = %v, want %v", false, true)
+	}
+	if w.matches(filepath.Join(dir, "topics", "foo.json")) {
+		t.Errorf("watcher.matches() = %v, want %v", true, false)
+	}
+
+	// Files not matching the patterns are ignored.
+	if err := os.WriteFile(filepath.Join(dir, "topics", "README.md"), []byte("foo"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-w.Changes():
+		t.Errorf("watcher.Changes() received for a file not matching the patterns")
+		return
+	case <-time.After(2 * watchDebounce):
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "topics", "foo.yml"), []byte("foo"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-w.Changes():
+	case <-time.After(5 * time.Second):
+		t.Errorf("watcher.Changes() not received for a changed definition")
+	}
+}
@@ -0,0 +1,233 @@
+// Package render implements the render controller.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/helpers/docparse"
+	"github.com/peter-evans/kdef/core/helpers/overlay"
+	"github.com/peter-evans/kdef/core/helpers/render"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+// ControllerOptions represents options to configure a render controller.
+type ControllerOptions struct {
+	DefinitionFormat   opt.DefinitionFormat
+	LenientAPIVersions []string
+	VarsFile           string
+	Substitute         bool
+	Template           bool
+	Overlay            string
+}
+
+// NewRenderController creates a new render controller.
+func NewRenderController(
+	args []string,
+	opts ControllerOptions,
+) *renderController { //revive:disable-line:unexported-return
+	return &renderController{
+		args: args,
+		opts: opts,
+	}
+}
+
+type renderController struct {
+	args []string
+	opts ControllerOptions
+
+	// Internal fields.
+	render  render.Options
+	overlay *overlay.Overlay
+}
+
+// renderedDoc represents a rendered definition document and its position.
+type renderedDoc struct {
+	content string
+	source  def.Source
+}
+
+// Execute implements the execution of the render controller.
+func (r *renderController) Execute() error {
+	out, err := r.renderOutput()
+	if err != nil {
+		return err
+	}
+	// Ignores --quiet.
+	fmt.Print(out)
+	return nil
+}
+
+// renderOutput renders the definitions of stdin or files and returns the output.
+func (r *renderController) renderOutput() (string, error) {
+	r.render.Template = r.opts.Template
+	// Supplying variables enables substitution.
+	r.render.Substitute = r.opts.Substitute || len(r.opts.VarsFile) > 0
+	if len(r.opts.VarsFile) > 0 {
+		var err error
+		if r.render.Vars, err = render.LoadVars(r.opts.VarsFile); err != nil {
+			return "", err
+		}
+	}
+
+	if len(r.opts.Overlay) > 0 {
+		var err error
+		if r.overlay, err = overlay.Load(r.opts.Overlay); err != nil {
+			return "", err
+		}
+	}
+
+	docs, err := r.renderDocuments()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range r.overlay.Unmatched() {
+		log.Warnf("Overlay %s did not match any definitions", p)
+	}
+	if docs, err = r.resolveProfiles(docs); err != nil {
+		return "", err
+	}
+	if len(docs) == 0 {
+		return "", fmt.Errorf("no resource definitions found")
+	}
+
+	if r.opts.DefinitionFormat == opt.JSONFormat {
+		contents := make([]json.RawMessage, len(docs))
+		for i, doc := range docs {
+			contents[i] = json.RawMessage(doc.content)
+		}
+		j, err := json.MarshalIndent(contents, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(j) + "\n", nil
+	}
+
+	var sb strings.Builder
+	for _, doc := range docs {
+		fmt.Fprintf(&sb, "---\n# Source: %s\n%s\n", doc.source, doc.content)
+	}
+	return sb.String(), nil
+}
+
+// resolveProfiles resolves the topic profiles referenced by topic definitions.
+// Topic profile definitions are not output because they are not applied.
+func (r *renderController) resolveProfiles(docs []renderedDoc) ([]renderedDoc, error) {
+	defs := make([]kdef.Definition, len(docs))
+	for i, doc := range docs {
+		defs[i] = kdef.Definition{
+			Document:           doc.content,
+			Format:             r.opts.DefinitionFormat,
+			LenientAPIVersions: r.opts.LenientAPIVersions,
+			Source:             &docs[i].source,
+		}
+		// Documents that are not valid resource definitions are output unchanged.
+		resourceDefs, err := kdef.ResourceDefinitions([]string{doc.content}, r.opts.DefinitionFormat, r.opts.LenientAPIVersions)
+		if err == nil {
+			defs[i].Resource = resourceDefs[0]
+		}
+	}
+
+	profiles, err := kdef.TopicProfiles(defs)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []renderedDoc
+	for i, doc := range docs {
+		if defs[i].Resource.Kind == def.KindTopicProfile {
+			continue
+		}
+		d, err := kdef.ResolveTopicProfiles(defs[i], profiles)
+		if err != nil {
+			return nil, doc.source.Errorf("%v", err)
+		}
+		doc.content = d.Document
+		resolved = append(resolved, doc)
+	}
+	return resolved, nil
+}
+
+// renderDocuments renders the definition documents of stdin or files.
+func (r *renderController) renderDocuments() ([]renderedDoc, error) {
+	if r.args[0] == "-" {
+		log.Infof("Reading definition(s) from stdin")
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read definition(s): %v", err)
+		}
+		return r.renderBytes(b, "")
+	}
+
+	var docs []renderedDoc
+	for _, arg := range r.args {
+		basepath, pattern := doublestar.SplitPattern(arg)
+		fsys := os.DirFS(basepath)
+
+		err := doublestar.GlobWalk(fsys, pattern, func(p string, d fs.DirEntry) error {
+			if d.IsDir() {
+				return nil
+			}
+
+			path := filepath.Join(basepath, p)
+			log.Infof("Reading definition(s) from file %q", path)
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read definition(s): %v", err)
+			}
+			fileDocs, err := r.renderBytes(b, path)
+			if err != nil {
+				return err
+			}
+			docs = append(docs, fileDocs...)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+// renderBytes renders the definition documents of stdin or a file.
+func (r *renderController) renderBytes(b []byte, file string) ([]renderedDoc, error) {
+	b, err := render.Render(b, r.render)
+	if err != nil {
+		if len(file) > 0 {
+			return nil, fmt.Errorf("failed to render definition(s): %s: %v", file, err)
+		}
+		return nil, fmt.Errorf("failed to render definition(s): %v", err)
+	}
+	defDocs, err := docparse.FromBytes(b, docparse.Format(r.opts.DefinitionFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition(s): %v", err)
+	}
+
+	docs := make([]renderedDoc, len(defDocs))
+	for i, defDoc := range defDocs {
+		source := def.Source{
+			File: file,
+			Doc:  defDoc.Index + 1,
+			Line: defDoc.Line,
+		}
+		if defDoc, err = r.overlay.Apply(defDoc, docparse.Format(r.opts.DefinitionFormat)); err != nil {
+			return nil, source.Errorf("%v", err)
+		}
+		docs[i] = renderedDoc{
+			content: defDoc.Content,
+			source:  source,
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,246 @@
+// Package render implements the render controller.
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func Test_renderController_renderOutput(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	topics := write("topics.yml", `# Topics
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: ${PARTITIONS}
+  replicationFactor: ${REPLICATION_FACTOR:-3}
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: bar
+spec:
+  partitions: {{ .PARTITIONS }}
+  replicationFactor: 3
+  configs:
+    retention.ms: "{{ duration "7d" }}"
+`)
+	jsonTopics := write("topics.json", `[
+  {"apiVersion": "v1", "kind": "topic", "metadata": {"name": "${NAME:-foo}"}}
+]`)
+	literal := write("literal.yml", `# Topics
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 3
+  replicationFactor: 3
+  configs:
+    # Literal ${NOT_SET} references are not substituted.
+    consumer.regex: "^${prefix}\\..*"
+`)
+	vars := write("vars.yml", "PARTITIONS: 6\n")
+	profiles := write("profiles.yml", `apiVersion: v1
+kind: topicProfile
+metadata:
+  name: compacted
+spec:
+  configs:
+    cleanup.policy: compact
+  replicationFactor: 3
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: baz
+spec:
+  profiles: [compacted]
+  partitions: 1
+`)
+	prod := write("prod.yml", `patches:
+  - target:
+      kind: topic
+      name: bar
+    merge:
+      spec:
+        replicationFactor: 5
+`)
+	invalidProd := write("invalid-prod.yml", `patches:
+  - target:
+      kind: topic
+    jsonPatch:
+      - op: remove
+        path: /spec/assignments
+`)
+
+	tests := []struct {
+		name    string
+		args    []string
+		opts    ControllerOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Tests rendering YAML definitions",
+			args: []string{topics},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				VarsFile:         vars,
+				Template:         true,
+			},
+			want: `---
+# Source: ` + topics + `:2 (document 1)
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 6
+  replicationFactor: 3
+---
+# Source: ` + topics + `:10 (document 2)
+apiVersion: v1
+kind: topic
+metadata:
+  name: bar
+spec:
+  partitions: 6
+  replicationFactor: 3
+  configs:
+    retention.ms: "604800000"
+`,
+		},
+		{
+			name: "Tests rendering definitions with an overlay",
+			args: []string{topics},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				VarsFile:         vars,
+				Template:         true,
+				Overlay:          prod,
+			},
+			want: `---
+# Source: ` + topics + `:2 (document 1)
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 6
+  replicationFactor: 3
+---
+# Source: ` + topics + `:10 (document 2)
+apiVersion: v1
+kind: topic
+metadata:
+  name: bar
+spec:
+  configs:
+    retention.ms: "604800000"
+  partitions: 6
+  replicationFactor: 5
+`,
+		},
+		{
+			name: "Tests rendering definitions with a failing overlay",
+			args: []string{topics},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				VarsFile:         vars,
+				Template:         true,
+				Overlay:          invalidProd,
+			},
+			wantErr: topics + ":2 (document 1): failed to apply overlay patch 1 of \"" + invalidProd + "\"",
+		},
+		{
+			name: "Tests rendering definitions with profiles",
+			args: []string{profiles},
+			opts: ControllerOptions{DefinitionFormat: opt.YAMLFormat},
+			want: `---
+# Source: ` + profiles + `:10 (document 2)
+apiVersion: v1
+kind: topic
+metadata:
+  name: baz
+spec:
+  configs:
+    cleanup.policy: compact
+  partitions: 1
+  replicationFactor: 3
+`,
+		},
+		{
+			name: "Tests rendering JSON definitions",
+			args: []string{jsonTopics},
+			opts: ControllerOptions{DefinitionFormat: opt.JSONFormat, Substitute: true},
+			want: `[
+  {
+    "apiVersion": "v1",
+    "kind": "topic",
+    "metadata": {
+      "name": "foo"
+    }
+  }
+]
+`,
+		},
+		{
+			name:    "Tests rendering definitions referencing unset variables",
+			args:    []string{topics},
+			opts:    ControllerOptions{DefinitionFormat: opt.YAMLFormat, Substitute: true},
+			wantErr: topics + ": line 7: variable \"PARTITIONS\" is not set",
+		},
+		{
+			name: "Tests rendering definitions containing references without substitution",
+			args: []string{literal},
+			opts: ControllerOptions{DefinitionFormat: opt.YAMLFormat},
+			want: `---
+# Source: ` + literal + `:2 (document 1)
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 3
+  replicationFactor: 3
+  configs:
+    # Literal ${NOT_SET} references are not substituted.
+    consumer.regex: "^${prefix}\\..*"
+`,
+		},
+		{
+			name:    "Tests no definitions",
+			args:    []string{filepath.Join(dir, "*.txt")},
+			opts:    ControllerOptions{DefinitionFormat: opt.YAMLFormat},
+			wantErr: "no resource definitions found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderController(tt.args, tt.opts)
+			got, err := r.renderOutput()
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("renderOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("renderOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
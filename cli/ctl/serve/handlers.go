@@ -0,0 +1,193 @@
+// Package serve implements the serve controller.
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/util/str"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+// maxRequestBytes is the maximum size of a request body.
+const maxRequestBytes = 10 << 20
+
+// exportKinds are the kinds of resources that can be exported.
+var exportKinds = []string{def.KindACL, def.KindBroker, def.KindBrokers, def.KindTopic}
+
+// ApplyRequest represents a request to apply definitions.
+type ApplyRequest struct {
+	Definitions       string   `json:"definitions"`
+	Format            string   `json:"format,omitempty"`
+	DryRun            bool     `json:"dryRun,omitempty"`
+	PropertyOverrides []string `json:"propertyOverrides,omitempty"`
+	ContinueOnError   bool     `json:"continueOnError,omitempty"`
+	ReassAwaitTimeout int      `json:"reassAwaitTimeout,omitempty"`
+}
+
+type errorResponse struct {
+	Err string `json:"error"`
+}
+
+func (s *serveController) handleApply(w http.ResponseWriter, r *http.Request) {
+	s.apply(w, r, false)
+}
+
+// handlePlan applies definitions in dry-run mode.
+func (s *serveController) handlePlan(w http.ResponseWriter, r *http.Request) {
+	s.apply(w, r, true)
+}
+
+func (s *serveController) apply(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	var req ApplyRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %v", err))
+		return
+	}
+
+	opts, err := applyOptions(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts.DryRun = opts.DryRun || dryRun
+
+	// The output of the request is kept together in the server log.
+	buffer := log.NewBuffer()
+	defer buffer.Flush()
+	opts.Logger = buffer.Logger()
+	opts.AuditLog = s.auditLog
+	opts.Policy = s.policy
+	opts.Hooks = s.hooks
+	opts.LenientAPIVersions = s.opts.LenientAPIVersions
+	opts.Logger.InfoMaybeWithKeyf("dry-run", opts.DryRun, "Applying definitions for request from %s", r.RemoteAddr)
+
+	results, err := kdef.Apply(r.Context(), s.cl, []byte(req.Definitions), opts)
+	if err != nil {
+		opts.Logger.Error(err)
+		switch {
+		case errors.Is(err, kdef.ErrInvalidDefinitions):
+			writeError(w, http.StatusBadRequest, err)
+		case errors.Is(err, kdef.ErrPolicyViolations):
+			writeError(w, http.StatusUnprocessableEntity, err)
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// applyOptions validates an apply request and returns the apply options.
+func applyOptions(req ApplyRequest) (kdef.ApplyOptions, error) {
+	format := req.Format
+	if len(format) == 0 {
+		format = "yaml"
+	}
+	defFormat := opt.ParseDefinitionFormat(format)
+	if defFormat == opt.UnsupportedFormat {
+		return kdef.ApplyOptions{}, fmt.Errorf("\"format\" must be one of %q", strings.Join(opt.DefinitionFormatValidValues, "|"))
+	}
+	if req.ReassAwaitTimeout < 0 {
+		return kdef.ApplyOptions{}, fmt.Errorf("\"reassAwaitTimeout\" must be greater or equal to 0")
+	}
+
+	return kdef.ApplyOptions{
+		DefinitionFormat:  defFormat,
+		PropertyOverrides: req.PropertyOverrides,
+		DryRun:            req.DryRun,
+		ReassAwaitTimeout: req.ReassAwaitTimeout,
+		ContinueOnError:   req.ContinueOnError,
+	}, nil
+}
+
+func (s *serveController) handleExport(w http.ResponseWriter, r *http.Request) {
+	kind := r.PathValue("kind")
+	if !str.Contains(kind, exportKinds) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("kind must be one of %q", strings.Join(exportKinds, "|")))
+		return
+	}
+
+	opts, err := exportOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := kdef.Export(r.Context(), s.cl, kind, opts)
+	if err != nil {
+		log.Error(err)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if results == nil {
+		results = res.ExportResults{}
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// exportOptions validates the query of an export request and returns the exporter options.
+// Defaults are the same as the export command.
+func exportOptions(r *http.Request) (kdef.ExporterOptions, error) {
+	query := r.URL.Query()
+	get := func(key string, defaultValue string) string {
+		if query.Has(key) {
+			return query.Get(key)
+		}
+		return defaultValue
+	}
+
+	opts := kdef.ExporterOptions{
+		Match:           get("match", ".*"),
+		Exclude:         get("exclude", ".^"),
+		ACLResourceType: get("type", "any"),
+	}
+	for _, pattern := range []string{opts.Match, opts.Exclude} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return opts, fmt.Errorf("\"match\" and \"exclude\" must be valid regular expressions: %v", err)
+		}
+	}
+	if !str.Contains(opts.ACLResourceType, opt.ACLResourceTypeValidValues) {
+		return opts, fmt.Errorf("\"type\" must be one of %q", strings.Join(opt.ACLResourceTypeValidValues, "|"))
+	}
+
+	opts.TopicAssignments = opt.ParseAssignments(get("assignments", "none"))
+	if opts.TopicAssignments == opt.UnsupportedAssignments {
+		return opts, fmt.Errorf("\"assignments\" must be one of %q", strings.Join(opt.AssignmentsValidValues, "|"))
+	}
+
+	var err error
+	if opts.TopicIncludeInternal, err = strconv.ParseBool(get("includeInternal", "false")); err != nil {
+		return opts, fmt.Errorf("\"includeInternal\" must be a boolean")
+	}
+	if opts.ACLAutoGroup, err = strconv.ParseBool(get("autoGroup", "true")); err != nil {
+		return opts, fmt.Errorf("\"autoGroup\" must be a boolean")
+	}
+
+	return opts, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error(err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, errorResponse{Err: err.Error()})
+}
@@ -0,0 +1,139 @@
+// Package serve implements the serve controller.
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/audit"
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/hooks"
+	"github.com/peter-evans/kdef/core/policy"
+)
+
+// shutdownTimeout is the time to wait for in-flight requests to complete when shutting down.
+const shutdownTimeout = 30 * time.Second
+
+// ControllerOptions represents options to configure a serve controller.
+type ControllerOptions struct {
+	Address               string
+	AuthToken             string
+	MaxConcurrentRequests int
+	LenientAPIVersions    []string
+}
+
+// NewServeController creates a new serve controller.
+func NewServeController(
+	cl *client.Client,
+	opts ControllerOptions,
+) *serveController { //revive:disable-line:unexported-return
+	return &serveController{
+		cl:   cl,
+		opts: opts,
+		sem:  make(chan struct{}, opts.MaxConcurrentRequests),
+	}
+}
+
+type serveController struct {
+	cl       *client.Client
+	opts     ControllerOptions
+	sem      chan struct{}
+	hooks    []client.HookConfig
+	auditLog *audit.Log
+	policy   *policy.Policy
+}
+
+// Execute implements the execution of the serve controller.
+// Requests are served until the context is cancelled.
+func (s *serveController) Execute(ctx context.Context) error {
+	if err := hooks.Validate(s.cl.Hooks()); err != nil {
+		return err
+	}
+	s.hooks = s.cl.Hooks()
+	s.auditLog = audit.NewLog(s.cl)
+	if path := s.cl.PolicyFile(); len(path) > 0 {
+		var err error
+		if s.policy, err = policy.Load(path); err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("tcp", s.opts.Address)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+	log.Infof("Serving API on %q", ln.Addr().String())
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Infof("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handler returns the handler of the API.
+func (s *serveController) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/apply", s.handleApply)
+	mux.HandleFunc("POST /v1/plan", s.handlePlan)
+	mux.HandleFunc("GET /v1/export/{kind}", s.handleExport)
+	return s.authenticate(s.limit(mux))
+}
+
+// authenticate requires requests to present the auth token as a bearer token, if configured.
+func (s *serveController) authenticate(next http.Handler) http.Handler {
+	if len(s.opts.AuthToken) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.AuthToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limit limits the number of requests handled concurrently.
+// Requests over the limit wait until a request completes or the client gives up.
+func (s *serveController) limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case s.sem <- struct{}{}:
+		case <-r.Context().Done():
+			writeError(w, http.StatusServiceUnavailable, r.Context().Err())
+			return
+		}
+		defer func() { <-s.sem }()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,119 @@
+// Package serve implements the serve controller.
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_serveController_handler(t *testing.T) {
+	s := NewServeController(nil, ControllerOptions{
+		AuthToken:             "secret",
+		MaxConcurrentRequests: 1,
+	})
+	handler := s.handler()
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		token    string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Tests missing bearer token",
+			method:   http.MethodPost,
+			target:   "/v1/apply",
+			body:     `{}`,
+			wantCode: http.StatusUnauthorized,
+			wantBody: "invalid or missing bearer token",
+		},
+		{
+			name:     "Tests invalid bearer token",
+			method:   http.MethodPost,
+			target:   "/v1/apply",
+			body:     `{}`,
+			token:    "foo",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "Tests unknown request field",
+			method:   http.MethodPost,
+			target:   "/v1/apply",
+			body:     `{"foo": "bar"}`,
+			token:    "secret",
+			wantCode: http.StatusBadRequest,
+			wantBody: "invalid request",
+		},
+		{
+			name:     "Tests unsupported format",
+			method:   http.MethodPost,
+			target:   "/v1/plan",
+			body:     `{"definitions": "{}", "format": "toml"}`,
+			token:    "secret",
+			wantCode: http.StatusBadRequest,
+			wantBody: "\\\"format\\\" must be one of",
+		},
+		{
+			name:     "Tests invalid definitions",
+			method:   http.MethodPost,
+			target:   "/v1/plan",
+			body:     `{"definitions": "apiVersion: v1\nkind: foo"}`,
+			token:    "secret",
+			wantCode: http.StatusBadRequest,
+			wantBody: "invalid definitions",
+		},
+		{
+			name:     "Tests no definitions",
+			method:   http.MethodPost,
+			target:   "/v1/apply",
+			body:     `{"definitions": ""}`,
+			token:    "secret",
+			wantCode: http.StatusBadRequest,
+			wantBody: "no resource definitions found",
+		},
+		{
+			name:     "Tests unsupported export kind",
+			method:   http.MethodGet,
+			target:   "/v1/export/user",
+			token:    "secret",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Tests invalid export query",
+			method:   http.MethodGet,
+			target:   "/v1/export/topic?assignments=foo",
+			token:    "secret",
+			wantCode: http.StatusBadRequest,
+			wantBody: "assignments",
+		},
+		{
+			name:     "Tests unsupported method",
+			method:   http.MethodGet,
+			target:   "/v1/apply",
+			token:    "secret",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if len(tt.token) > 0 {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("handler.ServeHTTP() code = %v, want %v", rec.Code, tt.wantCode)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("handler.ServeHTTP() body = %v, want containing %v", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Package validate implements the validate controller.
+package validate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/peter-evans/kdef/core/model/meta"
+)
+
+// brokerMetadata represents the metadata of a broker in a brokers file.
+type brokerMetadata struct {
+	ID   int32  `json:"id"`
+	Rack string `json:"rack,omitempty"`
+}
+
+// loadBrokers loads the brokers of a YAML or JSON brokers file.
+func loadBrokers(path string) (meta.Brokers, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read brokers file: %v", err)
+	}
+
+	var metadata []brokerMetadata
+	if err := yaml.Unmarshal(b, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid brokers file %q: %v", path, err)
+	}
+	if len(metadata) == 0 {
+		return nil, fmt.Errorf("invalid brokers file %q: no brokers found", path)
+	}
+
+	ids := map[int32]bool{}
+	brokers := make(meta.Brokers, len(metadata))
+	for i, m := range metadata {
+		if ids[m.ID] {
+			return nil, fmt.Errorf("invalid brokers file %q: duplicate broker id %d", path, m.ID)
+		}
+		ids[m.ID] = true
+		brokers[i] = meta.Broker{ID: m.ID, Rack: m.Rack}
+	}
+
+	return brokers, nil
+}
@@ -0,0 +1,351 @@
+// Package validate implements the validate controller.
+package validate
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/helpers/docparse"
+	"github.com/peter-evans/kdef/core/helpers/overlay"
+	"github.com/peter-evans/kdef/core/helpers/render"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/policy"
+	"github.com/peter-evans/kdef/kdef"
+)
+
+// ControllerOptions represents options to configure a validate controller.
+type ControllerOptions struct {
+	DefinitionFormat   opt.DefinitionFormat
+	LenientAPIVersions []string
+	PolicyFile         string
+	BrokersFile        string
+	VarsFile           string
+	Substitute         bool
+	Template           bool
+	Overlay            string
+	Selector           string
+}
+
+// NewValidateController creates a new validate controller.
+func NewValidateController(
+	args []string,
+	opts ControllerOptions,
+) *validateController { //revive:disable-line:unexported-return
+	return &validateController{
+		args: args,
+		opts: opts,
+	}
+}
+
+type validateController struct {
+	args []string
+	opts ControllerOptions
+
+	// Internal fields.
+	render   render.Options
+	overlay  *overlay.Overlay
+	problems []problem
+}
+
+// problem represents an error or warning found validating definitions.
+type problem struct {
+	severity string
+	msg      string
+}
+
+// document represents a definition document and its position.
+type document struct {
+	def    kdef.Definition
+	source *def.Source
+}
+
+// position returns the position of the document.
+func (d document) position() string {
+	return d.source.String()
+}
+
+// Execute implements the execution of the validate controller.
+func (v *validateController) Execute() error {
+	var p *policy.Policy
+	if len(v.opts.PolicyFile) > 0 {
+		var err error
+		if p, err = policy.Load(v.opts.PolicyFile); err != nil {
+			return err
+		}
+	}
+
+	v.render.Template = v.opts.Template
+	// Supplying variables enables substitution.
+	v.render.Substitute = v.opts.Substitute || len(v.opts.VarsFile) > 0
+	if len(v.opts.VarsFile) > 0 {
+		var err error
+		if v.render.Vars, err = render.LoadVars(v.opts.VarsFile); err != nil {
+			return err
+		}
+	}
+
+	if len(v.opts.Overlay) > 0 {
+		var err error
+		if v.overlay, err = overlay.Load(v.opts.Overlay); err != nil {
+			return err
+		}
+	}
+
+	var sel selector.Selector
+	if len(v.opts.Selector) > 0 {
+		var err error
+		if sel, err = selector.Parse(v.opts.Selector); err != nil {
+			return err
+		}
+	}
+
+	var brokers meta.Brokers
+	if len(v.opts.BrokersFile) > 0 {
+		var err error
+		if brokers, err = loadBrokers(v.opts.BrokersFile); err != nil {
+			return err
+		}
+	}
+
+	docs := v.loadDocuments()
+	for _, p := range v.overlay.Unmatched() {
+		v.report(policy.SeverityWarning, v.opts.Overlay, fmt.Sprintf("%s did not match any definitions", p))
+	}
+	docs = v.resolveProfiles(docs)
+	docs = selectDocuments(docs, sel)
+
+	for _, doc := range docs {
+		if err := kdef.ValidateDefinition(doc.def, brokers); err != nil {
+			v.reportError(doc, err)
+		}
+	}
+
+	defs := make([]kdef.Definition, len(docs))
+	for i, doc := range docs {
+		defs[i] = doc.def
+	}
+	duplicates := kdef.DuplicateDefinitions(defs)
+	indices := make([]int, 0, len(duplicates))
+	for i := range duplicates {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	for _, i := range indices {
+		v.reportError(docs[i], fmt.Errorf(
+			"%s %q is already defined in %s",
+			docs[i].def.Resource.Kind,
+			docs[i].def.Resource.Metadata.Name,
+			docs[duplicates[i]].position(),
+		))
+	}
+
+	if p != nil {
+		for _, doc := range docs {
+			violations, err := kdef.CheckPolicy(p, []kdef.Definition{doc.def})
+			if err != nil {
+				v.reportError(doc, err)
+				continue
+			}
+			for _, violation := range violations {
+				if violation.Severity == policy.SeverityWarning {
+					v.reportWarning(doc, violation.String())
+				} else {
+					v.reportError(doc, fmt.Errorf("%s", violation))
+				}
+			}
+		}
+	}
+
+	errors := v.count(policy.SeverityError)
+	log.Infof("Validated %d definition(s) with %d error(s) and %d warning(s)", len(docs), errors, v.count(policy.SeverityWarning))
+
+	if errors > 0 {
+		return fmt.Errorf("validation completed with errors")
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("no resource definitions found")
+	}
+
+	return nil
+}
+
+// count returns the number of problems of a severity.
+func (v *validateController) count(severity string) int {
+	n := 0
+	for _, p := range v.problems {
+		if p.severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// reportError reports an error in a document.
+func (v *validateController) reportError(doc document, err error) {
+	v.report(policy.SeverityError, doc.position(), err.Error())
+}
+
+// reportWarning reports a warning in a document.
+func (v *validateController) reportWarning(doc document, msg string) {
+	v.report(policy.SeverityWarning, doc.position(), msg)
+}
+
+// report reports a problem at a location.
+func (v *validateController) report(severity string, location string, msg string) {
+	msg = fmt.Sprintf("%s: %s", location, msg)
+	v.problems = append(v.problems, problem{severity: severity, msg: msg})
+	if severity == policy.SeverityWarning {
+		log.Warnf("%s", msg)
+	} else {
+		log.Error(fmt.Errorf("%s", msg))
+	}
+}
+
+// resolveProfiles resolves the topic profiles referenced by topic definitions.
+// Topic definitions referencing profiles that cannot be resolved are reported as errors and not further validated.
+func (v *validateController) resolveProfiles(docs []document) []document {
+	// Invalid and duplicate profiles are reported when definitions are validated.
+	profiles := map[string]def.TopicProfileDefinition{}
+	for _, doc := range docs {
+		if doc.def.Resource.Kind != def.KindTopicProfile {
+			continue
+		}
+		profileDef, err := def.LoadTopicProfileDefinition(doc.def.Document, doc.def.Format, doc.def.LenientAPIVersions)
+		if err != nil || profileDef.Validate() != nil {
+			continue
+		}
+		if _, ok := profiles[profileDef.Metadata.Name]; !ok {
+			profiles[profileDef.Metadata.Name] = profileDef
+		}
+	}
+
+	var resolved []document
+	for _, doc := range docs {
+		d, err := kdef.ResolveTopicProfiles(doc.def, profiles)
+		if err != nil {
+			v.reportError(doc, err)
+			continue
+		}
+		doc.def = d
+		resolved = append(resolved, doc)
+	}
+	return resolved
+}
+
+// selectDocuments returns the documents of definitions with labels matching a label selector.
+// All documents are returned if the selector is nil.
+func selectDocuments(docs []document, sel selector.Selector) []document {
+	if sel == nil {
+		return docs
+	}
+
+	var selected []document
+	for _, doc := range docs {
+		if sel.Matches(doc.def.Resource.Metadata.Labels) {
+			selected = append(selected, doc)
+		}
+	}
+	return selected
+}
+
+// loadDocuments loads the definition documents of stdin or files.
+// Documents that cannot be loaded are reported as errors.
+func (v *validateController) loadDocuments() []document {
+	if v.args[0] == "-" {
+		log.Infof("Reading definition(s) from stdin")
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			v.report(policy.SeverityError, "stdin", fmt.Sprintf("failed to read definition(s): %v", err))
+			return nil
+		}
+		return v.loadDefinitionBytes(b, "")
+	}
+
+	var docs []document
+	for _, arg := range v.args {
+		basepath, pattern := doublestar.SplitPattern(arg)
+		fsys := os.DirFS(basepath)
+
+		err := doublestar.GlobWalk(fsys, pattern, func(p string, d fs.DirEntry) error {
+			if d.IsDir() {
+				return nil
+			}
+
+			path := filepath.Join(basepath, p)
+			log.Infof("Reading definition(s) from file %q", path)
+			b, err := os.ReadFile(path)
+			if err != nil {
+				v.report(policy.SeverityError, path, fmt.Sprintf("failed to read definition(s): %v", err))
+				return nil
+			}
+			docs = append(docs, v.loadDefinitionBytes(b, path)...)
+
+			return nil
+		})
+		if err != nil {
+			v.report(policy.SeverityError, arg, err.Error())
+		}
+	}
+
+	return docs
+}
+
+// loadDefinitionBytes renders and loads the definitions of stdin or a file.
+func (v *validateController) loadDefinitionBytes(b []byte, file string) []document {
+	location := file
+	if len(location) == 0 {
+		location = "stdin"
+	}
+
+	b, err := render.Render(b, v.render)
+	if err != nil {
+		v.report(policy.SeverityError, location, fmt.Sprintf("failed to render definition(s): %v", err))
+		return nil
+	}
+	defDocs, err := docparse.FromBytes(b, docparse.Format(v.opts.DefinitionFormat))
+	if err != nil {
+		v.report(policy.SeverityError, location, fmt.Sprintf("failed to read definition(s): %v", err))
+		return nil
+	}
+	return v.loadDefinitionDocs(defDocs, file)
+}
+
+// loadDefinitionDocs loads the definitions of the documents of a file.
+func (v *validateController) loadDefinitionDocs(defDocs []docparse.Document, file string) []document {
+	var docs []document
+	for _, defDoc := range defDocs {
+		doc := document{source: &def.Source{
+			File: file,
+			Doc:  defDoc.Index + 1,
+			Line: defDoc.Line,
+		}}
+		defDoc, err := v.overlay.Apply(defDoc, docparse.Format(v.opts.DefinitionFormat))
+		if err != nil {
+			v.reportError(doc, err)
+			continue
+		}
+		resourceDefs, err := kdef.ResourceDefinitions([]string{defDoc.Content}, v.opts.DefinitionFormat, v.opts.LenientAPIVersions)
+		if err != nil {
+			v.reportError(doc, fmt.Errorf("invalid resource definition: %v", err))
+			continue
+		}
+		doc.def = kdef.Definition{
+			Document:           defDoc.Content,
+			Resource:           resourceDefs[0],
+			Format:             v.opts.DefinitionFormat,
+			LenientAPIVersions: v.opts.LenientAPIVersions,
+			Source:             doc.source,
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
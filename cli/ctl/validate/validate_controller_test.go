@@ -0,0 +1,326 @@
+// Package validate implements the validate controller.
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/policy"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func Test_validateController_Execute(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	topics := write("topics.yml", `apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 3
+  replicationFactor: 2
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: bar
+spec:
+  partitions: 0
+  replicationFactor: 1
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: baz
+spec:
+  partitions: 1
+  replicationFactor: 1
+  deleteUndefinedConfig: true
+`)
+	acls := write("acls.yml", `apiVersion: v1
+kind: foo
+metadata:
+  name: foo
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 1
+  replicationFactor: 1
+`)
+	brokers := write("brokers.yml", `- id: 1
+  rack: zone-a
+`)
+	rendered := write("rendered.yml", `apiVersion: v1
+kind: topic
+metadata:
+  name: {{ .name }}
+spec:
+  partitions: ${PARTITIONS}
+  replicationFactor: ${REPLICATION_FACTOR:-1}
+`)
+	unrendered := write("unrendered.yml", `apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: ${KDEF_TEST_UNSET}
+  replicationFactor: 1
+`)
+	vars := write("vars.yml", `name: qux
+PARTITIONS: 0
+`)
+	overlay := write("overlay.yml", `patches:
+  - target:
+      name: bar
+    merge:
+      spec:
+        partitions: 3
+  - target:
+      kind: topic
+      name: baz
+    jsonPatch:
+      - op: remove
+        path: /spec/deleteUndefinedConfig
+  - target:
+      kind: topic
+      name: qux
+    merge:
+      spec:
+        partitions: 3
+`)
+	profiles := write("profiles.yml", `apiVersion: v1
+kind: topicProfile
+metadata:
+  name: compacted
+spec:
+  configs:
+    cleanup.policy: compact
+  partitions: 3
+  replicationFactor: 1
+---
+apiVersion: v1
+kind: topicProfile
+metadata:
+  name: invalid
+spec:
+  partitions: -1
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  profiles: [compacted]
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: bar
+spec:
+  profiles: [invalid]
+`)
+	labelled := write("labelled.yml", `apiVersion: v1
+kind: topic
+metadata:
+  name: payments.events
+  labels:
+    team: payments
+spec:
+  partitions: 3
+  replicationFactor: 2
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: payments.test
+  labels:
+    team: payments
+    tier: test
+spec:
+  partitions: 0
+  replicationFactor: 1
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: orders.events
+  labels:
+    team: orders
+spec:
+  partitions: 0
+  replicationFactor: 1
+`)
+	policyFile := write("policy.yml", `rules:
+  - name: replication-factor
+    severity: warning
+    kinds: [topic]
+    assert:
+      - path: spec.replicationFactor
+        gte: 2
+`)
+
+	tests := []struct {
+		name    string
+		args    []string
+		opts    ControllerOptions
+		want    []problem
+		wantErr string
+	}{
+		{
+			name: "Tests validation of definitions",
+			args: []string{topics, acls},
+			opts: ControllerOptions{DefinitionFormat: opt.YAMLFormat},
+			want: []problem{
+				{policy.SeverityError, acls + ":1 (document 1): invalid resource definition: invalid definition kind \"foo\""},
+				{policy.SeverityError, topics + ":9 (document 2): partitions must be greater than 0"},
+				{policy.SeverityError, topics + ":17 (document 3): unknown field \"spec.deleteUndefinedConfig\""},
+				{policy.SeverityError, acls + ":6 (document 2): topic \"foo\" is already defined in " + topics + ":1 (document 1)"},
+			},
+			wantErr: "validation completed with errors",
+		},
+		{
+			name: "Tests validation with broker metadata and a policy",
+			args: []string{topics},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				BrokersFile:      brokers,
+				PolicyFile:       policyFile,
+			},
+			want: []problem{
+				{policy.SeverityError, topics + ":1 (document 1): replication factor cannot exceed the number of available brokers"},
+				{policy.SeverityError, topics + ":9 (document 2): partitions must be greater than 0"},
+				{policy.SeverityError, topics + ":17 (document 3): unknown field \"spec.deleteUndefinedConfig\""},
+				{policy.SeverityWarning, topics + ":9 (document 2): topic definition \"bar\" violates policy rule \"replication-factor\": spec.replicationFactor must be greater than or equal to 2 but is 1"},
+				{policy.SeverityWarning, topics + ":17 (document 3): topic definition \"baz\" violates policy rule \"replication-factor\": spec.replicationFactor must be greater than or equal to 2 but is 1"},
+			},
+			wantErr: "validation completed with errors",
+		},
+		{
+			name: "Tests validation of rendered definitions",
+			args: []string{rendered, unrendered},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				VarsFile:         vars,
+				Template:         true,
+			},
+			want: []problem{
+				{policy.SeverityError, unrendered + ": failed to render definition(s): line 6: variable \"KDEF_TEST_UNSET\" is not set"},
+				{policy.SeverityError, rendered + ":1 (document 1): partitions must be greater than 0"},
+			},
+			wantErr: "validation completed with errors",
+		},
+		{
+			name: "Tests validation of definitions with an overlay",
+			args: []string{topics},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				Overlay:          overlay,
+			},
+			want: []problem{
+				{policy.SeverityWarning, overlay + ": patch 3 of \"" + overlay + "\" did not match any definitions"},
+			},
+		},
+		{
+			name: "Tests validation of definitions with profiles",
+			args: []string{profiles},
+			opts: ControllerOptions{DefinitionFormat: opt.YAMLFormat},
+			want: []problem{
+				{policy.SeverityError, profiles + ":25 (document 4): topic profile \"invalid\" is not defined"},
+				{policy.SeverityError, profiles + ":11 (document 2): partitions must not be negative"},
+			},
+			wantErr: "validation completed with errors",
+		},
+		{
+			name: "Tests validation of definitions matching a selector",
+			args: []string{labelled},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				Selector:         "team=payments,tier!=test",
+			},
+			want: nil,
+		},
+		{
+			name: "Tests validation of definitions not matching a selector",
+			args: []string{labelled},
+			opts: ControllerOptions{
+				DefinitionFormat: opt.YAMLFormat,
+				Selector:         "team=finance",
+			},
+			want:    nil,
+			wantErr: "no resource definitions found",
+		},
+		{
+			name:    "Tests an invalid selector",
+			args:    []string{labelled},
+			opts:    ControllerOptions{DefinitionFormat: opt.YAMLFormat, Selector: "team=,"},
+			want:    nil,
+			wantErr: "invalid selector",
+		},
+		{
+			name:    "Tests no definitions",
+			args:    []string{filepath.Join(dir, "*.json")},
+			opts:    ControllerOptions{DefinitionFormat: opt.JSONFormat},
+			want:    nil,
+			wantErr: "no resource definitions found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidateController(tt.args, tt.opts)
+			if err := v.Execute(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(v.problems, tt.want) {
+				t.Errorf("problems = %v, want %v", v.problems, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadBrokers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "Tests a valid brokers file",
+			content: "- id: 1\n  rack: zone-a\n- id: 2\n",
+			wantErr: "",
+		},
+		{
+			name:    "Tests an empty brokers file",
+			content: "[]",
+			wantErr: "no brokers found",
+		},
+		{
+			name:    "Tests duplicate broker ids",
+			content: "- id: 1\n- id: 1\n",
+			wantErr: "duplicate broker id 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "brokers.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadBrokers(path); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("loadBrokers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
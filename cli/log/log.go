@@ -4,8 +4,11 @@ package log //nolint:revive // package name conflicts with standard library
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/fatih/color"
+
+	corelog "github.com/peter-evans/kdef/core/log"
 )
 
 // Logging options.
@@ -14,47 +17,123 @@ var (
 	Verbose = false
 )
 
+// Logger is the logger of the core packages.
+type Logger = corelog.Logger
+
+// Output is the sink that writes log entries to standard output and standard error.
+var Output corelog.Sink = output{}
+
+// outMu serializes writes to standard output and standard error.
+var outMu sync.Mutex
+
+// std is the logger used by the package level functions.
+var std = corelog.New(Output)
+
+func init() {
+	// The core packages log to the output of the cli.
+	corelog.SetDefaultSink(Output)
+}
+
+type output struct{}
+
+// Log writes a log entry immediately.
+func (output) Log(entry corelog.Entry) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	write(entry)
+}
+
+// Buffer represents a sink that buffers log entries until flushed.
+// Buffering keeps the messages of concurrent operations grouped in the output.
+type Buffer struct {
+	mu      sync.Mutex
+	entries []corelog.Entry
+}
+
+// NewBuffer creates a sink that buffers log entries until flushed.
+func NewBuffer() *Buffer {
+	return &Buffer{}
+}
+
+// Log buffers a log entry.
+func (b *Buffer) Log(entry corelog.Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, entry)
+}
+
+// Logger creates a logger that writes to the buffer.
+func (b *Buffer) Logger() *Logger {
+	return corelog.New(b)
+}
+
+// Flush writes buffered log entries and empties the buffer.
+func (b *Buffer) Flush() {
+	b.mu.Lock()
+	entries := b.entries
+	b.entries = nil
+	b.mu.Unlock()
+
+	outMu.Lock()
+	defer outMu.Unlock()
+	for _, entry := range entries {
+		write(entry)
+	}
+}
+
+func write(entry corelog.Entry) {
+	switch entry.Level {
+	case corelog.LevelDebug:
+		if !Quiet && Verbose {
+			fmt.Print(color.HiBlackString("%s", entry.Msg) + "\n")
+		}
+	case corelog.LevelInfo:
+		if !Quiet {
+			if len(entry.Key) > 0 {
+				fmt.Print(color.MagentaString("[%s] ", entry.Key) + entry.Msg + "\n")
+			} else {
+				fmt.Print(entry.Msg + "\n")
+			}
+		}
+	case corelog.LevelWarn:
+		if !Quiet {
+			fmt.Print(color.YellowString("[warn] ") + entry.Msg + "\n")
+		}
+	case corelog.LevelError:
+		fmt.Fprint(os.Stderr, color.RedString("[error] ")+entry.Msg+"\n")
+	case corelog.LevelOutput:
+		if !Quiet {
+			fmt.Print(entry.Msg + "\n")
+		}
+	}
+}
+
 // Infof prints an info level message.
 func Infof(format string, args ...interface{}) {
-	if !Quiet {
-		fmt.Printf(format+"\n", args...)
-	}
+	std.Infof(format, args...)
 }
 
 // InfoWithKeyf prints an info level message with prefixed key.
 func InfoWithKeyf(key string, format string, args ...interface{}) {
-	if !Quiet {
-		k := color.MagentaString("[%s] ", key)
-		fmt.Printf(k+format+"\n", args...)
-	}
+	std.InfoWithKeyf(key, format, args...)
 }
 
 // InfoMaybeWithKeyf prints an info level message optionally with prefixed key.
 func InfoMaybeWithKeyf(key string, showKey bool, format string, args ...interface{}) {
-	if showKey {
-		InfoWithKeyf(key, format, args...)
-	} else {
-		Infof(format, args...)
-	}
+	std.InfoMaybeWithKeyf(key, showKey, format, args...)
 }
 
 // Debugf prints a debug level message.
 func Debugf(format string, args ...interface{}) {
-	if !Quiet && Verbose {
-		color.HiBlack(format, args...)
-	}
+	std.Debugf(format, args...)
 }
 
 // Warnf prints a warn level message.
 func Warnf(format string, args ...interface{}) {
-	if !Quiet {
-		k := color.YellowString("[warn] ")
-		fmt.Printf(k+format+"\n", args...)
-	}
+	std.Warnf(format, args...)
 }
 
 // Error prints an error level message.
 func Error(err error) {
-	k := color.RedString("[error] ")
-	fmt.Fprintf(os.Stderr, k+"%v\n", err)
+	std.Error(err)
 }
@@ -0,0 +1,182 @@
+// Package audit implements a tamper-evident log of applied changes.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// Record represents an audit record of the apply of a definition.
+// Each record contains the hash of the previous record, chaining the records of a log.
+type Record struct {
+	Time       time.Time       `json:"time"`
+	User       string          `json:"user"`
+	Host       string          `json:"host"`
+	ClusterID  string          `json:"clusterId"`
+	Kind       string          `json:"kind"`
+	Name       string          `json:"name"`
+	Source     string          `json:"source,omitempty"`
+	Diff       string          `json:"diff"`
+	Operations json.RawMessage `json:"operations"`
+	Applied    bool            `json:"applied"`
+	Err        string          `json:"error,omitempty"`
+	PrevHash   string          `json:"prevHash"`
+	Hash       string          `json:"hash"`
+}
+
+// computeHash computes the SHA-256 hash of the JSON representation of a record, excluding its hash.
+func (r Record) computeHash() (string, error) {
+	r.Hash = ""
+	j, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute audit record hash: %v", err)
+	}
+	sum := sha256.Sum256(j)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify verifies that records are an unbroken chain, in order.
+func Verify(records []Record) error {
+	prevHash := ""
+	for i, r := range records {
+		// The first record may follow records that are no longer available.
+		if i > 0 && r.PrevHash != prevHash {
+			return fmt.Errorf("audit record %d does not follow the previous record", i)
+		}
+		hash, err := r.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != r.Hash {
+			return fmt.Errorf("audit record %d has been modified", i)
+		}
+		prevHash = r.Hash
+	}
+	return nil
+}
+
+// Sink represents a destination for audit records.
+type Sink interface {
+	// LastHash returns the hash of the last record, or an empty string if there are no records.
+	LastHash(ctx context.Context) (string, error)
+	// Append appends a record.
+	Append(ctx context.Context, r Record) error
+}
+
+// Log represents an audit log that chains records appended to a sink.
+// A Log is safe for concurrent use, but records of concurrent writers to the same sink are not chained.
+type Log struct {
+	sink           Sink
+	fetchClusterID func(ctx context.Context) (string, error)
+
+	mu        sync.Mutex
+	loaded    bool
+	lastHash  string
+	clusterID string
+}
+
+// NewLog creates an audit log from the audit configuration of a client.
+// Returns nil if an audit log is not configured.
+func NewLog(cl *client.Client) *Log {
+	config := cl.Audit()
+	if config == nil {
+		return nil
+	}
+
+	var sink Sink
+	if len(config.File) > 0 {
+		sink = NewFileSink(config.File)
+	} else {
+		sink = NewTopicSink(cl, config.Topic)
+	}
+
+	srv := kafka.NewService(cl)
+	return newLog(sink, func(ctx context.Context) (string, error) {
+		metadata, err := srv.DescribeMetadata(ctx, []string{}, false)
+		if err != nil {
+			return "", err
+		}
+		return metadata.ClusterID, nil
+	})
+}
+
+// newLog creates an audit log that appends records to a sink.
+func newLog(sink Sink, fetchClusterID func(ctx context.Context) (string, error)) *Log {
+	return &Log{
+		sink:           sink,
+		fetchClusterID: fetchClusterID,
+	}
+}
+
+// Record appends an audit record of the apply of a definition.
+func (l *Log) Record(ctx context.Context, kind string, name string, source string, result *res.ApplyResult) error {
+	ops, err := json.Marshal(result.Operations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal operations: %v", err)
+	}
+
+	r := Record{
+		Time:       time.Now().UTC(),
+		User:       currentUser(),
+		Kind:       kind,
+		Name:       name,
+		Source:     source,
+		Diff:       result.Diff,
+		Operations: ops,
+		Applied:    result.Applied,
+		Err:        result.Err,
+	}
+	r.Host, _ = os.Hostname()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.loaded {
+		l.clusterID, err = l.fetchClusterID(ctx)
+		if err != nil {
+			return err
+		}
+
+		l.lastHash, err = l.sink.LastHash(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %v", err)
+		}
+		l.loaded = true
+	}
+
+	r.ClusterID = l.clusterID
+	r.PrevHash = l.lastHash
+	if r.Hash, err = r.computeHash(); err != nil {
+		return err
+	}
+
+	if err := l.sink.Append(ctx, r); err != nil {
+		return fmt.Errorf("failed to write audit record: %v", err)
+	}
+	l.lastHash = r.Hash
+
+	return nil
+}
+
+// currentUser returns the user running kdef.
+// The user can be overridden with the KDEF_AUDIT_USER environment variable, for example, in CI.
+func currentUser() string {
+	if u, ok := os.LookupEnv("KDEF_AUDIT_USER"); ok {
+		return u
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,118 @@
+// Package audit implements a tamper-evident log of applied changes.
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestLog_Record(t *testing.T) {
+	t.Setenv("KDEF_AUDIT_USER", "ci")
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	ctx := context.Background()
+	fetchClusterID := func(context.Context) (string, error) { return "cluster", nil }
+
+	// A new log continues the chain of records written by a previous log.
+	for _, name := range []string{"foo", "bar"} {
+		l := newLog(NewFileSink(path), fetchClusterID)
+		result := &res.ApplyResult{Diff: "diff", Applied: true}
+		if err := l.Record(ctx, "topic", name, "topics.yml", result); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	records, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %v, want 2", len(records))
+	}
+	if err := Verify(records); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if records[0].PrevHash != "" || records[1].PrevHash != records[0].Hash {
+		t.Errorf("records are not chained: %+v", records)
+	}
+	r := records[1]
+	if r.User != "ci" || r.ClusterID != "cluster" || r.Kind != "topic" || r.Name != "bar" ||
+		r.Source != "topics.yml" || r.Diff != "diff" || !r.Applied {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	chain := func() []Record {
+		var records []Record
+		prevHash := ""
+		for _, name := range []string{"foo", "bar", "baz"} {
+			r := Record{Kind: "topic", Name: name, PrevHash: prevHash}
+			r.Hash, _ = r.computeHash()
+			prevHash = r.Hash
+			records = append(records, r)
+		}
+		return records
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(records []Record) []Record
+		wantErr string
+	}{
+		{
+			name:    "Tests an unbroken chain",
+			modify:  func(records []Record) []Record { return records },
+			wantErr: "",
+		},
+		{
+			name:    "Tests a chain following records that are no longer available",
+			modify:  func(records []Record) []Record { return records[1:] },
+			wantErr: "",
+		},
+		{
+			name: "Tests a modified record",
+			modify: func(records []Record) []Record {
+				records[1].Name = "qux"
+				return records
+			},
+			wantErr: "audit record 1 has been modified",
+		},
+		{
+			name: "Tests a removed record",
+			modify: func(records []Record) []Record {
+				return append(records[:1], records[2:]...)
+			},
+			wantErr: "audit record 1 does not follow the previous record",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.modify(chain())); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileSink_LastHash(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	hash, err := NewFileSink(filepath.Join(dir, "missing.jsonl")).LastHash(ctx)
+	if err != nil || hash != "" {
+		t.Errorf("LastHash() = %q, %v, want empty hash for a missing file", hash, err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.jsonl")
+	if err := os.WriteFile(invalid, []byte("{}\nfoo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSink(invalid).LastHash(ctx); !tutil.ErrorContains(err, "invalid audit record on line 2") {
+		t.Errorf("LastHash() error = %v, want invalid audit record", err)
+	}
+}
@@ -0,0 +1,88 @@
+// Package audit implements a tamper-evident log of applied changes.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileSink represents a sink that appends audit records to a JSON lines file.
+type FileSink struct {
+	path string
+}
+
+// NewFileSink creates a sink that appends audit records to a JSON lines file.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// LastHash returns the hash of the last record of the file.
+func (f *FileSink) LastHash(_ context.Context) (string, error) {
+	records, err := ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", nil
+	}
+	return records[len(records)-1].Hash, nil
+}
+
+// Append appends a record to the file, creating it if necessary.
+func (f *FileSink) Append(_ context.Context, r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadFile reads the audit records of a JSON lines file.
+func ReadFile(path string) ([]Record, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("invalid audit record on line %d: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
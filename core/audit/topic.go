@@ -0,0 +1,112 @@
+// Package audit implements a tamper-evident log of applied changes.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/helpers/assignments"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+// maxTopicReplicationFactor is the maximum replication factor of a created audit topic.
+const maxTopicReplicationFactor = 3
+
+// TopicSink represents a sink that produces audit records to a topic.
+// The topic is created if it does not exist, with a single partition to preserve the order of records.
+// Records are keyed by their unique hash, so no records are removed by compaction.
+type TopicSink struct {
+	srv   *kafka.Service
+	topic string
+}
+
+// NewTopicSink creates a sink that produces audit records to a topic.
+func NewTopicSink(cl *client.Client, topic string) *TopicSink {
+	return &TopicSink{
+		srv:   kafka.NewService(cl),
+		topic: topic,
+	}
+}
+
+// LastHash returns the hash of the last record of the topic, creating the topic if it does not exist.
+func (t *TopicSink) LastHash(ctx context.Context) (string, error) {
+	if err := t.ensureTopic(ctx); err != nil {
+		return "", err
+	}
+
+	offsets, err := t.srv.ListOffsets(ctx, kafka.PartitionOffsets{
+		t.topic: {0: kafka.ListOffsetsLatest},
+	})
+	if err != nil {
+		return "", err
+	}
+	end := offsets[t.topic][0]
+	if end == 0 {
+		return "", nil
+	}
+
+	record, err := t.srv.FetchRecord(ctx, t.topic, 0, end-1)
+	if err != nil {
+		return "", err
+	}
+	var r Record
+	if err := json.Unmarshal(record.Value, &r); err != nil {
+		return "", fmt.Errorf("invalid audit record at offset %d: %v", record.Offset, err)
+	}
+
+	return r.Hash, nil
+}
+
+// Append produces a record to the topic.
+func (t *TopicSink) Append(ctx context.Context, r Record) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return t.srv.ProduceRecord(ctx, t.topic, []byte(r.Hash), value)
+}
+
+// ensureTopic creates the topic if it does not exist.
+func (t *TopicSink) ensureTopic(ctx context.Context) error {
+	metadata, err := t.srv.DescribeMetadata(ctx, []string{t.topic}, false)
+	if err != nil {
+		return err
+	}
+	if metadata.Topics[0].Exists {
+		if partitions := len(metadata.Topics[0].PartitionAssignments); partitions != 1 {
+			return fmt.Errorf("audit topic %q must have 1 partition but has %d", t.topic, partitions)
+		}
+		return nil
+	}
+
+	repFactor := len(metadata.Brokers)
+	if repFactor > maxTopicReplicationFactor {
+		repFactor = maxTopicReplicationFactor
+	}
+
+	cleanupPolicy := "compact"
+	topicDef := def.TopicDefinition{
+		ResourceDefinition: def.ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       def.KindTopic,
+			Metadata:   def.ResourceMetadataDefinition{Name: t.topic},
+		},
+		Spec: def.TopicSpecDefinition{
+			Configs:           def.ConfigsMap{"cleanup.policy": &cleanupPolicy},
+			Partitions:        1,
+			ReplicationFactor: repFactor,
+		},
+	}
+	topicAssignments := assignments.AddPartitions(
+		[][]int32{},
+		1,
+		repFactor,
+		map[int32]int{},
+		metadata.Brokers.IDs(),
+	)
+
+	return t.srv.CreateTopic(ctx, topicDef, topicAssignments, false)
+}
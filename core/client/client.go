@@ -11,7 +11,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/util/str"
 
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -60,6 +60,26 @@ func (cl *Client) AlterConfigsMethod() string {
 	return cl.cc.AlterConfigsMethod
 }
 
+// ExpectedClusterID is the ID of the cluster that definitions are expected to be applied to.
+func (cl *Client) ExpectedClusterID() string {
+	return cl.cc.ExpectedClusterID
+}
+
+// Audit is the configuration of the audit log, if any.
+func (cl *Client) Audit() *AuditConfig {
+	return cl.cc.Audit
+}
+
+// PolicyFile is the path of the policy file that definitions must comply with, if any.
+func (cl *Client) PolicyFile() string {
+	return cl.cc.PolicyFile
+}
+
+// Hooks are the hooks that run commands and call webhooks on apply events.
+func (cl *Client) Hooks() []HookConfig {
+	return cl.cc.Hooks
+}
+
 func (cl *Client) validateNonClientOptConfig() error {
 	if cl.cc.TimeoutMs < 0 {
 		return fmt.Errorf("timeoutMs must be greater or equal to 0")
@@ -69,9 +89,19 @@ func (cl *Client) validateNonClientOptConfig() error {
 		return fmt.Errorf("alterConfigsMethod must be one of %q", strings.Join(alterConfigsMethodValidValues, "|"))
 	}
 
+	if cl.cc.Audit != nil && (len(cl.cc.Audit.File) > 0) == (len(cl.cc.Audit.Topic) > 0) {
+		return fmt.Errorf("audit must specify one of file or topic")
+	}
+
 	return nil
 }
 
+// NewConsumer creates an underlying Kafka client with the options of the client and additional options,
+// such as the partitions to consume. The caller must close the client.
+func (cl *Client) NewConsumer(opts ...kgo.Opt) (*kgo.Client, error) {
+	return kgo.NewClient(append(append([]kgo.Opt{}, cl.kgoOpts...), opts...)...)
+}
+
 func (cl *Client) buildClient() error {
 	log.Debugf("Building Kafka client")
 
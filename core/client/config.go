@@ -19,6 +19,34 @@ type Config struct {
 	TimeoutMs int32 `json:"timeoutMs,omitempty"`
 	// The alter configs method that should be used (auto, incremental, non-incremental).
 	AlterConfigsMethod string `json:"alterConfigsMethod,omitempty"`
+	// The ID of the cluster that definitions are expected to be applied to.
+	ExpectedClusterID string `json:"expectedClusterId,omitempty"`
+	// Hooks that run commands and call webhooks on apply events.
+	Hooks []HookConfig `json:"hooks,omitempty"`
+	// The audit log that records applied changes.
+	Audit *AuditConfig `json:"audit,omitempty"`
+	// The path of a policy file with rules that definitions must comply with.
+	PolicyFile string `json:"policyFile,omitempty"`
+}
+
+// HookConfig represents the configuration of a hook.
+type HookConfig struct {
+	// The hook point (preApply, postApply) or event type the hook runs on.
+	On string `json:"on"`
+	// A command and its arguments to run.
+	Command []string `json:"command,omitempty"`
+	// A URL to send a POST request to.
+	URL string `json:"url,omitempty"`
+	// Timeout in milliseconds of the command or request.
+	TimeoutMs int `json:"timeoutMs,omitempty"`
+}
+
+// AuditConfig represents the configuration of an audit log.
+type AuditConfig struct {
+	// The path of a JSON lines file to append audit records to.
+	File string `json:"file,omitempty"`
+	// The name of a topic to produce audit records to.
+	Topic string `json:"topic,omitempty"`
 }
 
 type tlsConfig struct {
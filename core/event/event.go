@@ -0,0 +1,132 @@
+// Package event implements events emitted during the apply of a definition.
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// Type represents the type of an event.
+type Type string
+
+// Event types.
+const (
+	// TypeDefinitionLoaded is emitted when a definition has been loaded.
+	TypeDefinitionLoaded Type = "definitionLoaded"
+	// TypeOpsPlanned is emitted when the operations to apply a definition have been planned.
+	TypeOpsPlanned Type = "opsPlanned"
+	// TypeOpExecuted is emitted when an operation has been executed.
+	TypeOpExecuted Type = "opExecuted"
+	// TypeOpFailed is emitted when an operation has failed.
+	TypeOpFailed Type = "opFailed"
+	// TypeReassignmentProgress is emitted when the in-progress partition reassignments of a topic change.
+	TypeReassignmentProgress Type = "reassignmentProgress"
+	// TypeCompleted is emitted when the apply of a definition has completed, successfully or not.
+	TypeCompleted Type = "completed"
+)
+
+// TypeValidValues is the list of valid event types.
+var TypeValidValues = []string{
+	string(TypeDefinitionLoaded),
+	string(TypeOpsPlanned),
+	string(TypeOpExecuted),
+	string(TypeOpFailed),
+	string(TypeReassignmentProgress),
+	string(TypeCompleted),
+}
+
+// Event represents an event emitted during the apply of a definition.
+type Event struct {
+	Type   Type      `json:"type"`
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Name   string    `json:"name,omitempty"`
+	DryRun bool      `json:"dryRun"`
+
+	// Op is the operation of opExecuted and opFailed events.
+	Op string `json:"op,omitempty"`
+	// Err is the error of opFailed and completed events.
+	Err string `json:"error,omitempty"`
+	// Reassignments are the in-progress partition reassignments of reassignmentProgress events.
+	// Empty when the reassignments have completed.
+	Reassignments meta.PartitionReassignments `json:"reassignments,omitempty"`
+	// Result is the apply result of opsPlanned and completed events.
+	Result *res.ApplyResult `json:"result,omitempty"`
+}
+
+// Handler represents a handler of events.
+// Handlers must be safe for concurrent use.
+type Handler interface {
+	Handle(ctx context.Context, e Event)
+}
+
+// HandlerFunc is an adapter to use a function as a handler.
+type HandlerFunc func(ctx context.Context, e Event)
+
+// Handle calls f(ctx, e).
+func (f HandlerFunc) Handle(ctx context.Context, e Event) {
+	f(ctx, e)
+}
+
+// Emitter emits the events of the apply of a definition to a handler.
+// A nil handler discards all events.
+type Emitter struct {
+	handler Handler
+	kind    string
+	name    string
+	dryRun  bool
+}
+
+// NewEmitter creates an emitter for the apply of a definition of a kind.
+func NewEmitter(handler Handler, kind string, dryRun bool) *Emitter {
+	return &Emitter{
+		handler: handler,
+		kind:    kind,
+		dryRun:  dryRun,
+	}
+}
+
+// DefinitionLoaded emits a definitionLoaded event and names the definition of subsequent events.
+func (e *Emitter) DefinitionLoaded(ctx context.Context, name string) {
+	e.name = name
+	e.emit(ctx, Event{Type: TypeDefinitionLoaded})
+}
+
+// OpsPlanned emits an opsPlanned event.
+func (e *Emitter) OpsPlanned(ctx context.Context, result *res.ApplyResult) {
+	e.emit(ctx, Event{Type: TypeOpsPlanned, Result: result})
+}
+
+// ExecuteOp executes an operation and emits an opExecuted or opFailed event.
+func (e *Emitter) ExecuteOp(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		e.emit(ctx, Event{Type: TypeOpFailed, Op: op, Err: err.Error()})
+		return err
+	}
+	e.emit(ctx, Event{Type: TypeOpExecuted, Op: op})
+	return nil
+}
+
+// ReassignmentProgress emits a reassignmentProgress event.
+func (e *Emitter) ReassignmentProgress(ctx context.Context, reassignments meta.PartitionReassignments) {
+	e.emit(ctx, Event{Type: TypeReassignmentProgress, Reassignments: reassignments})
+}
+
+// Completed emits a completed event.
+func (e *Emitter) Completed(ctx context.Context, result *res.ApplyResult) {
+	e.emit(ctx, Event{Type: TypeCompleted, Err: result.Err, Result: result})
+}
+
+func (e *Emitter) emit(ctx context.Context, ev Event) {
+	if e.handler == nil {
+		return
+	}
+	ev.Time = time.Now()
+	ev.Kind = e.kind
+	ev.Name = e.name
+	ev.DryRun = e.dryRun
+	e.handler.Handle(ctx, ev)
+}
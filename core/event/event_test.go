@@ -0,0 +1,74 @@
+// Package event implements events emitted during the apply of a definition.
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+func TestEmitter(t *testing.T) {
+	var events []Event
+	handler := HandlerFunc(func(_ context.Context, e Event) {
+		events = append(events, e)
+	})
+	ctx := context.Background()
+	result := &res.ApplyResult{Err: "failed"}
+
+	e := NewEmitter(handler, "topic", true)
+	e.DefinitionLoaded(ctx, "foo")
+	e.OpsPlanned(ctx, result)
+	if err := e.ExecuteOp(ctx, "createTopic", func(context.Context) error { return nil }); err != nil {
+		t.Errorf("ExecuteOp() error = %v", err)
+	}
+	if err := e.ExecuteOp(ctx, "alterConfigs", func(context.Context) error { return errors.New("failed") }); err == nil {
+		t.Errorf("ExecuteOp() error = nil, want error")
+	}
+	e.ReassignmentProgress(ctx, meta.PartitionReassignments{{Partition: 1}})
+	e.Completed(ctx, result)
+
+	want := []struct {
+		typ Type
+		op  string
+		err string
+	}{
+		{typ: TypeDefinitionLoaded},
+		{typ: TypeOpsPlanned},
+		{typ: TypeOpExecuted, op: "createTopic"},
+		{typ: TypeOpFailed, op: "alterConfigs", err: "failed"},
+		{typ: TypeReassignmentProgress},
+		{typ: TypeCompleted, err: "failed"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("len(events) = %v, want %v", len(events), len(want))
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Type != w.typ || got.Op != w.op || got.Err != w.err {
+			t.Errorf("events[%d] = %v %q %q, want %v %q %q", i, got.Type, got.Op, got.Err, w.typ, w.op, w.err)
+		}
+		if got.Kind != "topic" || got.Name != "foo" || !got.DryRun || got.Time.IsZero() {
+			t.Errorf("events[%d] kind, name, dry-run or time not set: %+v", i, got)
+		}
+	}
+	if events[1].Result != result || events[5].Result != result {
+		t.Errorf("opsPlanned and completed events do not have the apply result")
+	}
+	if len(events[4].Reassignments) != 1 {
+		t.Errorf("reassignmentProgress event does not have the reassignments")
+	}
+}
+
+func TestEmitter_nilHandler(t *testing.T) {
+	e := NewEmitter(nil, "topic", false)
+	e.DefinitionLoaded(context.Background(), "foo")
+	err := e.ExecuteOp(context.Background(), "createTopic", func(context.Context) error {
+		return errors.New("failed")
+	})
+	if err == nil || err.Error() != "failed" {
+		t.Errorf("ExecuteOp() error = %v, want failed", err)
+	}
+}
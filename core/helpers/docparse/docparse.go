@@ -0,0 +1,216 @@
+// Package docparse implements parsers to transform input into separated documents.
+package docparse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format represents the format of the documents to be parsed.
+type Format int8
+
+// Supported formats.
+const (
+	YAML Format = 1
+	JSON Format = 2
+)
+
+// Document represents a document and its position in the parsed input.
+type Document struct {
+	Content string
+	// Index is the index of the document in the input.
+	Index int
+	// Line is the line of the input the document starts on, starting at 1.
+	Line int
+}
+
+// yamlDocMarkerRegExp matches YAML document start and end markers, which cannot occur within the content of a document.
+var yamlDocMarkerRegExp = regexp.MustCompile(`(?m)^(---|\.\.\.)([ \t\r]|$)`)
+
+// FromFile parses a file to a slice of separated documents.
+func FromFile(filepath string, format Format) ([]Document, error) {
+	b, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromBytes(b, format)
+}
+
+// FromStdin parses stdin to a slice of separated documents.
+func FromStdin(format Format) ([]Document, error) {
+	var b []byte
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		b = append(b, scanner.Bytes()...)
+		b = append(b, "\n"...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return FromBytes(b, format)
+}
+
+// FromBytes parses bytes to a slice of separated documents.
+func FromBytes(b []byte, format Format) ([]Document, error) {
+	switch format {
+	case YAML:
+		return bytesToYAMLDocs(b)
+	case JSON:
+		docs, err := bytesToJSONDocs(b)
+		if err != nil {
+			return nil, err
+		}
+		return docs, nil
+	default:
+		return nil, fmt.Errorf("unsupported format")
+	}
+}
+
+// bytesToYAMLDocs parses a stream of YAML documents and returns the original text of each non-empty document.
+func bytesToYAMLDocs(b []byte) ([]Document, error) {
+	lines := lineOffsets(b)
+	markers := yamlDocMarkerRegExp.FindAllIndex(b, -1)
+
+	var yDocs []Document
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		content := node.Content[0]
+		if content.Kind == yaml.ScalarNode && content.Tag == "!!null" && len(content.Value) == 0 {
+			// The document is empty or contains only comments.
+			continue
+		}
+
+		start := offsetAt(b, lines, content.Line, content.Column)
+		// Include the indentation of the first line unless the content follows a document marker.
+		if lineStart := offsetAt(b, lines, content.Line, 1); len(bytes.TrimSpace(b[lineStart:start])) == 0 {
+			start = lineStart
+		}
+		// The document ends at the next document marker.
+		end := len(b)
+		for _, m := range markers {
+			if m[0] >= start {
+				end = m[0]
+				break
+			}
+		}
+
+		yDocs = append(yDocs, Document{
+			Content: string(bytes.TrimRight(b[start:end], " \t\r\n")),
+			Index:   len(yDocs),
+			Line:    content.Line,
+		})
+	}
+
+	return yDocs, nil
+}
+
+func bytesToJSONDocs(b []byte) ([]Document, error) {
+	var bi interface{}
+	if err := json.Unmarshal(b, &bi); err != nil {
+		return nil, err
+	}
+
+	var docs []interface{}
+	var offsets []int
+	switch v := bi.(type) {
+	case []interface{}:
+		docs = v
+		var err error
+		if offsets, err = jsonArrayOffsets(b); err != nil {
+			return nil, err
+		}
+	case interface{}:
+		docs = []interface{}{v}
+		offsets = []int{len(b) - len(bytes.TrimLeft(b, " \t\r\n"))}
+	default:
+		return nil, fmt.Errorf("json document is invalid")
+	}
+
+	jDocs := make([]Document, len(docs))
+	for i, doc := range docs {
+		jb, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		jDocs[i] = Document{
+			Content: string(jb),
+			Index:   i,
+			Line:    lineAt(b, offsets[i]),
+		}
+	}
+
+	return jDocs, nil
+}
+
+// jsonArrayOffsets returns the offsets of the elements of a JSON array.
+func jsonArrayOffsets(b []byte) ([]int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var offsets []int
+	for decoder.More() {
+		// The offset of the decoder precedes any separator and whitespace before the element.
+		offset := int(decoder.InputOffset())
+		offset += len(b[offset:]) - len(bytes.TrimLeft(b[offset:], ", \t\r\n"))
+		offsets = append(offsets, offset)
+
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return nil, err
+		}
+	}
+	return offsets, nil
+}
+
+// lineOffsets returns the offsets in bytes of the start of each line.
+func lineOffsets(b []byte) []int {
+	offsets := []int{0}
+	for i, c := range b {
+		if c == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// offsetAt returns the offset in bytes of a line and column, starting at 1.
+func offsetAt(b []byte, lines []int, line int, column int) int {
+	if line > len(lines) {
+		return len(b)
+	}
+	offset := lines[line-1]
+	// Columns count characters rather than bytes.
+	for i := 1; i < column && offset < len(b); i++ {
+		_, size := utf8.DecodeRune(b[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// lineAt returns the line of an offset in bytes, starting at 1.
+func lineAt(b []byte, offset int) int {
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
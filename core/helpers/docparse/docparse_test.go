@@ -0,0 +1,181 @@
+// Package docparse implements parsers to transform input into separated documents.
+package docparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_bytesToYAMLDocs(t *testing.T) {
+	type args struct {
+		bytes []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Document
+	}{
+		{
+			name: "Tests basic doc split",
+			args: args{
+				bytes: []byte("doc1\n---\ndoc2\n---\ndoc3"),
+			},
+			want: []Document{
+				{Content: "doc1", Index: 0, Line: 1},
+				{Content: "doc2", Index: 1, Line: 3},
+				{Content: "doc3", Index: 2, Line: 5},
+			},
+		},
+		{
+			name: "Tests doc split with leading separator",
+			args: args{
+				bytes: []byte("---\ndoc1\n---\ndoc2\n---\ndoc3"),
+			},
+			want: []Document{
+				{Content: "doc1", Index: 0, Line: 2},
+				{Content: "doc2", Index: 1, Line: 4},
+				{Content: "doc3", Index: 2, Line: 6},
+			},
+		},
+		{
+			name: "Tests doc split with unnecessary whitespace",
+			args: args{
+				bytes: []byte("doc1    \n\n---\n  doc2\n---\n\n  doc3"),
+			},
+			want: []Document{
+				{Content: "doc1", Index: 0, Line: 1},
+				{Content: "  doc2", Index: 1, Line: 4},
+				{Content: "  doc3", Index: 2, Line: 7},
+			},
+		},
+		{
+			name: "Tests doc split with YAML comments",
+			args: args{
+				bytes: []byte("doc1 #foo\n---\n#bar\ndoc2\n---\n#baz\n---\ndoc3"),
+			},
+			want: []Document{
+				{Content: "doc1 #foo", Index: 0, Line: 1},
+				{Content: "doc2", Index: 1, Line: 4},
+				{Content: "doc3", Index: 2, Line: 8},
+			},
+		},
+		{
+			name: "Tests doc split retaining values containing #",
+			args: args{
+				bytes: []byte("a: \"b#c\"\nd: e#f\n---\ng: '#h'"),
+			},
+			want: []Document{
+				{Content: "a: \"b#c\"\nd: e#f", Index: 0, Line: 1},
+				{Content: "g: '#h'", Index: 1, Line: 4},
+			},
+		},
+		{
+			name: "Tests doc split with a block scalar containing ---",
+			args: args{
+				bytes: []byte("a: |\n  ---\n  b\nc: d\n---\ne: f"),
+			},
+			want: []Document{
+				{Content: "a: |\n  ---\n  b\nc: d", Index: 0, Line: 1},
+				{Content: "e: f", Index: 1, Line: 6},
+			},
+		},
+		{
+			name: "Tests doc split with document end markers",
+			args: args{
+				bytes: []byte("a: b\n...\n---\nc: d\n...\n"),
+			},
+			want: []Document{
+				{Content: "a: b", Index: 0, Line: 1},
+				{Content: "c: d", Index: 1, Line: 4},
+			},
+		},
+		{
+			name: "Tests doc split with anchors and aliases",
+			args: args{
+				bytes: []byte("a: &x\n  b: c\nd: *x\n--- !!map\ne: f"),
+			},
+			want: []Document{
+				{Content: "a: &x\n  b: c\nd: *x", Index: 0, Line: 1},
+				{Content: "!!map\ne: f", Index: 1, Line: 4},
+			},
+		},
+		{
+			name: "Tests doc split with content following a document marker",
+			args: args{
+				bytes: []byte("--- |\n  a\n--- {b: c}\n"),
+			},
+			want: []Document{
+				{Content: "|\n  a", Index: 0, Line: 1},
+				{Content: "{b: c}", Index: 1, Line: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bytesToYAMLDocs(tt.args.bytes)
+			if err != nil {
+				t.Fatalf("bytesToYAMLDocs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bytesToYAMLDocs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_bytesToJSONDocs(t *testing.T) {
+	type args struct {
+		bytes []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Document
+		wantErr bool
+	}{
+		{
+			name: "Tests handling a single JSON doc (non-array)",
+			args: args{
+				bytes: []byte("{\"name\": \"foo\"}"),
+			},
+			want: []Document{
+				{Content: "{\"name\":\"foo\"}", Index: 0, Line: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Tests handling an array of JSON docs",
+			args: args{
+				bytes: []byte("[{\"name\": \"foo\"},{\"name\": \"bar\"}]"),
+			},
+			want: []Document{
+				{Content: "{\"name\":\"foo\"}", Index: 0, Line: 1},
+				{Content: "{\"name\":\"bar\"}", Index: 1, Line: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Tests the lines of an array of JSON docs",
+			args: args{
+				bytes: []byte("\n[\n  {\n    \"name\": \"foo\"\n  },\n\n  {\"name\": \"bar\"}\n]\n"),
+			},
+			want: []Document{
+				{Content: "{\"name\":\"foo\"}", Index: 0, Line: 3},
+				{Content: "{\"name\":\"bar\"}", Index: 1, Line: 7},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bytesToJSONDocs(tt.args.bytes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bytesToJSONDocs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bytesToJSONDocs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Package drift implements helper functions for building drift reports.
+package drift
+
+import (
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// Status returns the drift status of an existing resource with or without pending operations.
+func Status(pending bool) string {
+	if pending {
+		return res.DriftStatusDrifted
+	}
+	return res.DriftStatusInSync
+}
+
+// Configs returns the drift of remote configs that config operations would change.
+func Configs(ops kafka.ConfigOperations, remoteConfigs def.Configs) []res.ValueDrift {
+	var drift []res.ValueDrift
+	for _, op := range ops {
+		var remoteValue *string
+		var source string
+		remote, exists := remoteConfigs.Get(op.Name)
+		if exists {
+			remoteValue = remote.Value
+			source = remote.Source.String()
+		}
+
+		var localValue *string
+		if op.Op == kafka.SetConfigOperation {
+			localValue = op.Value
+			// Non-incremental alter configs operations also set unchanged configs.
+			if exists && equalValues(localValue, remoteValue) {
+				continue
+			}
+		}
+
+		drift = append(drift, res.ValueDrift{
+			Name:   op.Name,
+			Local:  localValue,
+			Remote: remoteValue,
+			Source: source,
+		})
+	}
+	return drift
+}
+
+func equalValues(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
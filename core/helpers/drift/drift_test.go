@@ -0,0 +1,54 @@
+// Package drift implements helper functions for building drift reports.
+package drift
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+func TestConfigs(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	remoteConfigs := def.Configs{
+		{Name: "retention.ms", Value: strPtr("3600000"), Source: def.ConfigSourceDynamicTopicConfig},
+		{Name: "cleanup.policy", Value: strPtr("delete"), Source: def.ConfigSourceDefaultConfig},
+		{Name: "segment.ms", Value: strPtr("600000"), Source: def.ConfigSourceDynamicTopicConfig},
+	}
+
+	tests := []struct {
+		name string
+		ops  kafka.ConfigOperations
+		want []res.ValueDrift
+	}{
+		{
+			name: "Tests no operations",
+			ops:  nil,
+			want: nil,
+		},
+		{
+			name: "Tests changed, added, deleted and unchanged configs",
+			ops: kafka.ConfigOperations{
+				{Name: "cleanup.policy", Value: strPtr("compact"), Op: kafka.SetConfigOperation},
+				{Name: "max.message.bytes", Value: strPtr("1048588"), Op: kafka.SetConfigOperation},
+				{Name: "retention.ms", Value: strPtr("3600000"), Op: kafka.SetConfigOperation},
+				{Name: "segment.ms", Op: kafka.DeleteConfigOperation},
+			},
+			want: []res.ValueDrift{
+				{Name: "cleanup.policy", Local: strPtr("compact"), Remote: strPtr("delete"), Source: "DEFAULT_CONFIG"},
+				{Name: "max.message.bytes", Local: strPtr("1048588"), Remote: (*string)(nil), Source: ""},
+				{Name: "segment.ms", Local: (*string)(nil), Remote: strPtr("600000"), Source: "DYNAMIC_TOPIC_CONFIG"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Configs(tt.ops, remoteConfigs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Configs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
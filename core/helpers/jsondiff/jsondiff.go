@@ -2,7 +2,9 @@
 package jsondiff
 
 import (
+	"bytes"
 	"encoding/json"
+	"strings"
 
 	"github.com/peter-evans/kdef/core/util/diff"
 )
@@ -13,11 +15,15 @@ func Diff(a interface{}, b interface{}) (string, error) {
 	toJSON := func(d interface{}) (string, error) {
 		j := "null"
 		if d != nil {
-			jBytes, err := json.MarshalIndent(d, "", "  ")
-			if err != nil {
+			// HTML escaping is disabled to keep values such as "<default>" human readable.
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(d); err != nil {
 				return "", err
 			}
-			j = string(jBytes)
+			j = strings.TrimSuffix(buf.String(), "\n")
 		}
 		return j, nil
 	}
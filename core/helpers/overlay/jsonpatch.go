@@ -0,0 +1,288 @@
+// Package overlay implements environment overlays that patch resource definitions.
+package overlay
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// operation represents a JSON patch (RFC 6902) operation.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// validate validates the operation.
+func (o operation) validate() error {
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return fmt.Errorf("%s operation must have a value", o.Op)
+		}
+	case "move", "copy":
+		if _, err := parsePointer(o.From); err != nil {
+			return fmt.Errorf("%s operation has an invalid from: %v", o.Op, err)
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("invalid operation %q", o.Op)
+	}
+	if _, err := parsePointer(o.Path); err != nil {
+		return fmt.Errorf("%s operation has an invalid path: %v", o.Op, err)
+	}
+	return nil
+}
+
+// applyJSONPatch applies JSON patch operations to a document and returns the patched document.
+func applyJSONPatch(doc interface{}, ops []operation) (interface{}, error) {
+	for _, o := range ops {
+		var err error
+		if doc, err = applyOperation(doc, o); err != nil {
+			return nil, fmt.Errorf("%s operation at %q failed: %v", o.Op, o.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// applyOperation applies a JSON patch operation to a document and returns the patched document.
+func applyOperation(doc interface{}, o operation) (interface{}, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add":
+		value, err := decodeJSON(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "remove":
+		return removeValue(doc, path)
+	case "replace":
+		value, err := decodeJSON(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, err = removeValue(doc, path); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "move":
+		from, _ := parsePointer(o.From)
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("cannot move a value into one of its children")
+		}
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = removeValue(doc, from); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case "copy":
+		from, _ := parsePointer(o.From)
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, deepCopy(value))
+	case "test":
+		want, err := decodeJSON(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		got, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(got, want) {
+			return nil, fmt.Errorf("value does not match")
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("invalid operation %q", o.Op)
+}
+
+// parsePointer parses a JSON pointer (RFC 6901) into its reference tokens.
+func parsePointer(p string) ([]string, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("pointer %q must start with \"/\"", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// isPrefix returns true if the tokens of a pointer are a prefix of another.
+func isPrefix(prefix []string, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getValue returns the value at the path of a document.
+func getValue(doc interface{}, path []string) (interface{}, error) {
+	for i, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", pointer(path[:i+1]))
+			}
+			doc = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", pointer(path[:i+1]))
+		}
+	}
+	return doc, nil
+}
+
+// addValue adds a value at the path of a document and returns the updated document.
+func addValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("path %q does not exist", pointer(path))
+	})
+}
+
+// removeValue removes the value at the path of a document and returns the updated document.
+func removeValue(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("cannot remove the document")
+	}
+	return updateParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := node[token]; !ok {
+				return nil, fmt.Errorf("path %q does not exist", pointer(path))
+			}
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(node[:index], node[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %q does not exist", pointer(path))
+	})
+}
+
+// updateParent updates the parent of the value at the path of a document with a function.
+// The function receives the parent and the last token of the path, and returns the updated parent.
+func updateParent(
+	doc interface{},
+	path []string,
+	update func(parent interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+
+	child, err := getValue(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = updateParent(child, path[1:], update); err != nil {
+		return nil, err
+	}
+
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		node[path[0]] = child
+	case []interface{}:
+		index, _ := strconv.Atoi(path[0])
+		node[index] = child
+	}
+	return doc, nil
+}
+
+// arrayIndex parses an array index token that must not be greater than max.
+func arrayIndex(token string, max int) (int, error) {
+	if len(token) == 0 || (len(token) > 1 && token[0] == '0') || strings.Trim(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > max {
+		return 0, fmt.Errorf("array index %q is out of range", token)
+	}
+	return index, nil
+}
+
+// pointer returns the JSON pointer of reference tokens.
+func pointer(tokens []string) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteString("/")
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+	}
+	return sb.String()
+}
+
+// deepCopy returns a deep copy of a decoded JSON value.
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			c[key] = deepCopy(item)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, item := range v {
+			c[i] = deepCopy(item)
+		}
+		return c
+	}
+	return value
+}
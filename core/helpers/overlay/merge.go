@@ -0,0 +1,23 @@
+// Package overlay implements environment overlays that patch resource definitions.
+package overlay
+
+// applyMergePatch applies a JSON merge patch (RFC 7386) to a document and returns the patched document.
+// Objects are merged recursively, null values remove keys, and arrays and scalars are replaced.
+func applyMergePatch(doc interface{}, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		d = map[string]interface{}{}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(d, key)
+		} else {
+			d[key] = applyMergePatch(d[key], value)
+		}
+	}
+	return d
+}
@@ -0,0 +1,295 @@
+// Package overlay implements environment overlays that patch resource definitions.
+package overlay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/peter-evans/kdef/core/helpers/docparse"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+)
+
+// overlayFileExtensions are the extensions of files loaded from an overlay directory.
+var overlayFileExtensions = []string{".yml", ".yaml", ".json"}
+
+// Overlay represents an environment overlay of patches to resource definitions.
+type Overlay struct {
+	patches []*patch
+}
+
+// overlayFile represents a file of overlay patches.
+type overlayFile struct {
+	Patches []patchDefinition `json:"patches"`
+}
+
+// target represents the resource definitions targeted by a patch.
+type target struct {
+	Kind     string `json:"kind,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Selector string `json:"selector,omitempty"`
+}
+
+// patchDefinition represents the definition of a patch in an overlay file.
+type patchDefinition struct {
+	Target    target          `json:"target"`
+	Merge     json.RawMessage `json:"merge,omitempty"`
+	JSONPatch []operation     `json:"jsonPatch,omitempty"`
+}
+
+// patch represents a patch to resource definitions and its position in an overlay file.
+type patch struct {
+	patchDefinition
+	selector selector.Selector
+	file     string
+	index    int
+	matched  int
+}
+
+// String returns the position of the patch.
+func (p *patch) String() string {
+	return fmt.Sprintf("patch %d of %q", p.index+1, p.file)
+}
+
+// Load loads an overlay from a file, or the YAML and JSON files of a directory in lexical order.
+func Load(path string) (*Overlay, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay: %v", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay: %v", err)
+		}
+		files = nil
+		for _, entry := range entries {
+			if !entry.IsDir() && isOverlayFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no overlay files found in %q", path)
+		}
+	}
+
+	o := &Overlay{}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay: %v", err)
+		}
+		patches, err := parse(b, file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overlay file %q: %v", file, err)
+		}
+		o.patches = append(o.patches, patches...)
+	}
+	return o, nil
+}
+
+// isOverlayFile returns true if the name of a file has an overlay file extension.
+func isOverlayFile(name string) bool {
+	ext := filepath.Ext(name)
+	for _, e := range overlayFileExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// parse parses and validates the patches of a YAML or JSON overlay file.
+func parse(b []byte, file string) ([]*patch, error) {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var of overlayFile
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&of); err != nil {
+		return nil, err
+	}
+	if len(of.Patches) == 0 {
+		return nil, fmt.Errorf("patches must be supplied")
+	}
+
+	patches := make([]*patch, len(of.Patches))
+	for i, pd := range of.Patches {
+		p := &patch{
+			patchDefinition: pd,
+			file:            file,
+			index:           i,
+		}
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("patch %d: %v", i+1, err)
+		}
+		patches[i] = p
+	}
+	return patches, nil
+}
+
+// validate validates the patch and parses its selector.
+func (p *patch) validate() error {
+	t := p.Target
+	if len(t.Kind) == 0 && len(t.Name) == 0 && len(t.Selector) == 0 {
+		return fmt.Errorf("target must have a kind, name or selector")
+	}
+	if len(t.Selector) > 0 {
+		var err error
+		if p.selector, err = selector.Parse(t.Selector); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.Merge != nil && p.JSONPatch != nil:
+		return fmt.Errorf("merge and jsonPatch cannot be used together")
+	case p.Merge != nil:
+		merge, err := decodeJSON(p.Merge)
+		if err != nil {
+			return err
+		}
+		if _, ok := merge.(map[string]interface{}); !ok {
+			return fmt.Errorf("merge must be an object")
+		}
+	case len(p.JSONPatch) > 0:
+		for i, o := range p.JSONPatch {
+			if err := o.validate(); err != nil {
+				return fmt.Errorf("operation %d: %v", i+1, err)
+			}
+		}
+	default:
+		return fmt.Errorf("one of merge or jsonPatch must be supplied")
+	}
+	return nil
+}
+
+// matches returns true if the patch targets a resource definition.
+func (p *patch) matches(kind string, name string, labels map[string]string) bool {
+	t := p.Target
+	if len(t.Kind) > 0 && t.Kind != kind {
+		return false
+	}
+	if len(t.Name) > 0 && t.Name != name {
+		return false
+	}
+	if p.selector != nil && !p.selector.Matches(labels) {
+		return false
+	}
+	return true
+}
+
+// apply applies the patch to a decoded resource definition and returns the patched definition.
+func (p *patch) apply(doc interface{}) (interface{}, error) {
+	if p.Merge != nil {
+		// Decoding the patch for each definition prevents definitions sharing values.
+		merge, err := decodeJSON(p.Merge)
+		if err != nil {
+			return nil, err
+		}
+		return applyMergePatch(doc, merge), nil
+	}
+	return applyJSONPatch(doc, p.JSONPatch)
+}
+
+// Apply applies the patches targeting the resource definition of a document in order.
+// Documents that are not targeted by any patch are returned unchanged.
+// Patched documents are re-encoded in their format, and YAML comments are not retained.
+func (o *Overlay) Apply(doc docparse.Document, format docparse.Format) (docparse.Document, error) {
+	if o == nil || len(o.patches) == 0 {
+		return doc, nil
+	}
+
+	j := []byte(doc.Content)
+	if format == docparse.YAML {
+		var err error
+		if j, err = yaml.YAMLToJSON(j); err != nil {
+			// Invalid documents are reported when their definitions are loaded.
+			return doc, nil
+		}
+	}
+	value, err := decodeJSON(j)
+	if err != nil {
+		return doc, nil
+	}
+	kind, name, labels := identify(value)
+
+	var patched bool
+	for _, p := range o.patches {
+		if !p.matches(kind, name, labels) {
+			continue
+		}
+		p.matched++
+		if value, err = p.apply(value); err != nil {
+			return doc, fmt.Errorf("failed to apply overlay %s: %v", p, err)
+		}
+		patched = true
+	}
+	if !patched {
+		return doc, nil
+	}
+
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return doc, err
+	}
+	if format == docparse.YAML {
+		if out, err = yaml.JSONToYAML(out); err != nil {
+			return doc, err
+		}
+	}
+	doc.Content = strings.TrimRight(string(out), "\n")
+	return doc, nil
+}
+
+// Unmatched returns the positions of patches that have not targeted any resource definitions.
+func (o *Overlay) Unmatched() []string {
+	if o == nil {
+		return nil
+	}
+	var unmatched []string
+	for _, p := range o.patches {
+		if p.matched == 0 {
+			unmatched = append(unmatched, p.String())
+		}
+	}
+	return unmatched
+}
+
+// identify returns the kind, name and labels of a decoded resource definition.
+func identify(doc interface{}) (string, string, map[string]string) {
+	m, _ := doc.(map[string]interface{})
+	kind, _ := m["kind"].(string)
+	metadata, _ := m["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+
+	labels := map[string]string{}
+	if l, ok := metadata["labels"].(map[string]interface{}); ok {
+		for key, value := range l {
+			if s, ok := value.(string); ok {
+				labels[key] = s
+			}
+		}
+	}
+	return kind, name, labels
+}
+
+// decodeJSON decodes JSON, preserving the original representation of numbers.
+func decodeJSON(b []byte) (interface{}, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
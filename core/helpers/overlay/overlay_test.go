@@ -0,0 +1,359 @@
+// Package overlay implements environment overlays that patch resource definitions.
+package overlay
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/helpers/docparse"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+var testTopic = `# Orders topic
+apiVersion: v1
+kind: topic
+metadata:
+  name: store.orders
+  labels:
+    tier: critical
+spec:
+  configs:
+    retention.ms: "604800000"
+    cleanup.policy: delete
+  partitions: 3
+  replicationFactor: 1`
+
+func TestOverlay_Apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay string
+		format  docparse.Format
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Tests a merge patch targeting kind and name",
+			overlay: `
+patches:
+  - target:
+      kind: topic
+      name: store.orders
+    merge:
+      spec:
+        configs:
+          retention.ms: "2592000000"
+          cleanup.policy: null
+        replicationFactor: 3
+`,
+			format:  docparse.YAML,
+			content: testTopic,
+			want: `apiVersion: v1
+kind: topic
+metadata:
+  labels:
+    tier: critical
+  name: store.orders
+spec:
+  configs:
+    retention.ms: "2592000000"
+  partitions: 3
+  replicationFactor: 3`,
+		},
+		{
+			name: "Tests a JSON patch targeting a selector",
+			overlay: `
+patches:
+  - target:
+      selector: tier=critical
+    jsonPatch:
+      - op: replace
+        path: /spec/partitions
+        value: 12
+      - op: add
+        path: /metadata/labels/env
+        value: prod
+`,
+			format:  docparse.YAML,
+			content: testTopic,
+			want: `apiVersion: v1
+kind: topic
+metadata:
+  labels:
+    env: prod
+    tier: critical
+  name: store.orders
+spec:
+  configs:
+    cleanup.policy: delete
+    retention.ms: "604800000"
+  partitions: 12
+  replicationFactor: 1`,
+		},
+		{
+			name: "Tests patches applied in order to a JSON document",
+			overlay: `
+patches:
+  - target:
+      kind: topic
+    merge:
+      spec:
+        partitions: 6
+  - target:
+      name: store.orders
+    jsonPatch:
+      - op: test
+        path: /spec/partitions
+        value: 6
+      - op: remove
+        path: /spec/configs
+`,
+			format:  docparse.JSON,
+			content: `{"apiVersion": "v1", "kind": "topic", "metadata": {"name": "store.orders"}, "spec": {"configs": {"cleanup.policy": "delete"}, "partitions": 3}}`,
+			want: `{
+  "apiVersion": "v1",
+  "kind": "topic",
+  "metadata": {
+    "name": "store.orders"
+  },
+  "spec": {
+    "partitions": 6
+  }
+}`,
+		},
+		{
+			name: "Tests a document not targeted by any patch",
+			overlay: `
+patches:
+  - target:
+      kind: topic
+      selector: tier!=critical
+    merge:
+      spec:
+        partitions: 6
+`,
+			format:  docparse.YAML,
+			content: testTopic,
+			want:    testTopic,
+		},
+		{
+			name: "Tests a failed JSON patch",
+			overlay: `
+patches:
+  - target:
+      kind: topic
+    jsonPatch:
+      - op: replace
+        path: /spec/assignments
+        value: []
+`,
+			format:  docparse.YAML,
+			content: testTopic,
+			wantErr: "failed to apply overlay patch 1 of \"prod.yml\": replace operation at \"/spec/assignments\" failed: path \"/spec/assignments\" does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patches, err := parse([]byte(tt.overlay), "prod.yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := &Overlay{patches: patches}
+
+			got, err := o.Apply(docparse.Document{Content: tt.content, Index: 1, Line: 5}, tt.format)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Overlay.Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(tt.wantErr) > 0 {
+				return
+			}
+			want := docparse.Document{Content: tt.want, Index: 1, Line: 5}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Overlay.Apply() = %q, want %q", got.Content, want.Content)
+			}
+			if unmatched := o.Unmatched(); tt.want != tt.content && len(unmatched) > 0 {
+				t.Errorf("Overlay.Unmatched() = %v, want none", unmatched)
+			}
+		})
+	}
+}
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay string
+		wantErr string
+	}{
+		{
+			name:    "Tests an overlay without patches",
+			overlay: "patches: []",
+			wantErr: "patches must be supplied",
+		},
+		{
+			name:    "Tests an unknown field",
+			overlay: "patches:\n  - target:\n      kind: topic\n    strategicMerge: {}\n",
+			wantErr: "unknown field \"strategicMerge\"",
+		},
+		{
+			name:    "Tests an empty target",
+			overlay: "patches:\n  - target: {}\n    merge: {}\n",
+			wantErr: "patch 1: target must have a kind, name or selector",
+		},
+		{
+			name:    "Tests an invalid selector",
+			overlay: "patches:\n  - target:\n      selector: tier=a=b\n    merge: {}\n",
+			wantErr: "invalid label value \"a=b\"",
+		},
+		{
+			name:    "Tests a patch without a merge or JSON patch",
+			overlay: "patches:\n  - target:\n      kind: topic\n",
+			wantErr: "one of merge or jsonPatch must be supplied",
+		},
+		{
+			name:    "Tests a patch with both a merge and JSON patch",
+			overlay: "patches:\n  - target:\n      kind: topic\n    merge: {}\n    jsonPatch:\n      - op: remove\n        path: /spec\n",
+			wantErr: "merge and jsonPatch cannot be used together",
+		},
+		{
+			name:    "Tests a merge that is not an object",
+			overlay: "patches:\n  - target:\n      kind: topic\n    merge: [1]\n",
+			wantErr: "merge must be an object",
+		},
+		{
+			name:    "Tests an invalid operation",
+			overlay: "patches:\n  - target:\n      kind: topic\n    jsonPatch:\n      - op: merge\n        path: /spec\n",
+			wantErr: "patch 1: operation 1: invalid operation \"merge\"",
+		},
+		{
+			name:    "Tests an operation without a value",
+			overlay: "patches:\n  - target:\n      kind: topic\n    jsonPatch:\n      - op: add\n        path: /spec/partitions\n",
+			wantErr: "add operation must have a value",
+		},
+		{
+			name:    "Tests an invalid path",
+			overlay: "patches:\n  - target:\n      kind: topic\n    jsonPatch:\n      - op: remove\n        path: spec\n",
+			wantErr: "remove operation has an invalid path: pointer \"spec\" must start with \"/\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse([]byte(tt.overlay), "prod.yml")
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b-topics.yml": "patches:\n  - target:\n      kind: topic\n    merge: {}\n",
+		"a-acls.json":  `{"patches": [{"target": {"kind": "acl"}, "merge": {}}]}`,
+		"README.md":    "# Production overlay",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"patch 1 of \"" + filepath.Join(dir, "a-acls.json") + "\"",
+		"patch 1 of \"" + filepath.Join(dir, "b-topics.yml") + "\"",
+	}
+	if got := o.Unmatched(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() patches = %v, want %v", got, want)
+	}
+
+	if _, err := Load(filepath.Join(dir, "README.md")); !tutil.ErrorContains(err, "invalid overlay file") {
+		t.Errorf("Load() error = %v, want an invalid overlay file", err)
+	}
+	if _, err := Load(t.TempDir()); !tutil.ErrorContains(err, "no overlay files found") {
+		t.Errorf("Load() error = %v, want no overlay files found", err)
+	}
+}
+
+func Test_applyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		ops     []operation
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Tests adding to and removing from arrays",
+			doc:  `{"acls": [{"host": "a"}, {"host": "c"}]}`,
+			ops: []operation{
+				{Op: "add", Path: "/acls/1", Value: []byte(`{"host": "b"}`)},
+				{Op: "add", Path: "/acls/-", Value: []byte(`{"host": "d"}`)},
+				{Op: "remove", Path: "/acls/0"},
+			},
+			want: `{"acls": [{"host": "b"}, {"host": "c"}, {"host": "d"}]}`,
+		},
+		{
+			name: "Tests moving and copying values",
+			doc:  `{"spec": {"configs": {"a.b": "1", "x/y": "2"}}}`,
+			ops: []operation{
+				{Op: "move", From: "/spec/configs/a.b", Path: "/spec/configs/c"},
+				{Op: "copy", From: "/spec/configs/x~1y", Path: "/spec/d"},
+			},
+			want: `{"spec": {"configs": {"c": "1", "x/y": "2"}, "d": "2"}}`,
+		},
+		{
+			name: "Tests a failed test",
+			doc:  `{"spec": {"partitions": 3}}`,
+			ops: []operation{
+				{Op: "test", Path: "/spec/partitions", Value: []byte(`6`)},
+			},
+			wantErr: "test operation at \"/spec/partitions\" failed: value does not match",
+		},
+		{
+			name: "Tests an array index out of range",
+			doc:  `{"acls": []}`,
+			ops: []operation{
+				{Op: "replace", Path: "/acls/0", Value: []byte(`{}`)},
+			},
+			wantErr: "array index \"0\" is out of range",
+		},
+		{
+			name: "Tests moving a value into one of its children",
+			doc:  `{"spec": {"configs": {}}}`,
+			ops: []operation{
+				{Op: "move", From: "/spec", Path: "/spec/configs"},
+			},
+			wantErr: "cannot move a value into one of its children",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeJSON([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := applyJSONPatch(doc, tt.ops)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("applyJSONPatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(tt.wantErr) > 0 {
+				return
+			}
+			want, err := decodeJSON([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("applyJSONPatch() = %v, want %v", got, want)
+			}
+		})
+	}
+}
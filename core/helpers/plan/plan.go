@@ -0,0 +1,70 @@
+// Package plan implements helper functions for recording and verifying apply plans.
+package plan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// Record records the remote state fingerprint and operations of an apply in its result.
+// If the apply is of a planned apply, the fingerprint and operations must match the plan.
+func Record(
+	result *res.ApplyResult,
+	remote interface{},
+	ops interface{},
+	planned *res.PlannedApply,
+) error {
+	fingerprint, err := Fingerprint(remote)
+	if err != nil {
+		return err
+	}
+
+	result.Fingerprint = fingerprint
+	result.Operations = ops
+
+	if planned == nil {
+		return nil
+	}
+
+	return Verify(*planned, fingerprint, ops)
+}
+
+// Fingerprint computes the SHA-256 fingerprint of the JSON representation of a remote state.
+func Fingerprint(remote interface{}) (string, error) {
+	j, err := json.Marshal(remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute fingerprint: %v", err)
+	}
+	sum := sha256.Sum256(j)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify verifies that a remote state fingerprint and operations match a planned apply.
+func Verify(planned res.PlannedApply, fingerprint string, ops interface{}) error {
+	if fingerprint != planned.Fingerprint {
+		return fmt.Errorf("remote state has drifted since the plan was created")
+	}
+
+	j, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("failed to marshal operations: %v", err)
+	}
+
+	var plannedOps bytes.Buffer
+	if len(planned.Operations) > 0 {
+		if err := json.Compact(&plannedOps, planned.Operations); err != nil {
+			return fmt.Errorf("invalid planned operations: %v", err)
+		}
+	}
+
+	if !bytes.Equal(j, plannedOps.Bytes()) {
+		return fmt.Errorf("operations differ from the plan")
+	}
+
+	return nil
+}
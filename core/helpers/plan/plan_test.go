@@ -0,0 +1,82 @@
+// Package plan implements helper functions for recording and verifying apply plans.
+package plan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestRecord(t *testing.T) {
+	type ops struct {
+		Create bool     `json:"create"`
+		Delete []string `json:"delete,omitempty"`
+	}
+
+	remote := map[string]string{"retention.ms": "86400000"}
+	fingerprint, err := Fingerprint(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  interface{}
+		ops     interface{}
+		planned *res.PlannedApply
+		wantErr string
+	}{
+		{
+			name:    "Tests recording without a plan",
+			remote:  remote,
+			ops:     ops{Create: true},
+			planned: nil,
+			wantErr: "",
+		},
+		{
+			name:   "Tests a plan with matching fingerprint and indented operations",
+			remote: remote,
+			ops:    ops{Delete: []string{"foo"}},
+			planned: &res.PlannedApply{
+				Fingerprint: fingerprint,
+				Operations:  json.RawMessage("{\n  \"create\": false,\n  \"delete\": [\n    \"foo\"\n  ]\n}"),
+			},
+			wantErr: "",
+		},
+		{
+			name:   "Tests a plan where the remote state has drifted",
+			remote: map[string]string{"retention.ms": "3600000"},
+			ops:    ops{Delete: []string{"foo"}},
+			planned: &res.PlannedApply{
+				Fingerprint: fingerprint,
+				Operations:  json.RawMessage(`{"create":false,"delete":["foo"]}`),
+			},
+			wantErr: "remote state has drifted since the plan was created",
+		},
+		{
+			name:   "Tests a plan where the operations differ",
+			remote: remote,
+			ops:    ops{Create: true},
+			planned: &res.PlannedApply{
+				Fingerprint: fingerprint,
+				Operations:  json.RawMessage(`{"create":false,"delete":["foo"]}`),
+			},
+			wantErr: "operations differ from the plan",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result res.ApplyResult
+			err := Record(&result, tt.remote, tt.ops, tt.planned)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Record() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(result.Fingerprint) == 0 || result.Operations == nil {
+				t.Errorf("Record() did not record the fingerprint and operations")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package render implements the rendering of definitions with variables and templates.
+package render
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the functions available to templates.
+var templateFuncs = template.FuncMap{
+	"env":      os.Getenv,
+	"default":  defaultValue,
+	"duration": durationMs,
+	"bytes":    byteSize,
+}
+
+var (
+	durationRegExp = regexp.MustCompile(`^([0-9]+)(w|d)$`)
+	byteSizeRegExp = regexp.MustCompile(`^([0-9]+)\s*([KMGTP]i?B|B)?$`)
+)
+
+var byteUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"KB":  1000,
+	"MB":  1000 * 1000,
+	"GB":  1000 * 1000 * 1000,
+	"TB":  1000 * 1000 * 1000 * 1000,
+	"PB":  1000 * 1000 * 1000 * 1000 * 1000,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+}
+
+// defaultValue returns a value, or a default if the value is empty.
+// The default is the first argument so the value can be piped, e.g. {{ env "RETENTION" | default "604800000" }}.
+func defaultValue(def string, value string) string {
+	if len(value) == 0 {
+		return def
+	}
+	return value
+}
+
+// durationMs returns the milliseconds of a duration, e.g. "7d" or "36h".
+// Durations are in the format of Go durations, or a number of days (d) or weeks (w).
+func durationMs(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if m := durationRegExp.FindStringSubmatch(s); m != nil {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		days := n
+		if m[2] == "w" {
+			days = n * 7
+		}
+		return (time.Duration(days) * 24 * time.Hour).Milliseconds(), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d.Milliseconds(), nil
+}
+
+// byteSize returns the bytes of a size, e.g. "1GiB" or "500MB".
+func byteSize(s string) (int64, error) {
+	m := byteSizeRegExp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	return n * byteUnits[m[2]], nil
+}
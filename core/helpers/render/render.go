@@ -0,0 +1,65 @@
+// Package render implements the rendering of definitions with variables and templates.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// Options represents options to configure rendering.
+type Options struct {
+	// Variables referenced by definitions. Variables take precedence over environment variables of the same name.
+	Vars map[string]string
+	// Execute definitions as templates before substituting variables.
+	Template bool
+	// Substitute references to variables with their values.
+	// Content containing references is left unchanged if false.
+	Substitute bool
+}
+
+// Render renders definition content.
+// If enabled, the content is executed as a template with the variables as data.
+// If enabled, references to variables are then substituted with their values.
+func Render(content []byte, opts Options) ([]byte, error) {
+	if opts.Template {
+		var err error
+		if content, err = executeTemplate(content, opts.Vars); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.Substitute {
+		return content, nil
+	}
+
+	return Substitute(content, func(name string) (string, bool) {
+		if v, ok := opts.Vars[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	})
+}
+
+// executeTemplate executes content as a template.
+func executeTemplate(content []byte, vars map[string]string) ([]byte, error) {
+	tmpl, err := template.New("definitions").
+		Option("missingkey=error").
+		Funcs(templateFuncs).
+		Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	data := vars
+	if data == nil {
+		data = map[string]string{}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+// Package render implements the rendering of definitions with variables and templates.
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    Options
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "Tests substituting variables",
+			content: "partitions: ${PARTITIONS}\nreplicationFactor: ${KDEF_TEST_RF}\n",
+			opts:    Options{Vars: map[string]string{"PARTITIONS": "6"}, Substitute: true},
+			want:    "partitions: 6\nreplicationFactor: 3\n",
+		},
+		{
+			name:    "Tests variables taking precedence over environment variables",
+			content: "replicationFactor: ${KDEF_TEST_RF}",
+			opts:    Options{Vars: map[string]string{"KDEF_TEST_RF": "2"}, Substitute: true},
+			want:    "replicationFactor: 2",
+		},
+		{
+			name:    "Tests default values",
+			content: "partitions: ${PARTITIONS:-3}\nname: ${EMPTY:-foo}\nrf: ${KDEF_TEST_RF:-1}",
+			opts:    Options{Vars: map[string]string{"EMPTY": ""}, Substitute: true},
+			want:    "partitions: 3\nname: foo\nrf: 3",
+		},
+		{
+			name:    "Tests escaped references",
+			content: "regex: \"$${FOO}\" cost: $5",
+			opts:    Options{Substitute: true},
+			want:    "regex: \"${FOO}\" cost: $5",
+		},
+		{
+			name:    "Tests an unset variable",
+			content: "a: 1\npartitions: ${PARTITIONS}",
+			opts:    Options{Substitute: true},
+			wantErr: "line 2: variable \"PARTITIONS\" is not set",
+		},
+		{
+			name:    "Tests an unterminated reference",
+			content: "partitions: ${PARTITIONS\nreplicationFactor: 3}",
+			opts:    Options{Substitute: true},
+			wantErr: "line 1: unterminated variable reference",
+		},
+		{
+			name:    "Tests an invalid variable name",
+			content: "partitions: ${PARTITIONS-3}",
+			opts:    Options{Substitute: true},
+			wantErr: "line 1: invalid variable name \"PARTITIONS-3\"",
+		},
+		{
+			name: "Tests executing a template",
+			content: `partitions: {{ .partitions }}
+retention.ms: "{{ duration "7d" }}"
+segment.bytes: "{{ bytes "1GiB" }}"
+cleanup.policy: {{ env "KDEF_TEST_POLICY" | default "delete" }}
+replicationFactor: ${KDEF_TEST_RF}`,
+			opts: Options{
+				Vars:       map[string]string{"partitions": "12"},
+				Template:   true,
+				Substitute: true,
+			},
+			want: `partitions: 12
+retention.ms: "604800000"
+segment.bytes: "1073741824"
+cleanup.policy: delete
+replicationFactor: 3`,
+		},
+		{
+			name:    "Tests a template referencing a missing variable",
+			content: "partitions: {{ .partitions }}",
+			opts:    Options{Template: true},
+			wantErr: "map has no entry for key \"partitions\"",
+		},
+		{
+			name:    "Tests a template with an invalid duration",
+			content: "retention.ms: {{ duration \"7 days\" }}",
+			opts:    Options{Template: true},
+			wantErr: "invalid duration \"7 days\"",
+		},
+		{
+			name:    "Tests content that is not substituted",
+			content: "# ${NOT_SET}\nregex: \"^${prefix}\\\\..*\"",
+			want:    "# ${NOT_SET}\nregex: \"^${prefix}\\\\..*\"",
+		},
+		{
+			name:    "Tests content that is not executed as a template",
+			content: "partitions: {{ .partitions }}",
+			want:    "partitions: {{ .partitions }}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KDEF_TEST_RF", "3")
+			got, err := Render([]byte(tt.content), tt.opts)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "Tests parsing scalar values",
+			vars: `
+partitions: 12
+retentionMs: 2592000000
+compacted: true
+ENV_NAME: prod
+empty:
+`,
+			want: map[string]string{
+				"partitions":  "12",
+				"retentionMs": "2592000000",
+				"compacted":   "true",
+				"ENV_NAME":    "prod",
+				"empty":       "",
+			},
+		},
+		{
+			name:    "Tests a non-scalar value",
+			vars:    "configs:\n  retention.ms: 1000\n",
+			wantErr: "variable \"configs\" must have a scalar value",
+		},
+		{
+			name:    "Tests an invalid variable name",
+			vars:    "retention.ms: 1000\n",
+			wantErr: "invalid variable name \"retention.ms\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVars([]byte(tt.vars))
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("parseVars() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package render implements the rendering of definitions with variables and templates.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var variableNameRegExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Substitute substitutes references to variables in content with their values.
+// A reference of the form ${NAME} is substituted with the value of the variable, which must be set.
+// A reference of the form ${NAME:-default} is substituted with the default if the variable is unset or empty.
+// The sequence $${ is substituted with a literal ${.
+func Substitute(content []byte, lookup func(name string) (string, bool)) ([]byte, error) {
+	var buf bytes.Buffer
+	line := 1
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c == '\n' {
+			line++
+		}
+		if c != '$' {
+			buf.WriteByte(c)
+			continue
+		}
+
+		if bytes.HasPrefix(content[i:], []byte("$${")) {
+			buf.WriteString("${")
+			i += 2
+			continue
+		}
+		if !bytes.HasPrefix(content[i:], []byte("${")) {
+			buf.WriteByte(c)
+			continue
+		}
+
+		end := bytes.IndexAny(content[i:], "}\n")
+		if end < 0 || content[i+end] != '}' {
+			return nil, fmt.Errorf("line %d: unterminated variable reference", line)
+		}
+		value, err := resolve(string(content[i+2:i+end]), lookup)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		buf.WriteString(value)
+		i += end
+	}
+	return buf.Bytes(), nil
+}
+
+// resolve resolves the value of the expression of a variable reference.
+func resolve(expr string, lookup func(name string) (string, bool)) (string, error) {
+	name, fallback, hasDefault := strings.Cut(expr, ":-")
+	if !variableNameRegExp.MatchString(name) {
+		return "", fmt.Errorf("invalid variable name %q", name)
+	}
+
+	value, ok := lookup(name)
+	if hasDefault && len(value) == 0 {
+		return fallback, nil
+	}
+	if !ok {
+		return "", fmt.Errorf("variable %q is not set", name)
+	}
+	return value, nil
+}
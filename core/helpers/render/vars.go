@@ -0,0 +1,58 @@
+// Package render implements the rendering of definitions with variables and templates.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+// LoadVars loads variables from a YAML or JSON file containing a map of names to scalar values.
+func LoadVars(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vars file: %v", err)
+	}
+	vars, err := parseVars(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vars file %q: %v", path, err)
+	}
+	return vars, nil
+}
+
+// parseVars parses YAML or JSON variables.
+func parseVars(b []byte) (map[string]string, error) {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	// Decoding numbers as json.Number preserves their original representation.
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string, len(values))
+	for name, value := range values {
+		if !variableNameRegExp.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable name %q", name)
+		}
+		switch v := value.(type) {
+		case string:
+			vars[name] = v
+		case json.Number, bool:
+			vars[name] = fmt.Sprint(v)
+		case nil:
+			vars[name] = ""
+		default:
+			return nil, fmt.Errorf("variable %q must have a scalar value", name)
+		}
+	}
+	return vars, nil
+}
@@ -0,0 +1,65 @@
+// Package scram implements helper functions for handling SCRAM credentials.
+package scram
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+// saltLength is the length in bytes of generated salts.
+const saltLength = 32
+
+// ResolvePassword resolves a password from its reference.
+func ResolvePassword(ref *def.PasswordRefDefinition) (string, error) {
+	if ref == nil {
+		return "", fmt.Errorf("password reference is missing")
+	}
+
+	if len(ref.FromEnv) > 0 {
+		password, ok := os.LookupEnv(ref.FromEnv)
+		if !ok || len(password) == 0 {
+			return "", fmt.Errorf("password environment variable %q is not set", ref.FromEnv)
+		}
+		return password, nil
+	}
+
+	b, err := os.ReadFile(ref.FromFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %v", err)
+	}
+	// Trailing newlines are commonly added by editors and secret mounts.
+	password := strings.TrimRight(string(b), "\r\n")
+	if len(password) == 0 {
+		return "", fmt.Errorf("password file %q is empty", ref.FromFile)
+	}
+
+	return password, nil
+}
+
+// NewSalt generates a random salt.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// SaltedPassword computes the SCRAM salted password for a mechanism (RFC 5802).
+func SaltedPassword(mechanism string, password string, salt []byte, iterations int) ([]byte, error) {
+	switch mechanism {
+	case def.SCRAMSHA256:
+		return pbkdf2.Key(sha256.New, password, salt, iterations, sha256.Size)
+	case def.SCRAMSHA512:
+		return pbkdf2.Key(sha512.New, password, salt, iterations, sha512.Size)
+	default:
+		return nil, fmt.Errorf("unsupported scram mechanism %q", mechanism)
+	}
+}
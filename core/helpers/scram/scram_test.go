@@ -0,0 +1,131 @@
+// Package scram implements helper functions for handling SCRAM credentials.
+package scram
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestSaltedPassword(t *testing.T) {
+	rfcSalt, _ := base64.StdEncoding.DecodeString("W22ZaJ0SNY7soEsUEjb6gQ==")
+
+	type args struct {
+		mechanism  string
+		password   string
+		salt       []byte
+		iterations int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Tests SCRAM-SHA-256 salted password (RFC 7677 example)",
+			args: args{
+				mechanism:  def.SCRAMSHA256,
+				password:   "pencil",
+				salt:       rfcSalt,
+				iterations: 4096,
+			},
+			want:    "c4a49510323ab4f952cac1fa99441939e78ea74d6be81ddf7096e87513dc615d",
+			wantErr: "",
+		},
+		{
+			name: "Tests SCRAM-SHA-512 salted password",
+			args: args{
+				mechanism:  def.SCRAMSHA512,
+				password:   "pencil",
+				salt:       []byte("salt"),
+				iterations: 4096,
+			},
+			want: "2cfe3a1c151662b1ea49d13f595674a1c666add70df15d3d02254e9905993878" +
+				"261da7407fd11c2fee4b0a30df5154b1a752f86a13380ddd4bdd9a7c958ec769",
+			wantErr: "",
+		},
+		{
+			name: "Tests unsupported mechanism",
+			args: args{
+				mechanism:  "SCRAM-SHA-1",
+				password:   "pencil",
+				salt:       []byte("salt"),
+				iterations: 4096,
+			},
+			want:    "",
+			wantErr: "unsupported scram mechanism",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SaltedPassword(tt.args.mechanism, tt.args.password, tt.args.salt, tt.args.iterations)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("SaltedPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("SaltedPassword() = %x, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePassword(t *testing.T) {
+	t.Setenv("KDEF_TEST_PASSWORD", "foo")
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("bar\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ref     *def.PasswordRefDefinition
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "Tests resolving a password from an environment variable",
+			ref:     &def.PasswordRefDefinition{FromEnv: "KDEF_TEST_PASSWORD"},
+			want:    "foo",
+			wantErr: "",
+		},
+		{
+			name:    "Tests resolving a password from an unset environment variable",
+			ref:     &def.PasswordRefDefinition{FromEnv: "KDEF_TEST_PASSWORD_UNSET"},
+			want:    "",
+			wantErr: "password environment variable \"KDEF_TEST_PASSWORD_UNSET\" is not set",
+		},
+		{
+			name:    "Tests resolving a password from a file",
+			ref:     &def.PasswordRefDefinition{FromFile: passwordFile},
+			want:    "bar",
+			wantErr: "",
+		},
+		{
+			name:    "Tests resolving a password from a missing file",
+			ref:     &def.PasswordRefDefinition{FromFile: filepath.Join(dir, "missing")},
+			want:    "",
+			wantErr: "failed to read password file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePassword(tt.ref)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("ResolvePassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
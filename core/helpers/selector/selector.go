@@ -0,0 +1,128 @@
+// Package selector implements label selectors for resource definitions.
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var labelKeyRegExp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+// Operator represents the operator of a selector requirement.
+type Operator string
+
+// Selector requirement operators.
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement represents a requirement of a label selector.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Value    string
+}
+
+// Matches returns true if labels satisfy the requirement.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case Equals:
+		return ok && value == r.Value
+	case NotEquals:
+		return !ok || value != r.Value
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// String returns the requirement in selector syntax.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	}
+	return r.Key + string(r.Operator) + r.Value
+}
+
+// Selector represents a label selector, a set of requirements that must all be satisfied.
+type Selector []Requirement
+
+// Parse parses a label selector of comma-separated requirements.
+// Requirements are of the form key=value, key==value, key!=value, key (exists) or !key (does not exist).
+func Parse(s string) (Selector, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, fmt.Errorf("selector must not be empty")
+	}
+
+	var selector Selector
+	for _, term := range strings.Split(s, ",") {
+		r, err := parseRequirement(strings.TrimSpace(term))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %v", s, err)
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// parseRequirement parses a requirement of a label selector.
+func parseRequirement(term string) (Requirement, error) {
+	var r Requirement
+	switch {
+	case strings.Contains(term, "!="):
+		key, value, _ := strings.Cut(term, "!=")
+		r = Requirement{Key: key, Operator: NotEquals, Value: value}
+	case strings.Contains(term, "=="):
+		key, value, _ := strings.Cut(term, "==")
+		r = Requirement{Key: key, Operator: Equals, Value: value}
+	case strings.Contains(term, "="):
+		key, value, _ := strings.Cut(term, "=")
+		r = Requirement{Key: key, Operator: Equals, Value: value}
+	case strings.HasPrefix(term, "!"):
+		r = Requirement{Key: strings.TrimPrefix(term, "!"), Operator: DoesNotExist}
+	default:
+		r = Requirement{Key: term, Operator: Exists}
+	}
+
+	r.Key = strings.TrimSpace(r.Key)
+	r.Value = strings.TrimSpace(r.Value)
+	if len(r.Key) == 0 {
+		return r, fmt.Errorf("requirement %q must have a label key", term)
+	}
+	if !labelKeyRegExp.MatchString(r.Key) {
+		return r, fmt.Errorf("invalid label key %q", r.Key)
+	}
+	if strings.ContainsAny(r.Value, "=!") {
+		return r, fmt.Errorf("invalid label value %q", r.Value)
+	}
+	return r, nil
+}
+
+// Matches returns true if labels satisfy all requirements of the selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in selector syntax.
+func (s Selector) String() string {
+	terms := make([]string, len(s))
+	for i, r := range s {
+		terms[i] = r.String()
+	}
+	return strings.Join(terms, ",")
+}
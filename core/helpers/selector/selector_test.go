@@ -0,0 +1,114 @@
+// Package selector implements label selectors for resource definitions.
+package selector
+
+import (
+	"testing"
+
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Tests parsing all operators",
+			s:    "team=payments, tier!=test,env==prod,critical,!deprecated",
+			want: "team=payments,tier!=test,env=prod,critical,!deprecated",
+		},
+		{
+			name: "Tests a prefixed key and an empty value",
+			s:    "example.com/owner=",
+			want: "example.com/owner=",
+		},
+		{
+			name:    "Tests an empty selector",
+			s:       " ",
+			wantErr: "selector must not be empty",
+		},
+		{
+			name:    "Tests a requirement without a key",
+			s:       "team=payments,=test",
+			wantErr: "invalid selector \"team=payments,=test\": requirement \"=test\" must have a label key",
+		},
+		{
+			name:    "Tests an invalid key",
+			s:       "team payments",
+			wantErr: "invalid label key \"team payments\"",
+		},
+		{
+			name:    "Tests an invalid value",
+			s:       "team=payments=core",
+			wantErr: "invalid label value \"payments=core\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{
+		"team": "payments",
+		"tier": "critical",
+	}
+	tests := []struct {
+		name     string
+		selector string
+		want     bool
+	}{
+		{
+			name:     "Tests matching equality",
+			selector: "team=payments,tier==critical",
+			want:     true,
+		},
+		{
+			name:     "Tests not matching equality",
+			selector: "team=payments,tier=test",
+			want:     false,
+		},
+		{
+			name:     "Tests matching inequality of a missing label",
+			selector: "env!=prod",
+			want:     true,
+		},
+		{
+			name:     "Tests not matching inequality",
+			selector: "tier!=critical",
+			want:     false,
+		},
+		{
+			name:     "Tests matching existence",
+			selector: "team,!deprecated",
+			want:     true,
+		},
+		{
+			name:     "Tests not matching existence",
+			selector: "!team",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Matches(labels); got != tt.want {
+				t.Errorf("Selector.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
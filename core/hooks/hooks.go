@@ -0,0 +1,205 @@
+// Package hooks implements hooks that run commands and call webhooks on apply events.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// Hook points that run with the apply result of a definition.
+const (
+	// PreApply hooks run before the pending operations of a definition are executed.
+	PreApply = "preApply"
+	// PostApply hooks run when the apply of a definition has completed.
+	PostApply = "postApply"
+)
+
+// OnValidValues is the list of valid values for the hook point of a hook.
+var OnValidValues = append([]string{PreApply, PostApply}, event.TypeValidValues...)
+
+const defaultTimeoutMs = 30000
+
+// Validate validates hook configurations.
+func Validate(configs []client.HookConfig) error {
+	for i, c := range configs {
+		if !str.Contains(c.On, OnValidValues) {
+			return fmt.Errorf("hooks[%d].on must be one of %q", i, strings.Join(OnValidValues, "|"))
+		}
+		if (len(c.Command) > 0) == (len(c.URL) > 0) {
+			return fmt.Errorf("hooks[%d] must specify one of command or url", i)
+		}
+		if len(c.URL) > 0 {
+			if err := checkURL(c.URL); err != nil {
+				return fmt.Errorf("hooks[%d].url %v", i, err)
+			}
+		}
+		if c.TimeoutMs < 0 {
+			return fmt.Errorf("hooks[%d].timeoutMs must be greater or equal to 0", i)
+		}
+	}
+	return nil
+}
+
+// checkURL checks that a webhook URL is an http or https URL with a loopback host.
+// Payloads may contain the configuration of resources, so are not sent off the host.
+func checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("is invalid: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("must have scheme http or https")
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("must have a loopback host (localhost, 127.0.0.0/8 or ::1)")
+}
+
+// Runner represents an event handler that runs hooks.
+type Runner struct {
+	configs []client.HookConfig
+	log     *log.Logger
+	client  *http.Client
+}
+
+// NewRunner creates a runner of hooks.
+// Hook failures are logged as warnings and do not fail the apply.
+func NewRunner(configs []client.HookConfig, logger *log.Logger) *Runner {
+	return &Runner{
+		configs: configs,
+		log:     logger,
+		client: &http.Client{
+			// Redirects are not followed so requests cannot be sent off the host.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Handle runs the hooks of an event.
+func (r *Runner) Handle(ctx context.Context, e event.Event) {
+	for _, c := range r.configs {
+		payload, ok := r.payload(c.On, e)
+		if !ok {
+			continue
+		}
+		if err := r.run(ctx, c, e, payload); err != nil {
+			r.log.Warnf("%s hook for %s definition %q failed: %v", c.On, e.Kind, e.Name, err)
+		}
+	}
+}
+
+// payload determines if a hook runs on an event and returns its payload.
+// Hook points receive the apply result and event types receive the event.
+func (r *Runner) payload(on string, e event.Event) ([]byte, bool) {
+	var v interface{}
+	switch on {
+	case PreApply:
+		// Run only if there are pending operations to execute.
+		if e.Type != event.TypeOpsPlanned || len(e.Result.Diff) == 0 {
+			return nil, false
+		}
+		v = e.Result
+	case PostApply:
+		if e.Type != event.TypeCompleted {
+			return nil, false
+		}
+		v = e.Result
+	default:
+		if on != string(e.Type) {
+			return nil, false
+		}
+		v = e
+	}
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		r.log.Warnf("failed to marshal %s hook payload: %v", on, err)
+		return nil, false
+	}
+	return payload, true
+}
+
+// run runs a hook with a payload.
+func (r *Runner) run(ctx context.Context, c client.HookConfig, e event.Event, payload []byte) error {
+	timeoutMs := c.TimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = defaultTimeoutMs
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+
+	if len(c.Command) > 0 {
+		return r.runCommand(ctx, c, e, payload)
+	}
+	return r.callWebhook(ctx, c, e, payload)
+}
+
+// runCommand runs a hook command with the payload on stdin.
+func (r *Runner) runCommand(ctx context.Context, c client.HookConfig, e event.Event, payload []byte) error {
+	r.log.Debugf("Running %s hook command %q", c.On, c.Command[0])
+
+	//nolint:gosec // the command is from trusted configuration
+	cmd := exec.CommandContext(ctx, c.Command[0], c.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"KDEF_HOOK="+c.On,
+		"KDEF_KIND="+e.Kind,
+		"KDEF_NAME="+e.Name,
+		"KDEF_DRY_RUN="+strconv.FormatBool(e.DryRun),
+	)
+
+	out, err := cmd.CombinedOutput()
+	if len(out) > 0 {
+		r.log.Debugf("%s", strings.TrimRight(string(out), "\n"))
+	}
+	return err
+}
+
+// callWebhook sends the payload to a hook URL in a POST request.
+func (r *Runner) callWebhook(ctx context.Context, c client.HookConfig, e event.Event, payload []byte) error {
+	if err := checkURL(c.URL); err != nil {
+		return fmt.Errorf("url %v", err)
+	}
+	r.log.Debugf("Calling %s hook webhook %q", c.On, c.URL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Kdef-Hook", c.On)
+	req.Header.Set("X-Kdef-Dry-Run", strconv.FormatBool(e.DryRun))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// Package hooks implements hooks that run commands and call webhooks on apply events.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []client.HookConfig
+		wantErr string
+	}{
+		{
+			name: "Tests valid hooks",
+			configs: []client.HookConfig{
+				{On: PreApply, Command: []string{"true"}},
+				{On: "reassignmentProgress", URL: "http://localhost:9000", TimeoutMs: 1000},
+			},
+			wantErr: "",
+		},
+		{
+			name: "Tests an invalid hook point",
+			configs: []client.HookConfig{
+				{On: "foo", Command: []string{"true"}},
+			},
+			wantErr: "hooks[0].on must be one of",
+		},
+		{
+			name: "Tests a hook with both command and url",
+			configs: []client.HookConfig{
+				{On: PostApply, Command: []string{"true"}, URL: "http://localhost:9000"},
+			},
+			wantErr: "hooks[0] must specify one of command or url",
+		},
+		{
+			name: "Tests a hook with neither command nor url",
+			configs: []client.HookConfig{
+				{On: PostApply},
+			},
+			wantErr: "hooks[0] must specify one of command or url",
+		},
+		{
+			name: "Tests a webhook with a loopback ip address",
+			configs: []client.HookConfig{
+				{On: PostApply, URL: "https://127.0.0.1:9000/events"},
+				{On: PostApply, URL: "http://[::1]:9000/events"},
+			},
+			wantErr: "",
+		},
+		{
+			name: "Tests a webhook with a remote host",
+			configs: []client.HookConfig{
+				{On: PostApply, URL: "https://hooks.example.com/events"},
+			},
+			wantErr: "hooks[0].url must have a loopback host",
+		},
+		{
+			name: "Tests a webhook with a remote ip address",
+			configs: []client.HookConfig{
+				{On: PostApply, URL: "http://10.0.0.1:9000"},
+			},
+			wantErr: "hooks[0].url must have a loopback host",
+		},
+		{
+			name: "Tests a webhook with an unsupported scheme",
+			configs: []client.HookConfig{
+				{On: PostApply, URL: "file:///etc/passwd"},
+			},
+			wantErr: "hooks[0].url must have scheme http or https",
+		},
+		{
+			name: "Tests a negative timeout",
+			configs: []client.HookConfig{
+				{On: PostApply, Command: []string{"true"}, TimeoutMs: -1},
+			},
+			wantErr: "hooks[0].timeoutMs must be greater or equal to 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.configs); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunner_command(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	r := NewRunner([]client.HookConfig{
+		{On: PreApply, Command: []string{"sh", "-c", `cat > "$0"; echo "$KDEF_HOOK $KDEF_KIND $KDEF_NAME $KDEF_DRY_RUN" >> "$0"`, out}},
+	}, nil)
+
+	ctx := context.Background()
+	e := event.Event{
+		Type:   event.TypeOpsPlanned,
+		Kind:   "topic",
+		Name:   "foo",
+		DryRun: true,
+	}
+
+	// The hook does not run if there are no pending operations.
+	e.Result = &res.ApplyResult{}
+	r.Handle(ctx, e)
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatalf("hook ran without pending operations")
+	}
+
+	e.Result = &res.ApplyResult{Diff: "diff"}
+	r.Handle(ctx, e)
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"local":null,"remote":null,"data":null,"diff":"diff","error":"","applied":false}` + "preApply topic foo true\n"
+	if string(got) != want {
+		t.Errorf("hook output = %q, want %q", got, want)
+	}
+}
+
+func TestRunner_webhook(t *testing.T) {
+	var got event.Event
+	var hook string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hook = r.Header.Get("X-Kdef-Hook")
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("failed to unmarshal payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewRunner([]client.HookConfig{
+		{On: string(event.TypeOpFailed), URL: srv.URL},
+	}, nil)
+
+	ctx := context.Background()
+	r.Handle(ctx, event.Event{Type: event.TypeOpExecuted, Kind: "topic", Name: "foo", Op: "createTopic"})
+	if len(hook) > 0 {
+		t.Fatalf("hook called for an event of another type")
+	}
+
+	r.Handle(ctx, event.Event{Type: event.TypeOpFailed, Kind: "topic", Name: "foo", Op: "createTopic", Err: "failed"})
+	if hook != string(event.TypeOpFailed) {
+		t.Errorf("X-Kdef-Hook = %q, want %q", hook, event.TypeOpFailed)
+	}
+	if got.Type != event.TypeOpFailed || got.Op != "createTopic" || got.Err != "failed" {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestRunner_webhookRedirect(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	r := NewRunner(nil, nil)
+	c := client.HookConfig{On: PostApply, URL: srv.URL}
+	err := r.callWebhook(context.Background(), c, event.Event{}, []byte("{}"))
+	if !tutil.ErrorContains(err, "webhook responded with status 307") {
+		t.Errorf("callWebhook() error = %v, wantErr %v", err, "webhook responded with status 307")
+	}
+	if redirected {
+		t.Errorf("callWebhook() followed a redirect")
+	}
+}
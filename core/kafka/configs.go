@@ -4,9 +4,10 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"sort"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -20,9 +21,9 @@ const (
 
 // ConfigOperation represents an alter config operation.
 type ConfigOperation struct {
-	Name  string
-	Value *string
-	Op    int8 // 0: SET, 1: DELETE, 2: APPEND, 3: SUBTRACT.
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
+	Op    int8    `json:"op"` // 0: SET, 1: DELETE, 2: APPEND, 3: SUBTRACT.
 }
 
 // ConfigOperations represents a slice of ConfigOperation.
@@ -48,6 +49,13 @@ func (c ConfigOperations) ContainsOp(operation int8) bool {
 	return false
 }
 
+// Sort sorts by name.
+func (c ConfigOperations) Sort() {
+	sort.Slice(c, func(i, j int) bool {
+		return c[i].Name < c[j].Name
+	})
+}
+
 func newConfigOps(
 	localConfigs def.ConfigsMap,
 	remoteConfigsMap def.ConfigsMap,
@@ -113,6 +121,9 @@ func newConfigOps(
 		}
 	}
 
+	// Sort to make operations deterministic.
+	configOps.Sort()
+
 	return configOps
 }
 
@@ -137,6 +148,27 @@ func describeBrokerConfigs(
 	return newConfigs(resp[0].Configs), nil
 }
 
+// describeBrokerLoggerConfigs executes a request to describe the loggers of a broker (Kafka 2.4.0+).
+func describeBrokerLoggerConfigs(
+	ctx context.Context,
+	cl *client.Client,
+	brokerID string,
+) (def.Configs, error) {
+	req := kmsg.NewDescribeConfigsRequest()
+
+	res := kmsg.NewDescribeConfigsRequestResource()
+	res.ResourceType = kmsg.ConfigResourceTypeBrokerLogger
+	res.ResourceName = brokerID
+	req.Resources = append(req.Resources, res)
+
+	resp, err := describeConfigs(ctx, cl, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfigs(resp[0].Configs), nil
+}
+
 // ResourceConfigs represents configs for a named resource.
 type ResourceConfigs struct {
 	ResourceName string
@@ -362,6 +394,27 @@ func incrementalAlterTopicConfigs(
 	)
 }
 
+// incrementalAlterBrokerLoggerConfigs executes a request to perform an incremental alter of broker loggers (Kafka 2.4.0+).
+func incrementalAlterBrokerLoggerConfigs(
+	ctx context.Context,
+	cl *client.Client,
+	brokerID string,
+	configOps ConfigOperations,
+	validateOnly bool,
+) error {
+	reqR := kmsg.NewIncrementalAlterConfigsRequestResource()
+	reqR.ResourceType = kmsg.ConfigResourceTypeBrokerLogger
+	reqR.ResourceName = brokerID
+	reqR.Configs = buildIncrementalAlterConfigsResourceConfig(configOps)
+
+	return incrementalAlterConfigs(
+		ctx,
+		cl,
+		[]kmsg.IncrementalAlterConfigsRequestResource{reqR},
+		validateOnly,
+	)
+}
+
 func buildIncrementalAlterConfigsResourceConfig(
 	configOps ConfigOperations,
 ) []kmsg.IncrementalAlterConfigsRequestResourceConfig {
@@ -0,0 +1,223 @@
+// Package kafka implements the Kafka service handling requests and responses.
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Special timestamps used to list the earliest and latest offsets of partitions.
+const (
+	ListOffsetsEarliest int64 = -2
+	ListOffsetsLatest   int64 = -1
+)
+
+// PartitionOffsets represents a map of topics to partition values used to request and list offsets.
+type PartitionOffsets map[string]map[int32]int64
+
+// Set sets the value for a topic partition.
+func (p PartitionOffsets) Set(topic string, partition int32, value int64) {
+	if _, ok := p[topic]; !ok {
+		p[topic] = map[int32]int64{}
+	}
+	p[topic][partition] = value
+}
+
+// GroupDescription represents the description of a consumer group.
+type GroupDescription struct {
+	Group   string
+	State   string
+	Members int
+}
+
+// describeGroup executes a request to describe a consumer group (Kafka 0.9.0+).
+func describeGroup(
+	ctx context.Context,
+	cl *client.Client,
+	group string,
+) (GroupDescription, error) {
+	req := kmsg.NewDescribeGroupsRequest()
+	req.Groups = []string{group}
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return GroupDescription{}, err
+	}
+	resp := kresp.(*kmsg.DescribeGroupsResponse)
+
+	if len(resp.Groups) != 1 {
+		return GroupDescription{}, fmt.Errorf("requested %d group(s) but received %d", 1, len(resp.Groups))
+	}
+
+	g := resp.Groups[0]
+	if err := kerr.ErrorForCode(g.ErrorCode); err != nil {
+		errMsg := err.Error()
+		if g.ErrorMessage != nil {
+			errMsg = fmt.Sprintf("%s: %s", errMsg, *g.ErrorMessage)
+		}
+		return GroupDescription{}, fmt.Errorf("%s", errMsg)
+	}
+
+	return GroupDescription{
+		Group:   g.Group,
+		State:   g.State,
+		Members: len(g.Members),
+	}, nil
+}
+
+// listGroups executes a request to list the consumer groups of the cluster (Kafka 0.9.0+).
+func listGroups(
+	ctx context.Context,
+	cl *client.Client,
+) ([]string, error) {
+	req := kmsg.NewListGroupsRequest()
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	resp := kresp.(*kmsg.ListGroupsResponse)
+
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, len(resp.Groups))
+	for i, g := range resp.Groups {
+		groups[i] = g.Group
+	}
+
+	return groups, nil
+}
+
+// fetchCommittedOffsets executes a request to fetch the committed offsets of a consumer group (Kafka 0.10.2+).
+func fetchCommittedOffsets(
+	ctx context.Context,
+	cl *client.Client,
+	group string,
+) (def.ConsumerGroupTopics, error) {
+	// A nil set of topics fetches all committed offsets.
+	req := kmsg.NewOffsetFetchRequest()
+	req.Group = group
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	resp := kresp.(*kmsg.OffsetFetchResponse)
+
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
+
+	var topics def.ConsumerGroupTopics
+	for _, t := range resp.Topics {
+		var partitions []def.ConsumerGroupPartitionDefinition
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return nil, fmt.Errorf("topic %q partition %d: %v", t.Topic, p.Partition, err)
+			}
+			// An offset of -1 indicates that no offset is committed.
+			if p.Offset < 0 {
+				continue
+			}
+			offset := p.Offset
+			partitions = append(partitions, def.ConsumerGroupPartitionDefinition{
+				Partition: p.Partition,
+				Offset:    &offset,
+			})
+		}
+		if len(partitions) == 0 {
+			continue
+		}
+		topics = append(topics, def.ConsumerGroupTopicDefinition{
+			Name:       t.Topic,
+			Partitions: partitions,
+		})
+	}
+	topics.Sort()
+
+	return topics, nil
+}
+
+// listOffsets executes a request to list the offsets of partitions for the supplied timestamps (Kafka 0.10.1+).
+func listOffsets(
+	ctx context.Context,
+	cl *client.Client,
+	timestamps PartitionOffsets,
+) (PartitionOffsets, error) {
+	req := kmsg.NewListOffsetsRequest()
+	for topic, partitions := range timestamps {
+		reqTopic := kmsg.NewListOffsetsRequestTopic()
+		reqTopic.Topic = topic
+		for partition, timestamp := range partitions {
+			reqPartition := kmsg.NewListOffsetsRequestTopicPartition()
+			reqPartition.Partition = partition
+			reqPartition.Timestamp = timestamp
+			reqTopic.Partitions = append(reqTopic.Partitions, reqPartition)
+		}
+		req.Topics = append(req.Topics, reqTopic)
+	}
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	resp := kresp.(*kmsg.ListOffsetsResponse)
+
+	offsets := PartitionOffsets{}
+	for _, t := range resp.Topics {
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return nil, fmt.Errorf("topic %q partition %d: %v", t.Topic, p.Partition, err)
+			}
+			offsets.Set(t.Topic, p.Partition, p.Offset)
+		}
+	}
+
+	return offsets, nil
+}
+
+// commitOffsets executes a request to commit offsets for a consumer group (Kafka 0.9.0+).
+func commitOffsets(
+	ctx context.Context,
+	cl *client.Client,
+	group string,
+	offsets PartitionOffsets,
+) error {
+	// The default generation and member ID commit offsets as an admin for an empty group.
+	req := kmsg.NewOffsetCommitRequest()
+	req.Group = group
+	for topic, partitions := range offsets {
+		reqTopic := kmsg.NewOffsetCommitRequestTopic()
+		reqTopic.Topic = topic
+		for partition, offset := range partitions {
+			reqPartition := kmsg.NewOffsetCommitRequestTopicPartition()
+			reqPartition.Partition = partition
+			reqPartition.Offset = offset
+			reqTopic.Partitions = append(reqTopic.Partitions, reqPartition)
+		}
+		req.Topics = append(req.Topics, reqTopic)
+	}
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return err
+	}
+	resp := kresp.(*kmsg.OffsetCommitResponse)
+
+	for _, t := range resp.Topics {
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return fmt.Errorf("topic %q partition %d: %v", t.Topic, p.Partition, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -30,6 +30,25 @@ type TopicMetadata struct {
 	PartitionLeaders     def.PartitionLeaders
 	PartitionISR         def.PartitionAssignments
 	Exists               bool
+	Internal             bool
+}
+
+// describeClusterSnapshot executes a request for the metadata of all topics and creates a cluster snapshot (Kafka 0.8.0+).
+func describeClusterSnapshot(
+	ctx context.Context,
+	cl *client.Client,
+) (*meta.ClusterSnapshot, error) {
+	metadata, err := describeMetadata(ctx, cl, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	topicAssignments := make(map[string][][]int32, len(metadata.Topics))
+	for _, t := range metadata.Topics {
+		topicAssignments[t.Topic] = t.PartitionAssignments
+	}
+
+	return meta.NewClusterSnapshot(metadata.Brokers, topicAssignments), nil
 }
 
 // describeMetadata executes a request for metadata (Kafka 0.8.0+).
@@ -79,8 +98,9 @@ func describeMetadata(
 		}
 
 		tm := TopicMetadata{
-			Topic:  *t.Topic,
-			Exists: exists,
+			Topic:    *t.Topic,
+			Exists:   exists,
+			Internal: t.IsInternal,
 		}
 
 		if exists {
@@ -0,0 +1,220 @@
+// Package kafka implements the Kafka service handling requests and responses.
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Quota entity types.
+const (
+	quotaEntityUser     = "user"
+	quotaEntityClientID = "client-id"
+	quotaEntityIP       = "ip"
+)
+
+// QuotaOperation represents an alter client quota operation.
+type QuotaOperation struct {
+	Key    string  `json:"key"`
+	Value  float64 `json:"value,omitempty"`
+	Remove bool    `json:"remove,omitempty"`
+}
+
+// QuotaOperations represents a slice of QuotaOperation.
+type QuotaOperations []QuotaOperation
+
+// Contains determines if the specified quota key exists.
+func (q QuotaOperations) Contains(key string) bool {
+	for _, op := range q {
+		if op.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityQuotas represents the quotas of an entity.
+type EntityQuotas struct {
+	Entity def.QuotaEntityDefinition
+	Quotas def.QuotasMap
+}
+
+// entityComponent represents a component of a quota entity.
+type entityComponent struct {
+	entityType string
+	name       *string // nil for the default entity.
+}
+
+// entityComponents returns the components of a quota entity definition.
+func entityComponents(entity def.QuotaEntityDefinition) []entityComponent {
+	var components []entityComponent
+	add := func(entityType string, name string) {
+		if len(name) == 0 {
+			return
+		}
+		c := entityComponent{entityType: entityType}
+		if name != def.QuotaEntityDefault {
+			c.name = kmsg.StringPtr(name)
+		}
+		components = append(components, c)
+	}
+	add(quotaEntityUser, entity.User)
+	add(quotaEntityClientID, entity.ClientID)
+	add(quotaEntityIP, entity.IP)
+	return components
+}
+
+// describeClientQuotas executes a request to describe the quotas of a specific entity (Kafka 2.6.0+).
+func describeClientQuotas(
+	ctx context.Context,
+	cl *client.Client,
+	entity def.QuotaEntityDefinition,
+) (def.QuotasMap, error) {
+	req := kmsg.NewDescribeClientQuotasRequest()
+	req.Strict = true
+	for _, c := range entityComponents(entity) {
+		rc := kmsg.NewDescribeClientQuotasRequestComponent()
+		rc.EntityType = c.entityType
+		if c.name == nil {
+			rc.MatchType = kmsg.QuotasMatchTypeDefault
+		} else {
+			rc.MatchType = kmsg.QuotasMatchTypeExact
+			rc.Match = c.name
+		}
+		req.Components = append(req.Components, rc)
+	}
+
+	entityQuotas, err := requestClientQuotas(ctx, cl, req)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := def.QuotasMap{}
+	for _, eq := range entityQuotas {
+		if eq.Entity == entity {
+			quotas = eq.Quotas
+		}
+	}
+
+	return quotas, nil
+}
+
+// describeAllClientQuotas executes a request to describe the quotas of all entities (Kafka 2.6.0+).
+func describeAllClientQuotas(
+	ctx context.Context,
+	cl *client.Client,
+) ([]EntityQuotas, error) {
+	req := kmsg.NewDescribeClientQuotasRequest()
+	// An empty set of components with non-strict matching describes all entities.
+	req.Strict = false
+	req.Components = []kmsg.DescribeClientQuotasRequestComponent{}
+
+	return requestClientQuotas(ctx, cl, req)
+}
+
+// requestClientQuotas executes a request to describe client quotas (Kafka 2.6.0+).
+func requestClientQuotas(
+	ctx context.Context,
+	cl *client.Client,
+	req kmsg.DescribeClientQuotasRequest,
+) ([]EntityQuotas, error) {
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	resp := kresp.(*kmsg.DescribeClientQuotasResponse)
+
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		errMsg := err.Error()
+		if resp.ErrorMessage != nil {
+			errMsg = fmt.Sprintf("%s: %s", errMsg, *resp.ErrorMessage)
+		}
+		return nil, fmt.Errorf("%s", errMsg)
+	}
+
+	entityQuotas := make([]EntityQuotas, len(resp.Entries))
+	for i, entry := range resp.Entries {
+		var entity def.QuotaEntityDefinition
+		for _, e := range entry.Entity {
+			name := def.QuotaEntityDefault
+			if e.Name != nil {
+				name = *e.Name
+			}
+			switch e.Type {
+			case quotaEntityUser:
+				entity.User = name
+			case quotaEntityClientID:
+				entity.ClientID = name
+			case quotaEntityIP:
+				entity.IP = name
+			}
+		}
+
+		quotas := def.QuotasMap{}
+		for _, v := range entry.Values {
+			quotas[v.Key] = v.Value
+		}
+
+		entityQuotas[i] = EntityQuotas{
+			Entity: entity,
+			Quotas: quotas,
+		}
+	}
+
+	return entityQuotas, nil
+}
+
+// alterClientQuotas executes a request to alter the quotas of an entity (Kafka 2.6.0+).
+func alterClientQuotas(
+	ctx context.Context,
+	cl *client.Client,
+	entity def.QuotaEntityDefinition,
+	quotaOps QuotaOperations,
+	validateOnly bool,
+) error {
+	reqEntry := kmsg.NewAlterClientQuotasRequestEntry()
+	for _, c := range entityComponents(entity) {
+		e := kmsg.NewAlterClientQuotasRequestEntryEntity()
+		e.Type = c.entityType
+		e.Name = c.name
+		reqEntry.Entity = append(reqEntry.Entity, e)
+	}
+	for _, op := range quotaOps {
+		o := kmsg.NewAlterClientQuotasRequestEntryOp()
+		o.Key = op.Key
+		o.Value = op.Value
+		o.Remove = op.Remove
+		reqEntry.Ops = append(reqEntry.Ops, o)
+	}
+
+	req := kmsg.NewAlterClientQuotasRequest()
+	req.Entries = append(req.Entries, reqEntry)
+	req.ValidateOnly = validateOnly
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return err
+	}
+	resp := kresp.(*kmsg.AlterClientQuotasResponse)
+
+	if len(resp.Entries) != 1 {
+		return fmt.Errorf("requested %d entity(s) but received %d", 1, len(resp.Entries))
+	}
+
+	for _, entry := range resp.Entries {
+		if err := kerr.ErrorForCode(entry.ErrorCode); err != nil {
+			errMsg := err.Error()
+			if entry.ErrorMessage != nil {
+				errMsg = fmt.Sprintf("%s: %s", errMsg, *entry.ErrorMessage)
+			}
+			return fmt.Errorf("%s", errMsg)
+		}
+	}
+
+	return nil
+}
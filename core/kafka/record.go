@@ -0,0 +1,61 @@
+// Package kafka implements the Kafka service handling requests and responses.
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// produceRecord produces a record to a topic and awaits its acknowledgement.
+func produceRecord(
+	ctx context.Context,
+	cl *client.Client,
+	topic string,
+	key []byte,
+	value []byte,
+) error {
+	record := &kgo.Record{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+	}
+	return cl.Client.ProduceSync(ctx, record).FirstErr()
+}
+
+// fetchRecord fetches the record at an offset of a topic partition.
+func fetchRecord(
+	ctx context.Context,
+	cl *client.Client,
+	topic string,
+	partition int32,
+	offset int64,
+) (*kgo.Record, error) {
+	consumer, err := cl.NewConsumer(kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{
+		topic: {partition: kgo.NewOffset().At(offset)},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	for {
+		fetches := consumer.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if errs := fetches.Errors(); len(errs) > 0 {
+			return nil, fmt.Errorf("topic %q partition %d: %v", topic, partition, errs[0].Err)
+		}
+		for _, record := range fetches.Records() {
+			if record.Offset == offset {
+				return record, nil
+			}
+			if record.Offset > offset {
+				return nil, fmt.Errorf("topic %q partition %d: record at offset %d not found", topic, partition, offset)
+			}
+		}
+	}
+}
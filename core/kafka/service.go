@@ -4,11 +4,13 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"sync"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
@@ -22,12 +24,18 @@ func NewService(
 }
 
 // Service represents a Kafka service.
+// A Service is safe for concurrent use.
 type Service struct {
-	cl               *client.Client
+	cl *client.Client
+
+	mu               sync.Mutex
 	incrementalAlter *bool
 }
 
 func (s *Service) getIncrementalAlter(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.incrementalAlter == nil {
 		var ia bool
 		switch s.cl.AlterConfigsMethod() {
@@ -65,6 +73,11 @@ func (s *Service) DescribeMetadata(
 	return describeMetadata(ctx, s.cl, topics, errorOnNonExistence)
 }
 
+// DescribeClusterSnapshot executes a request for the metadata of all topics and creates a cluster snapshot (Kafka 0.8.0+).
+func (s *Service) DescribeClusterSnapshot(ctx context.Context) (*meta.ClusterSnapshot, error) {
+	return describeClusterSnapshot(ctx, s.cl)
+}
+
 // IsKafkaReady executes describe cluster requests until a minimum number of brokers are alive (Kafka 2.8.0+).
 func (s *Service) IsKafkaReady(ctx context.Context, minBrokers int, timeoutSec int) bool {
 	return isKafkaReady(ctx, s.cl, minBrokers, timeoutSec)
@@ -152,6 +165,21 @@ func (s *Service) AlterTopicConfigs(
 	return alterTopicConfigs(ctx, s.cl, topic, configOps, validateOnly)
 }
 
+// DescribeBrokerLoggerConfigs executes a request to describe the loggers of a broker (Kafka 2.4.0+).
+func (s *Service) DescribeBrokerLoggerConfigs(ctx context.Context, brokerID string) (def.Configs, error) {
+	return describeBrokerLoggerConfigs(ctx, s.cl, brokerID)
+}
+
+// AlterBrokerLoggerConfigs executes a request to alter the loggers of a broker (Kafka 2.4.0+).
+func (s *Service) AlterBrokerLoggerConfigs(
+	ctx context.Context,
+	brokerID string,
+	configOps ConfigOperations,
+	validateOnly bool,
+) error {
+	return incrementalAlterBrokerLoggerConfigs(ctx, s.cl, brokerID, configOps, validateOnly)
+}
+
 // ========================= Topic ============================
 
 // TryRequestTopic executes a request for the metadata of a topic that may or may not exist (Kafka 0.11.0+).
@@ -175,6 +203,11 @@ func (s *Service) CreateTopic(
 	return createTopic(ctx, s.cl, topicDef, assignments, validateOnly)
 }
 
+// DeleteTopic executes a request to delete a topic (Kafka 0.10.1+).
+func (s *Service) DeleteTopic(ctx context.Context, topic string) error {
+	return deleteTopic(ctx, s.cl, topic)
+}
+
 // CreatePartitions executes a request to create partitions (Kafka 0.10.0+).
 func (s *Service) CreatePartitions(
 	ctx context.Context,
@@ -254,3 +287,89 @@ func (s *Service) DeleteACLs(
 ) error {
 	return deleteACLs(ctx, s.cl, name, resourceType, resourcePatternType, acls)
 }
+
+// ========================= Quota ===========================
+
+// DescribeClientQuotas executes a request to describe the quotas of a specific entity (Kafka 2.6.0+).
+func (s *Service) DescribeClientQuotas(
+	ctx context.Context,
+	entity def.QuotaEntityDefinition,
+) (def.QuotasMap, error) {
+	return describeClientQuotas(ctx, s.cl, entity)
+}
+
+// DescribeAllClientQuotas executes a request to describe the quotas of all entities (Kafka 2.6.0+).
+func (s *Service) DescribeAllClientQuotas(ctx context.Context) ([]EntityQuotas, error) {
+	return describeAllClientQuotas(ctx, s.cl)
+}
+
+// AlterClientQuotas executes a request to alter the quotas of an entity (Kafka 2.6.0+).
+func (s *Service) AlterClientQuotas(
+	ctx context.Context,
+	entity def.QuotaEntityDefinition,
+	quotaOps QuotaOperations,
+	validateOnly bool,
+) error {
+	return alterClientQuotas(ctx, s.cl, entity, quotaOps, validateOnly)
+}
+
+// ========================= User ============================
+
+// DescribeUserSCRAMCredentials executes a request to describe the SCRAM credentials of a user (Kafka 2.7.0+).
+func (s *Service) DescribeUserSCRAMCredentials(ctx context.Context, user string) (def.SCRAMCredentials, error) {
+	return describeUserSCRAMCredentials(ctx, s.cl, user)
+}
+
+// DescribeAllUserSCRAMCredentials executes a request to describe the SCRAM credentials of all users (Kafka 2.7.0+).
+func (s *Service) DescribeAllUserSCRAMCredentials(ctx context.Context) ([]UserSCRAMCredentials, error) {
+	return describeAllUserSCRAMCredentials(ctx, s.cl)
+}
+
+// AlterUserSCRAMCredentials executes a request to alter the SCRAM credentials of a user (Kafka 2.7.0+).
+func (s *Service) AlterUserSCRAMCredentials(
+	ctx context.Context,
+	user string,
+	upsertions []SCRAMUpsertion,
+	deletions []string,
+) error {
+	return alterUserSCRAMCredentials(ctx, s.cl, user, upsertions, deletions)
+}
+
+// ========================= Consumer Group ==================
+
+// DescribeGroup executes a request to describe a consumer group (Kafka 0.9.0+).
+func (s *Service) DescribeGroup(ctx context.Context, group string) (GroupDescription, error) {
+	return describeGroup(ctx, s.cl, group)
+}
+
+// ListGroups executes a request to list the consumer groups of the cluster (Kafka 0.9.0+).
+func (s *Service) ListGroups(ctx context.Context) ([]string, error) {
+	return listGroups(ctx, s.cl)
+}
+
+// FetchCommittedOffsets executes a request to fetch the committed offsets of a consumer group (Kafka 0.10.2+).
+func (s *Service) FetchCommittedOffsets(ctx context.Context, group string) (def.ConsumerGroupTopics, error) {
+	return fetchCommittedOffsets(ctx, s.cl, group)
+}
+
+// ListOffsets executes a request to list the offsets of partitions for the supplied timestamps (Kafka 0.10.1+).
+func (s *Service) ListOffsets(ctx context.Context, timestamps PartitionOffsets) (PartitionOffsets, error) {
+	return listOffsets(ctx, s.cl, timestamps)
+}
+
+// CommitOffsets executes a request to commit offsets for a consumer group (Kafka 0.9.0+).
+func (s *Service) CommitOffsets(ctx context.Context, group string, offsets PartitionOffsets) error {
+	return commitOffsets(ctx, s.cl, group, offsets)
+}
+
+// ========================= Records =========================
+
+// ProduceRecord produces a record to a topic and awaits its acknowledgement.
+func (s *Service) ProduceRecord(ctx context.Context, topic string, key []byte, value []byte) error {
+	return produceRecord(ctx, s.cl, topic, key, value)
+}
+
+// FetchRecord fetches the record at an offset of a topic partition.
+func (s *Service) FetchRecord(ctx context.Context, topic string, partition int32, offset int64) (*kgo.Record, error) {
+	return fetchRecord(ctx, s.cl, topic, partition, offset)
+}
@@ -120,6 +120,43 @@ func createTopic(
 	return nil
 }
 
+// deleteTopic executes a request to delete a topic (Kafka 0.10.1+).
+func deleteTopic(
+	ctx context.Context,
+	cl *client.Client,
+	topic string,
+) error {
+	reqT := kmsg.NewDeleteTopicsRequestTopic()
+	reqT.Topic = kmsg.StringPtr(topic)
+
+	req := kmsg.NewDeleteTopicsRequest()
+	req.TopicNames = []string{topic}
+	req.Topics = append(req.Topics, reqT)
+	req.TimeoutMillis = cl.TimeoutMs()
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return err
+	}
+	resp := kresp.(*kmsg.DeleteTopicsResponse)
+
+	if len(resp.Topics) != 1 {
+		return fmt.Errorf("requested %d topic(s) but received %d", 1, len(resp.Topics))
+	}
+
+	for _, topic := range resp.Topics {
+		if err := kerr.ErrorForCode(topic.ErrorCode); err != nil {
+			errMsg := err.Error()
+			if topic.ErrorMessage != nil {
+				errMsg = fmt.Sprintf("%s: %s", errMsg, *topic.ErrorMessage)
+			}
+			return fmt.Errorf("%s", errMsg)
+		}
+	}
+
+	return nil
+}
+
 // createPartitions executes a request to create partitions (Kafka 0.10.0+).
 func createPartitions(
 	ctx context.Context,
@@ -0,0 +1,194 @@
+// Package kafka implements the Kafka service handling requests and responses.
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// SCRAM mechanism types.
+const (
+	scramMechanismSHA256 int8 = 1
+	scramMechanismSHA512 int8 = 2
+)
+
+// SCRAMUpsertion represents the upsertion of a SCRAM credential.
+type SCRAMUpsertion struct {
+	Mechanism      string
+	Iterations     int
+	Salt           []byte
+	SaltedPassword []byte
+}
+
+// UserSCRAMCredentials represents the SCRAM credentials of a user.
+type UserSCRAMCredentials struct {
+	User        string
+	Credentials def.SCRAMCredentials
+}
+
+func scramMechanismToString(mechanism int8) string {
+	switch mechanism {
+	case scramMechanismSHA256:
+		return def.SCRAMSHA256
+	case scramMechanismSHA512:
+		return def.SCRAMSHA512
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func scramMechanismFromString(mechanism string) (int8, error) {
+	switch mechanism {
+	case def.SCRAMSHA256:
+		return scramMechanismSHA256, nil
+	case def.SCRAMSHA512:
+		return scramMechanismSHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported scram mechanism %q", mechanism)
+	}
+}
+
+// describeUserSCRAMCredentials executes a request to describe the SCRAM credentials of a user (Kafka 2.7.0+).
+func describeUserSCRAMCredentials(
+	ctx context.Context,
+	cl *client.Client,
+	user string,
+) (def.SCRAMCredentials, error) {
+	reqUser := kmsg.NewDescribeUserSCRAMCredentialsRequestUser()
+	reqUser.Name = user
+
+	req := kmsg.NewDescribeUserSCRAMCredentialsRequest()
+	req.Users = append(req.Users, reqUser)
+
+	userCredentials, err := requestUserSCRAMCredentials(ctx, cl, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userCredentials) != 1 {
+		return nil, fmt.Errorf("requested %d user(s) but received %d", 1, len(userCredentials))
+	}
+
+	return userCredentials[0].Credentials, nil
+}
+
+// describeAllUserSCRAMCredentials executes a request to describe the SCRAM credentials of all users (Kafka 2.7.0+).
+func describeAllUserSCRAMCredentials(
+	ctx context.Context,
+	cl *client.Client,
+) ([]UserSCRAMCredentials, error) {
+	// A nil set of users describes all users.
+	req := kmsg.NewDescribeUserSCRAMCredentialsRequest()
+
+	return requestUserSCRAMCredentials(ctx, cl, req)
+}
+
+// requestUserSCRAMCredentials executes a request to describe user SCRAM credentials (Kafka 2.7.0+).
+func requestUserSCRAMCredentials(
+	ctx context.Context,
+	cl *client.Client,
+	req kmsg.DescribeUserSCRAMCredentialsRequest,
+) ([]UserSCRAMCredentials, error) {
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	resp := kresp.(*kmsg.DescribeUserSCRAMCredentialsResponse)
+
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		errMsg := err.Error()
+		if resp.ErrorMessage != nil {
+			errMsg = fmt.Sprintf("%s: %s", errMsg, *resp.ErrorMessage)
+		}
+		return nil, fmt.Errorf("%s", errMsg)
+	}
+
+	userCredentials := make([]UserSCRAMCredentials, len(resp.Results))
+	for i, result := range resp.Results {
+		// A user without credentials is reported as a non-existent resource.
+		if result.ErrorCode != kerr.ResourceNotFound.Code {
+			if err := kerr.ErrorForCode(result.ErrorCode); err != nil {
+				errMsg := err.Error()
+				if result.ErrorMessage != nil {
+					errMsg = fmt.Sprintf("%s: %s", errMsg, *result.ErrorMessage)
+				}
+				return nil, fmt.Errorf("%s", errMsg)
+			}
+		}
+
+		var credentials def.SCRAMCredentials
+		for _, info := range result.CredentialInfos {
+			credentials = append(credentials, def.SCRAMCredentialDefinition{
+				Mechanism:  scramMechanismToString(info.Mechanism),
+				Iterations: int(info.Iterations),
+			})
+		}
+		credentials.Sort()
+
+		userCredentials[i] = UserSCRAMCredentials{
+			User:        result.User,
+			Credentials: credentials,
+		}
+	}
+
+	return userCredentials, nil
+}
+
+// alterUserSCRAMCredentials executes a request to alter the SCRAM credentials of a user (Kafka 2.7.0+).
+func alterUserSCRAMCredentials(
+	ctx context.Context,
+	cl *client.Client,
+	user string,
+	upsertions []SCRAMUpsertion,
+	deletions []string,
+) error {
+	req := kmsg.NewAlterUserSCRAMCredentialsRequest()
+
+	for _, u := range upsertions {
+		mechanism, err := scramMechanismFromString(u.Mechanism)
+		if err != nil {
+			return err
+		}
+		reqU := kmsg.NewAlterUserSCRAMCredentialsRequestUpsertion()
+		reqU.Name = user
+		reqU.Mechanism = mechanism
+		reqU.Iterations = int32(u.Iterations)
+		reqU.Salt = u.Salt
+		reqU.SaltedPassword = u.SaltedPassword
+		req.Upsertions = append(req.Upsertions, reqU)
+	}
+
+	for _, d := range deletions {
+		mechanism, err := scramMechanismFromString(d)
+		if err != nil {
+			return err
+		}
+		reqD := kmsg.NewAlterUserSCRAMCredentialsRequestDeletion()
+		reqD.Name = user
+		reqD.Mechanism = mechanism
+		req.Deletions = append(req.Deletions, reqD)
+	}
+
+	kresp, err := cl.Client.Request(ctx, &req)
+	if err != nil {
+		return err
+	}
+	resp := kresp.(*kmsg.AlterUserSCRAMCredentialsResponse)
+
+	for _, result := range resp.Results {
+		if err := kerr.ErrorForCode(result.ErrorCode); err != nil {
+			errMsg := err.Error()
+			if result.ErrorMessage != nil {
+				errMsg = fmt.Sprintf("%s: %s", errMsg, *result.ErrorMessage)
+			}
+			return fmt.Errorf("%s", errMsg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package log implements a logging interface with pluggable sinks.
+package log //nolint:revive // package name conflicts with standard library
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Level represents the level of a log entry.
+type Level int8
+
+// Log levels.
+const (
+	LevelDebug Level = iota + 1
+	LevelInfo
+	LevelWarn
+	LevelError
+	// LevelOutput is the level of preformatted output, such as diffs and tables.
+	LevelOutput
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelOutput:
+		return "output"
+	default:
+		return "unknown"
+	}
+}
+
+// Entry represents a log entry.
+type Entry struct {
+	Level Level
+	// Key is an optional key qualifying the message, such as "dry-run".
+	Key string
+	Msg string
+}
+
+// Sink represents a destination for log entries.
+// Sinks must be safe for concurrent use.
+type Sink interface {
+	Log(entry Entry)
+}
+
+// SinkFunc is an adapter to use a function as a sink.
+type SinkFunc func(entry Entry)
+
+// Log calls f(entry).
+func (f SinkFunc) Log(entry Entry) {
+	f(entry)
+}
+
+// Discard is a sink that discards all entries.
+var Discard Sink = SinkFunc(func(Entry) {})
+
+var (
+	defaultMu   sync.RWMutex
+	defaultSink = Discard
+)
+
+// SetDefaultSink sets the sink of the package level functions and nil loggers.
+// The default sink discards all entries.
+func SetDefaultSink(sink Sink) {
+	if sink == nil {
+		sink = Discard
+	}
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultSink = sink
+}
+
+func getDefaultSink() Sink {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultSink
+}
+
+// Logger represents a logger that writes entries to a sink.
+// A nil Logger is valid and writes entries to the default sink.
+type Logger struct {
+	sink Sink
+}
+
+// New creates a logger that writes entries to a sink.
+// A nil sink discards all entries.
+func New(sink Sink) *Logger {
+	if sink == nil {
+		sink = Discard
+	}
+	return &Logger{sink: sink}
+}
+
+// Debugf logs a debug level message.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(LevelDebug, "", fmt.Sprintf(format, args...))
+}
+
+// Infof logs an info level message.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(LevelInfo, "", fmt.Sprintf(format, args...))
+}
+
+// InfoWithKeyf logs an info level message with a key.
+func (l *Logger) InfoWithKeyf(key string, format string, args ...interface{}) {
+	l.log(LevelInfo, key, fmt.Sprintf(format, args...))
+}
+
+// InfoMaybeWithKeyf logs an info level message optionally with a key.
+func (l *Logger) InfoMaybeWithKeyf(key string, withKey bool, format string, args ...interface{}) {
+	if !withKey {
+		key = ""
+	}
+	l.log(LevelInfo, key, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a warn level message.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(LevelWarn, "", fmt.Sprintf(format, args...))
+}
+
+// Error logs an error level message.
+func (l *Logger) Error(err error) {
+	l.log(LevelError, "", err.Error())
+}
+
+// Println logs preformatted output, such as a diff or table.
+func (l *Logger) Println(msg string) {
+	l.log(LevelOutput, "", msg)
+}
+
+func (l *Logger) log(level Level, key string, msg string) {
+	sink := getDefaultSink()
+	if l != nil {
+		sink = l.sink
+	}
+	sink.Log(Entry{Level: level, Key: key, Msg: msg})
+}
+
+// Buffer represents a sink that buffers log entries until flushed.
+// Buffering keeps the messages of concurrent operations grouped in the output.
+type Buffer struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewBuffer creates a sink that buffers log entries until flushed.
+func NewBuffer() *Buffer {
+	return &Buffer{}
+}
+
+// Log buffers a log entry.
+func (b *Buffer) Log(entry Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, entry)
+}
+
+// Logger creates a logger that writes to the buffer.
+func (b *Buffer) Logger() *Logger {
+	return New(b)
+}
+
+// Flush writes buffered log entries to a logger and empties the buffer.
+func (b *Buffer) Flush(l *Logger) {
+	b.mu.Lock()
+	entries := b.entries
+	b.entries = nil
+	b.mu.Unlock()
+
+	for _, entry := range entries {
+		l.log(entry.Level, entry.Key, entry.Msg)
+	}
+}
+
+var std *Logger
+
+// Debugf logs a debug level message to the default sink.
+func Debugf(format string, args ...interface{}) {
+	std.Debugf(format, args...)
+}
+
+// Infof logs an info level message to the default sink.
+func Infof(format string, args ...interface{}) {
+	std.Infof(format, args...)
+}
+
+// Warnf logs a warn level message to the default sink.
+func Warnf(format string, args ...interface{}) {
+	std.Warnf(format, args...)
+}
@@ -0,0 +1,104 @@
+// Package log implements a logging interface with pluggable sinks.
+package log //nolint:revive // package name conflicts with standard library
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type recorder struct {
+	entries []Entry
+}
+
+func (r *recorder) Log(entry Entry) {
+	r.entries = append(r.entries, entry)
+}
+
+func TestLogger(t *testing.T) {
+	rec := &recorder{}
+	l := New(rec)
+
+	l.Debugf("debug %d", 1)
+	l.Infof("info %d", 2)
+	l.InfoWithKeyf("dry-run", "info %d", 3)
+	l.InfoMaybeWithKeyf("dry-run", false, "info %d", 4)
+	l.Warnf("warn %d", 5)
+	l.Error(errors.New("error 6"))
+	l.Println("output 7")
+
+	want := []Entry{
+		{Level: LevelDebug, Msg: "debug 1"},
+		{Level: LevelInfo, Msg: "info 2"},
+		{Level: LevelInfo, Key: "dry-run", Msg: "info 3"},
+		{Level: LevelInfo, Msg: "info 4"},
+		{Level: LevelWarn, Msg: "warn 5"},
+		{Level: LevelError, Msg: "error 6"},
+		{Level: LevelOutput, Msg: "output 7"},
+	}
+	if !reflect.DeepEqual(rec.entries, want) {
+		t.Errorf("entries = %v, want %v", rec.entries, want)
+	}
+}
+
+func TestLogger_defaultSink(t *testing.T) {
+	rec := &recorder{}
+	SetDefaultSink(rec)
+	defer SetDefaultSink(nil)
+
+	var l *Logger
+	l.Infof("nil logger")
+	Warnf("package level")
+	New(nil).Infof("discarded")
+
+	want := []Entry{
+		{Level: LevelInfo, Msg: "nil logger"},
+		{Level: LevelWarn, Msg: "package level"},
+	}
+	if !reflect.DeepEqual(rec.entries, want) {
+		t.Errorf("entries = %v, want %v", rec.entries, want)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	rec := &recorder{}
+	b := NewBuffer()
+
+	b.Logger().Infof("info %d", 1)
+	b.Logger().InfoWithKeyf("dry-run", "info %d", 2)
+	if len(rec.entries) > 0 {
+		t.Errorf("entries = %v, want none before flush", rec.entries)
+	}
+
+	b.Flush(New(rec))
+	b.Flush(New(rec))
+
+	want := []Entry{
+		{Level: LevelInfo, Msg: "info 1"},
+		{Level: LevelInfo, Key: "dry-run", Msg: "info 2"},
+	}
+	if !reflect.DeepEqual(rec.entries, want) {
+		t.Errorf("entries = %v, want %v", rec.entries, want)
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{level: LevelDebug, want: "debug"},
+		{level: LevelInfo, want: "info"},
+		{level: LevelWarn, want: "warn"},
+		{level: LevelError, want: "error"},
+		{level: LevelOutput, want: "output"},
+		{level: Level(0), want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.level.String(); got != tt.want {
+				t.Errorf("Level.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
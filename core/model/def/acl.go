@@ -2,12 +2,10 @@
 package def
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/bradfitz/slice" //nolint
-	"github.com/ghodss/yaml"
 	"github.com/gotidy/copy"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/util/str"
@@ -190,20 +188,12 @@ func NewACLDefinition(
 func LoadACLDefinition(
 	defDoc string,
 	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
 ) (ACLDefinition, error) {
 	var def ACLDefinition
 
-	switch format {
-	case opt.YAMLFormat:
-		if err := yaml.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	case opt.JSONFormat:
-		if err := json.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	default:
-		return def, fmt.Errorf("unsupported format")
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
 	}
 
 	// Set defaults
@@ -2,10 +2,8 @@
 package def
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/ghodss/yaml"
 	"github.com/gotidy/copy"
 	"github.com/peter-evans/kdef/core/model/meta"
 	"github.com/peter-evans/kdef/core/model/opt"
@@ -86,20 +84,12 @@ func NewBrokerDefinition(
 func LoadBrokerDefinition(
 	defDoc string,
 	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
 ) (BrokerDefinition, error) {
 	var def BrokerDefinition
 
-	switch format {
-	case opt.YAMLFormat:
-		if err := yaml.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	case opt.JSONFormat:
-		if err := json.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	default:
-		return def, fmt.Errorf("unsupported format")
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
 	}
 
 	return def, nil
@@ -0,0 +1,137 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/copy"
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/util/i32"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// KindBrokerLogger represents the broker logger definition kind.
+const KindBrokerLogger string = "brokerLogger"
+
+// BrokerLoggerAllBrokers represents the metadata name of a broker logger definition targeting all brokers.
+const BrokerLoggerAllBrokers string = "all"
+
+// BrokerLoggerRoot represents the name of the root logger.
+const BrokerLoggerRoot string = "root"
+
+var loggerLevels = []string{
+	"ALL",
+	"TRACE",
+	"DEBUG",
+	"INFO",
+	"WARN",
+	"ERROR",
+	"FATAL",
+	"OFF",
+}
+
+// LoggersMap represents a map of logger names to levels.
+type LoggersMap map[string]string
+
+// BrokerLoggerSpecDefinition represents a broker logger spec definition.
+type BrokerLoggerSpecDefinition struct {
+	Loggers LoggersMap `json:"loggers,omitempty"`
+}
+
+// BrokerLoggerDefinition represents a broker logger resource definition.
+type BrokerLoggerDefinition struct {
+	ResourceDefinition
+	Spec BrokerLoggerSpecDefinition `json:"spec"`
+}
+
+// Copy creates a copy of this BrokerLoggerDefinition.
+func (b BrokerLoggerDefinition) Copy() BrokerLoggerDefinition {
+	copiers := copy.New()
+	copier := copiers.Get(&BrokerLoggerDefinition{}, &BrokerLoggerDefinition{})
+	var brokerLoggerDefCopy BrokerLoggerDefinition
+	copier.Copy(&brokerLoggerDefCopy, &b)
+	return brokerLoggerDefCopy
+}
+
+// IsAllBrokers determines if the definition targets all brokers.
+func (b BrokerLoggerDefinition) IsAllBrokers() bool {
+	return b.Metadata.Name == BrokerLoggerAllBrokers
+}
+
+// Validate validates the definition.
+func (b BrokerLoggerDefinition) Validate() error {
+	if err := b.ValidateResource(); err != nil {
+		return err
+	}
+
+	if !b.IsAllBrokers() {
+		if _, err := i32.ParseStr(b.Metadata.Name); err != nil {
+			return fmt.Errorf("metadata name must be an integer broker id or %q", BrokerLoggerAllBrokers)
+		}
+	}
+
+	for logger, level := range b.Spec.Loggers {
+		if len(logger) == 0 {
+			return fmt.Errorf("logger name must be supplied")
+		}
+		if !str.Contains(level, loggerLevels) {
+			return fmt.Errorf("logger %q level must be one of %q", logger, strings.Join(loggerLevels, "|"))
+		}
+	}
+
+	return nil
+}
+
+// ValidateWithMetadata further validates the definition using metadata.
+func (b BrokerLoggerDefinition) ValidateWithMetadata(brokers meta.Brokers) error {
+	if b.IsAllBrokers() {
+		return nil
+	}
+
+	// Check the value of metadata name is a valid broker ID
+	brokerID, err := i32.ParseStr(b.Metadata.Name)
+	if err != nil {
+		return err
+	}
+	if !i32.Contains(brokerID, brokers.IDs()) {
+		return fmt.Errorf("metadata name must be the id of an available broker")
+	}
+
+	return nil
+}
+
+// NewBrokerLoggerDefinition creates a broker logger definition from metadata and loggers.
+func NewBrokerLoggerDefinition(
+	metadata ResourceMetadataDefinition,
+	loggers LoggersMap,
+) BrokerLoggerDefinition {
+	brokerLoggerDef := BrokerLoggerDefinition{
+		ResourceDefinition: ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       KindBrokerLogger,
+			Metadata:   metadata,
+		},
+		Spec: BrokerLoggerSpecDefinition{
+			Loggers: loggers,
+		},
+	}
+
+	return brokerLoggerDef
+}
+
+// LoadBrokerLoggerDefinition loads a broker logger definition from a document.
+func LoadBrokerLoggerDefinition(
+	defDoc string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) (BrokerLoggerDefinition, error) {
+	var def BrokerLoggerDefinition
+
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
+	}
+
+	return def, nil
+}
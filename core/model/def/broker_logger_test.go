@@ -0,0 +1,103 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestBrokerLoggerDefinition_Validate(t *testing.T) {
+	newDef := func(name string, loggers LoggersMap) BrokerLoggerDefinition {
+		return BrokerLoggerDefinition{
+			ResourceDefinition: ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       KindBrokerLogger,
+				Metadata: ResourceMetadataDefinition{
+					Name: name,
+				},
+			},
+			Spec: BrokerLoggerSpecDefinition{
+				Loggers: loggers,
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		brokerLoggerDef BrokerLoggerDefinition
+		wantErr         string
+	}{
+		{
+			name:            "Tests an invalid metadata name",
+			brokerLoggerDef: newDef("foo", nil),
+			wantErr:         "metadata name must be an integer broker id or \"all\"",
+		},
+		{
+			name:            "Tests an invalid logger level",
+			brokerLoggerDef: newDef("1", LoggersMap{"kafka.controller": "debug"}),
+			wantErr:         "logger \"kafka.controller\" level must be one of",
+		},
+		{
+			name:            "Tests a valid broker logger definition for a broker",
+			brokerLoggerDef: newDef("1", LoggersMap{"kafka.controller": "DEBUG"}),
+			wantErr:         "",
+		},
+		{
+			name:            "Tests a valid broker logger definition for all brokers",
+			brokerLoggerDef: newDef("all", LoggersMap{"kafka.controller": "DEBUG", "root": "INFO"}),
+			wantErr:         "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.brokerLoggerDef.Validate(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("BrokerLoggerDefinition.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBrokerLoggerDefinition_ValidateWithMetadata(t *testing.T) {
+	brokers := meta.Brokers{
+		meta.Broker{ID: 1},
+		meta.Broker{ID: 2},
+	}
+
+	tests := []struct {
+		name            string
+		brokerLoggerDef BrokerLoggerDefinition
+		wantErr         string
+	}{
+		{
+			name: "Tests an unavailable broker id",
+			brokerLoggerDef: BrokerLoggerDefinition{
+				ResourceDefinition: ResourceDefinition{
+					Metadata: ResourceMetadataDefinition{
+						Name: "9",
+					},
+				},
+			},
+			wantErr: "metadata name must be the id of an available broker",
+		},
+		{
+			name: "Tests all brokers",
+			brokerLoggerDef: BrokerLoggerDefinition{
+				ResourceDefinition: ResourceDefinition{
+					Metadata: ResourceMetadataDefinition{
+						Name: "all",
+					},
+				},
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.brokerLoggerDef.ValidateWithMetadata(brokers); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("BrokerLoggerDefinition.ValidateWithMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,10 +2,6 @@
 package def
 
 import (
-	"encoding/json"
-	"fmt"
-
-	"github.com/ghodss/yaml"
 	"github.com/gotidy/copy"
 	"github.com/peter-evans/kdef/core/model/opt"
 )
@@ -62,20 +58,12 @@ func NewBrokersDefinition(
 func LoadBrokersDefinition(
 	defDoc string,
 	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
 ) (BrokersDefinition, error) {
 	var def BrokersDefinition
 
-	switch format {
-	case opt.YAMLFormat:
-		if err := yaml.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	case opt.JSONFormat:
-		if err := json.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	default:
-		return def, fmt.Errorf("unsupported format")
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
 	}
 
 	return def, nil
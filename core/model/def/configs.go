@@ -18,6 +18,26 @@ const (
 	ConfigSourceDynamicBrokerLoggerConfig  ConfigSource = 6
 )
 
+// String returns the name of the config source used by Kafka.
+func (c ConfigSource) String() string {
+	switch c {
+	case ConfigSourceDynamicTopicConfig:
+		return "DYNAMIC_TOPIC_CONFIG"
+	case ConfigSourceDynamicBrokerConfig:
+		return "DYNAMIC_BROKER_CONFIG"
+	case ConfigSourceDynamicDefaultBrokerConfig:
+		return "DYNAMIC_DEFAULT_BROKER_CONFIG"
+	case ConfigSourceStaticBrokerConfig:
+		return "STATIC_BROKER_CONFIG"
+	case ConfigSourceDefaultConfig:
+		return "DEFAULT_CONFIG"
+	case ConfigSourceDynamicBrokerLoggerConfig:
+		return "DYNAMIC_BROKER_LOGGER_CONFIG"
+	default:
+		return "UNKNOWN"
+	}
+}
+
 // ConfigKey represents a config key.
 type ConfigKey struct {
 	Name        string
@@ -38,6 +58,16 @@ func (c ConfigKey) IsDynamic() bool {
 // Configs represents a slice of ConfigKey.
 type Configs []ConfigKey
 
+// Get returns the config key with the specified name if it exists.
+func (c Configs) Get(name string) (ConfigKey, bool) {
+	for _, config := range c {
+		if config.Name == name {
+			return config, true
+		}
+	}
+	return ConfigKey{}, false
+}
+
 // ToMap returns a map of the configs.
 func (c Configs) ToMap() ConfigsMap {
 	configsMap := ConfigsMap{}
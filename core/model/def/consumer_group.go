@@ -0,0 +1,220 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gotidy/copy"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// KindConsumerGroup represents the consumer group definition kind.
+const KindConsumerGroup string = "consumerGroup"
+
+// Offset reset modes.
+const (
+	OffsetResetAbsolute  = "absolute"
+	OffsetResetEarliest  = "earliest"
+	OffsetResetLatest    = "latest"
+	OffsetResetTimestamp = "timestamp"
+)
+
+var offsetResetModes = []string{
+	OffsetResetAbsolute,
+	OffsetResetEarliest,
+	OffsetResetLatest,
+	OffsetResetTimestamp,
+}
+
+// ConsumerGroupPartitionDefinition represents the target offset of a consumer group partition.
+type ConsumerGroupPartitionDefinition struct {
+	Partition int32  `json:"partition"`
+	Reset     string `json:"reset,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Offset    *int64 `json:"offset,omitempty"`
+}
+
+// ResetMode returns the reset mode of the partition, defaulting to absolute.
+func (c ConsumerGroupPartitionDefinition) ResetMode() string {
+	if len(c.Reset) == 0 {
+		return OffsetResetAbsolute
+	}
+	return c.Reset
+}
+
+// TimestampMillis returns the timestamp of the partition in milliseconds.
+func (c ConsumerGroupPartitionDefinition) TimestampMillis() (int64, error) {
+	t, err := time.Parse(time.RFC3339, c.Timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
+
+// ConsumerGroupTopicDefinition represents the target offsets of a consumer group topic.
+type ConsumerGroupTopicDefinition struct {
+	Name       string                             `json:"name"`
+	Partitions []ConsumerGroupPartitionDefinition `json:"partitions"`
+}
+
+// ConsumerGroupTopics represents a slice of consumer group topic definitions.
+type ConsumerGroupTopics []ConsumerGroupTopicDefinition
+
+// GetPartition returns the definition of a topic partition if it exists.
+func (c ConsumerGroupTopics) GetPartition(topic string, partition int32) (ConsumerGroupPartitionDefinition, bool) {
+	for _, t := range c {
+		if t.Name != topic {
+			continue
+		}
+		for _, p := range t.Partitions {
+			if p.Partition == partition {
+				return p, true
+			}
+		}
+	}
+	return ConsumerGroupPartitionDefinition{}, false
+}
+
+// Sort sorts topics by name and their partitions by partition number.
+func (c ConsumerGroupTopics) Sort() {
+	sort.Slice(c, func(i, j int) bool {
+		return c[i].Name < c[j].Name
+	})
+	for _, t := range c {
+		partitions := t.Partitions
+		sort.Slice(partitions, func(i, j int) bool {
+			return partitions[i].Partition < partitions[j].Partition
+		})
+	}
+}
+
+// ConsumerGroupSpecDefinition represents a consumer group spec definition.
+type ConsumerGroupSpecDefinition struct {
+	Topics ConsumerGroupTopics `json:"topics,omitempty"`
+}
+
+// ConsumerGroupDefinition represents a consumer group resource definition.
+type ConsumerGroupDefinition struct {
+	ResourceDefinition
+	Spec ConsumerGroupSpecDefinition `json:"spec"`
+}
+
+// Copy creates a copy of this ConsumerGroupDefinition.
+func (c ConsumerGroupDefinition) Copy() ConsumerGroupDefinition {
+	copiers := copy.New()
+	copier := copiers.Get(&ConsumerGroupDefinition{}, &ConsumerGroupDefinition{})
+	var consumerGroupDefCopy ConsumerGroupDefinition
+	copier.Copy(&consumerGroupDefCopy, &c)
+	return consumerGroupDefCopy
+}
+
+// Validate validates the definition.
+func (c ConsumerGroupDefinition) Validate() error {
+	if err := c.ValidateResource(); err != nil {
+		return err
+	}
+
+	topics := map[string]bool{}
+	for _, t := range c.Spec.Topics {
+		if len(t.Name) == 0 {
+			return fmt.Errorf("topic name must be supplied")
+		}
+		if topics[t.Name] {
+			return fmt.Errorf("topic %q cannot be defined more than once", t.Name)
+		}
+		topics[t.Name] = true
+
+		partitions := map[int32]bool{}
+		for _, p := range t.Partitions {
+			if p.Partition < 0 {
+				return fmt.Errorf("topic %q partition must be greater than or equal to 0", t.Name)
+			}
+			if partitions[p.Partition] {
+				return fmt.Errorf("topic %q partition %d cannot be defined more than once", t.Name, p.Partition)
+			}
+			partitions[p.Partition] = true
+
+			if err := p.validate(); err != nil {
+				return fmt.Errorf("topic %q partition %d %v", t.Name, p.Partition, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate validates the reset mode of a partition definition.
+func (c ConsumerGroupPartitionDefinition) validate() error {
+	if len(c.Reset) > 0 && !str.Contains(c.Reset, offsetResetModes) {
+		return fmt.Errorf("reset must be one of %q", strings.Join(offsetResetModes, "|"))
+	}
+
+	switch c.ResetMode() {
+	case OffsetResetAbsolute:
+		if c.Offset == nil {
+			return fmt.Errorf("offset must be supplied for reset %q", OffsetResetAbsolute)
+		}
+		if *c.Offset < 0 {
+			return fmt.Errorf("offset must be greater than or equal to 0")
+		}
+		if len(c.Timestamp) > 0 {
+			return fmt.Errorf("timestamp cannot be supplied for reset %q", OffsetResetAbsolute)
+		}
+	case OffsetResetTimestamp:
+		if c.Offset != nil {
+			return fmt.Errorf("offset cannot be supplied for reset %q", OffsetResetTimestamp)
+		}
+		if _, err := c.TimestampMillis(); err != nil {
+			return fmt.Errorf("timestamp must be a valid RFC 3339 timestamp")
+		}
+	default:
+		if c.Offset != nil {
+			return fmt.Errorf("offset cannot be supplied for reset %q", c.Reset)
+		}
+		if len(c.Timestamp) > 0 {
+			return fmt.Errorf("timestamp cannot be supplied for reset %q", c.Reset)
+		}
+	}
+
+	return nil
+}
+
+// NewConsumerGroupDefinition creates a consumer group definition from metadata and topic offsets.
+func NewConsumerGroupDefinition(
+	metadata ResourceMetadataDefinition,
+	topics ConsumerGroupTopics,
+) ConsumerGroupDefinition {
+	consumerGroupDef := ConsumerGroupDefinition{
+		ResourceDefinition: ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       KindConsumerGroup,
+			Metadata:   metadata,
+		},
+		Spec: ConsumerGroupSpecDefinition{
+			Topics: topics,
+		},
+	}
+
+	return consumerGroupDef
+}
+
+// LoadConsumerGroupDefinition loads a consumer group definition from a document.
+func LoadConsumerGroupDefinition(
+	defDoc string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) (ConsumerGroupDefinition, error) {
+	var def ConsumerGroupDefinition
+
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
+	}
+
+	def.Spec.Topics.Sort()
+
+	return def, nil
+}
@@ -0,0 +1,220 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestConsumerGroupDefinition_Validate(t *testing.T) {
+	resDef := ResourceDefinition{
+		APIVersion: "v1",
+		Kind:       KindConsumerGroup,
+		Metadata: ResourceMetadataDefinition{
+			Name: "foo",
+		},
+	}
+	offset := int64(100)
+	negativeOffset := int64(-1)
+
+	tests := []struct {
+		name             string
+		consumerGroupDef ConsumerGroupDefinition
+		wantErr          string
+	}{
+		{
+			name: "Tests duplicate topic",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{Name: "bar", Partitions: []ConsumerGroupPartitionDefinition{{Partition: 0, Offset: &offset}}},
+						{Name: "bar", Partitions: []ConsumerGroupPartitionDefinition{{Partition: 1, Offset: &offset}}},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" cannot be defined more than once",
+		},
+		{
+			name: "Tests duplicate partition",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{
+							Name: "bar",
+							Partitions: []ConsumerGroupPartitionDefinition{
+								{Partition: 0, Offset: &offset},
+								{Partition: 0, Reset: OffsetResetLatest},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" partition 0 cannot be defined more than once",
+		},
+		{
+			name: "Tests invalid reset",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{Name: "bar", Partitions: []ConsumerGroupPartitionDefinition{{Partition: 0, Reset: "foo"}}},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" partition 0 reset must be one of",
+		},
+		{
+			name: "Tests absolute reset without offset",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{Name: "bar", Partitions: []ConsumerGroupPartitionDefinition{{Partition: 0}}},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" partition 0 offset must be supplied for reset \"absolute\"",
+		},
+		{
+			name: "Tests negative offset",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{Name: "bar", Partitions: []ConsumerGroupPartitionDefinition{{Partition: 0, Offset: &negativeOffset}}},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" partition 0 offset must be greater than or equal to 0",
+		},
+		{
+			name: "Tests latest reset with offset",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{
+							Name:       "bar",
+							Partitions: []ConsumerGroupPartitionDefinition{{Partition: 0, Reset: OffsetResetLatest, Offset: &offset}},
+						},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" partition 0 offset cannot be supplied for reset \"latest\"",
+		},
+		{
+			name: "Tests invalid timestamp",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{
+							Name: "bar",
+							Partitions: []ConsumerGroupPartitionDefinition{
+								{Partition: 0, Reset: OffsetResetTimestamp, Timestamp: "yesterday"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "topic \"bar\" partition 0 timestamp must be a valid RFC 3339 timestamp",
+		},
+		{
+			name: "Tests a valid ConsumerGroupDefinition",
+			consumerGroupDef: ConsumerGroupDefinition{
+				ResourceDefinition: resDef,
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{
+							Name: "bar",
+							Partitions: []ConsumerGroupPartitionDefinition{
+								{Partition: 0, Offset: &offset},
+								{Partition: 1, Reset: OffsetResetEarliest},
+								{Partition: 2, Reset: OffsetResetLatest},
+								{Partition: 3, Reset: OffsetResetTimestamp, Timestamp: "2021-11-01T12:00:00Z"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.consumerGroupDef.Validate(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("ConsumerGroupDefinition.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConsumerGroupDefinition(t *testing.T) {
+	offset := int64(100)
+
+	type args struct {
+		defDoc string
+		format opt.DefinitionFormat
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    ConsumerGroupDefinition
+		wantErr string
+	}{
+		{
+			name: "Tests loading and sorting a valid consumer group definition",
+			args: args{
+				defDoc: "apiVersion: v1\nkind: consumerGroup\nmetadata:\n  name: foo\nspec:\n  topics:\n" +
+					"  - name: qux\n    partitions:\n    - partition: 0\n      reset: latest\n" +
+					"  - name: bar\n    partitions:\n    - partition: 1\n      reset: earliest\n" +
+					"    - partition: 0\n      offset: 100",
+				format: opt.YAMLFormat,
+			},
+			want: ConsumerGroupDefinition{
+				ResourceDefinition: ResourceDefinition{
+					APIVersion: "v1",
+					Kind:       KindConsumerGroup,
+					Metadata: ResourceMetadataDefinition{
+						Name: "foo",
+					},
+				},
+				Spec: ConsumerGroupSpecDefinition{
+					Topics: ConsumerGroupTopics{
+						{
+							Name: "bar",
+							Partitions: []ConsumerGroupPartitionDefinition{
+								{Partition: 0, Offset: &offset},
+								{Partition: 1, Reset: OffsetResetEarliest},
+							},
+						},
+						{
+							Name: "qux",
+							Partitions: []ConsumerGroupPartitionDefinition{
+								{Partition: 0, Reset: OffsetResetLatest},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadConsumerGroupDefinition(tt.args.defDoc, tt.args.format, nil)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("LoadConsumerGroupDefinition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadConsumerGroupDefinition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// resourceDefinition is implemented by definitions embedding a resource definition.
+type resourceDefinition interface {
+	resource() *ResourceDefinition
+}
+
+// unmarshalDefinition decodes a definition document.
+// Fields unknown to the definition are rejected, unless the apiVersion of the definition is one of
+// the lenient apiVersions, e.g. of definitions written for a newer version of kdef.
+// Definitions with a lenient apiVersion keep their apiVersion, and record the unknown fields they ignore.
+func unmarshalDefinition(defDoc string, format opt.DefinitionFormat, lenientAPIVersions []string, def interface{}) error {
+	var j []byte
+	switch format {
+	case opt.YAMLFormat:
+		if err := yaml.Unmarshal([]byte(defDoc), def); err != nil {
+			return err
+		}
+		var err error
+		if j, err = yaml.YAMLToJSON([]byte(defDoc)); err != nil {
+			return err
+		}
+	case opt.JSONFormat:
+		if err := json.Unmarshal([]byte(defDoc), def); err != nil {
+			return err
+		}
+		j = []byte(defDoc)
+	default:
+		return fmt.Errorf("unsupported format")
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(j, &doc); err != nil {
+		return err
+	}
+
+	unknown := unknownFields(doc, reflect.TypeOf(def), "")
+	if r, ok := def.(resourceDefinition); ok && isLenientAPIVersion(r.resource().APIVersion, lenientAPIVersions) {
+		r.resource().lenient = true
+		r.resource().ignoredFields = unknown
+		return nil
+	}
+
+	switch len(unknown) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("unknown field %q", unknown[0])
+	default:
+		quoted := make([]string, len(unknown))
+		for i, field := range unknown {
+			quoted[i] = fmt.Sprintf("%q", field)
+		}
+		return fmt.Errorf("unknown fields %s", strings.Join(quoted, ", "))
+	}
+}
+
+// isLenientAPIVersion determines if an apiVersion is one of the lenient apiVersions.
+// Empty lenient apiVersions are ignored.
+func isLenientAPIVersion(apiVersion string, lenientAPIVersions []string) bool {
+	for _, v := range lenientAPIVersions {
+		if v = strings.TrimSpace(v); len(v) > 0 && v == apiVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownFields returns the paths of the fields of a value decoded from JSON that are unknown to a type.
+// Field names must match exactly, unlike the case-insensitive matching of encoding/json.
+func unknownFields(v interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(m) {
+			ft, ok := fields[key]
+			if !ok {
+				unknown = append(unknown, fieldPath(path, key))
+				continue
+			}
+			unknown = append(unknown, unknownFields(m[key], ft, fieldPath(path, key))...)
+		}
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(m) {
+			unknown = append(unknown, unknownFields(m[key], t.Elem(), fieldPath(path, key))...)
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, e := range list {
+			unknown = append(unknown, unknownFields(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields returns the types of the fields of a struct type by JSON name, including promoted fields.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && len(name) == 0 && f.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// fieldPath returns the path of a field of a value at a path.
+func fieldPath(path string, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns the sorted keys of a map.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
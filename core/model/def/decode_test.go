@@ -0,0 +1,161 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestLoadTopicDefinition_UnknownFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		defDoc      string
+		format      opt.DefinitionFormat
+		lenient     string
+		wantIgnored []string
+		wantErr     string
+	}{
+		{
+			name: "Tests a definition without unknown fields",
+			defDoc: `
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+  labels:
+    team.name: bar
+spec:
+  configs:
+    retention.ms: "86400000"
+  partitions: 2
+  replicationFactor: 2
+  assignments:
+    - [1, 2]
+    - [2, 3]
+`,
+			format:  opt.YAMLFormat,
+			wantErr: "",
+		},
+		{
+			name: "Tests a field name with incorrect case",
+			defDoc: `
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 3
+  replicationfactor: 2
+`,
+			format:  opt.YAMLFormat,
+			wantErr: "unknown field \"spec.replicationfactor\"",
+		},
+		{
+			name: "Tests multiple unknown fields",
+			defDoc: `
+apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+  label:
+    team: bar
+spec:
+  partitions: 3
+  replicationFactor: 2
+  deleteUndefinedConfig: true
+`,
+			format:  opt.YAMLFormat,
+			wantErr: "unknown fields \"metadata.label\", \"spec.deleteUndefinedConfig\"",
+		},
+		{
+			name: "Tests an unknown field in JSON format",
+			defDoc: `{
+  "apiVersion": "v1",
+  "kind": "topic",
+  "metadata": {"name": "foo"},
+  "spec": {"partitions": 3, "replicationFactor": 2, "maintainLeader": true}
+}`,
+			format:  opt.JSONFormat,
+			wantErr: "unknown field \"spec.maintainLeader\"",
+		},
+		{
+			name: "Tests an unknown field of a definition with a lenient apiVersion",
+			defDoc: `
+apiVersion: v2
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 3
+  replicationFactor: 2
+  tieredStorage: true
+`,
+			format:      opt.YAMLFormat,
+			lenient:     "v1, v2",
+			wantIgnored: []string{"spec.tieredStorage"},
+			wantErr:     "",
+		},
+		{
+			name: "Tests an unknown field of a definition with an empty apiVersion",
+			defDoc: `
+apiVersion: ""
+kind: topic
+metadata:
+  name: foo
+spec:
+  partitions: 3
+  replicationFactor: 2
+  tieredStorage: true
+`,
+			format:  opt.YAMLFormat,
+			lenient: " , ",
+			wantErr: "unknown field \"spec.tieredStorage\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topicDef, err := LoadTopicDefinition(tt.defDoc, tt.format, nil, strings.Split(tt.lenient, ","))
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("LoadTopicDefinition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(tt.wantErr) == 0 {
+				// Definitions of lenient apiVersions keep their apiVersion and record the fields they ignore.
+				if err := topicDef.Validate(); err != nil {
+					t.Errorf("TopicDefinition.Validate() error = %v", err)
+				}
+				if got := topicDef.IgnoredFields(); !reflect.DeepEqual(got, tt.wantIgnored) {
+					t.Errorf("TopicDefinition.IgnoredFields() = %v, want %v", got, tt.wantIgnored)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadACLDefinition_UnknownFields(t *testing.T) {
+	defDoc := `
+apiVersion: v1
+kind: acl
+metadata:
+  name: foo
+  type: topic
+spec:
+  acls:
+    - principals: ["User:foo"]
+      hosts: ["*"]
+      operations: ["READ"]
+      permissionType: ALLOW
+    - principals: ["User:bar"]
+      hosts: ["*"]
+      operation: ["WRITE"]
+      permissionType: ALLOW
+`
+	wantErr := "unknown field \"spec.acls[1].operation\""
+	if _, err := LoadACLDefinition(defDoc, opt.YAMLFormat, nil); !tutil.ErrorContains(err, wantErr) {
+		t.Errorf("LoadACLDefinition() error = %v, wantErr %v", err, wantErr)
+	}
+}
@@ -0,0 +1,147 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/copy"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// KindQuota represents the quota definition kind.
+const KindQuota string = "quota"
+
+// QuotaEntityDefault represents the default entity name of an entity type.
+const QuotaEntityDefault string = "<default>"
+
+var clientQuotaKeys = []string{
+	"producer_byte_rate",
+	"consumer_byte_rate",
+	"request_percentage",
+	"controller_mutation_rate",
+}
+
+var ipQuotaKeys = []string{
+	"connection_creation_rate",
+}
+
+// QuotaEntityDefinition represents a quota entity definition.
+type QuotaEntityDefinition struct {
+	User     string `json:"user,omitempty"`
+	ClientID string `json:"clientId,omitempty"`
+	IP       string `json:"ip,omitempty"`
+}
+
+// IsEmpty determines if the entity has no components.
+func (q QuotaEntityDefinition) IsEmpty() bool {
+	return len(q.User) == 0 && len(q.ClientID) == 0 && len(q.IP) == 0
+}
+
+// String returns a string representation of the entity.
+func (q QuotaEntityDefinition) String() string {
+	var components []string
+	if len(q.User) > 0 {
+		components = append(components, fmt.Sprintf("user=%s", q.User))
+	}
+	if len(q.ClientID) > 0 {
+		components = append(components, fmt.Sprintf("client-id=%s", q.ClientID))
+	}
+	if len(q.IP) > 0 {
+		components = append(components, fmt.Sprintf("ip=%s", q.IP))
+	}
+	return strings.Join(components, ",")
+}
+
+// QuotasMap represents a map of quota keys to values.
+type QuotasMap map[string]float64
+
+// QuotaSpecDefinition represents a quota spec definition.
+type QuotaSpecDefinition struct {
+	Entity                QuotaEntityDefinition `json:"entity"`
+	Quotas                QuotasMap             `json:"quotas,omitempty"`
+	DeleteUndefinedQuotas bool                  `json:"deleteUndefinedQuotas"`
+}
+
+// QuotaDefinition represents a quota resource definition.
+type QuotaDefinition struct {
+	ResourceDefinition
+	Spec QuotaSpecDefinition `json:"spec"`
+}
+
+// Copy creates a copy of this QuotaDefinition.
+func (q QuotaDefinition) Copy() QuotaDefinition {
+	copiers := copy.New()
+	copier := copiers.Get(&QuotaDefinition{}, &QuotaDefinition{})
+	var quotaDefCopy QuotaDefinition
+	copier.Copy(&quotaDefCopy, &q)
+	return quotaDefCopy
+}
+
+// Validate validates the definition.
+func (q QuotaDefinition) Validate() error {
+	if err := q.ValidateResource(); err != nil {
+		return err
+	}
+
+	if q.Spec.Entity.IsEmpty() {
+		return fmt.Errorf("entity must specify at least one of user, clientId or ip")
+	}
+
+	isIP := len(q.Spec.Entity.IP) > 0
+	if isIP && (len(q.Spec.Entity.User) > 0 || len(q.Spec.Entity.ClientID) > 0) {
+		return fmt.Errorf("entity ip cannot be specified at the same time as user or clientId")
+	}
+
+	validKeys := clientQuotaKeys
+	if isIP {
+		validKeys = ipQuotaKeys
+	}
+	for k, v := range q.Spec.Quotas {
+		if !str.Contains(k, validKeys) {
+			return fmt.Errorf("quota key %q must be one of %q for the entity", k, strings.Join(validKeys, "|"))
+		}
+		if v <= 0 {
+			return fmt.Errorf("quota value for key %q must be greater than 0", k)
+		}
+	}
+
+	return nil
+}
+
+// NewQuotaDefinition creates a quota definition from metadata, entity and quotas.
+func NewQuotaDefinition(
+	metadata ResourceMetadataDefinition,
+	entity QuotaEntityDefinition,
+	quotasMap QuotasMap,
+) QuotaDefinition {
+	quotaDef := QuotaDefinition{
+		ResourceDefinition: ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       KindQuota,
+			Metadata:   metadata,
+		},
+		Spec: QuotaSpecDefinition{
+			Entity: entity,
+			Quotas: quotasMap,
+		},
+	}
+
+	return quotaDef
+}
+
+// LoadQuotaDefinition loads a quota definition from a document.
+func LoadQuotaDefinition(
+	defDoc string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) (QuotaDefinition, error) {
+	var def QuotaDefinition
+
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
+	}
+
+	return def, nil
+}
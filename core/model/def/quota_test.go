@@ -0,0 +1,157 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"testing"
+
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestQuotaDefinition_Validate(t *testing.T) {
+	resDef := ResourceDefinition{
+		APIVersion: "v1",
+		Kind:       KindQuota,
+		Metadata: ResourceMetadataDefinition{
+			Name: "foo",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		quotaDef QuotaDefinition
+		wantErr  string
+	}{
+		{
+			name: "Tests missing entity",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Quotas: QuotasMap{"producer_byte_rate": 1024},
+				},
+			},
+			wantErr: "entity must specify at least one of user, clientId or ip",
+		},
+		{
+			name: "Tests entity ip with user",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Entity: QuotaEntityDefinition{
+						User: "alice",
+						IP:   "10.0.0.1",
+					},
+				},
+			},
+			wantErr: "entity ip cannot be specified at the same time as user or clientId",
+		},
+		{
+			name: "Tests invalid quota key",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Entity: QuotaEntityDefinition{
+						User: "alice",
+					},
+					Quotas: QuotasMap{"foo": 1024},
+				},
+			},
+			wantErr: "quota key \"foo\" must be one of",
+		},
+		{
+			name: "Tests invalid quota key for entity ip",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Entity: QuotaEntityDefinition{
+						IP: QuotaEntityDefault,
+					},
+					Quotas: QuotasMap{"producer_byte_rate": 1024},
+				},
+			},
+			wantErr: "quota key \"producer_byte_rate\" must be one of",
+		},
+		{
+			name: "Tests invalid quota value",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Entity: QuotaEntityDefinition{
+						ClientID: "foo",
+					},
+					Quotas: QuotasMap{"request_percentage": 0},
+				},
+			},
+			wantErr: "quota value for key \"request_percentage\" must be greater than 0",
+		},
+		{
+			name: "Tests a valid QuotaDefinition",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Entity: QuotaEntityDefinition{
+						User:     "alice",
+						ClientID: QuotaEntityDefault,
+					},
+					Quotas: QuotasMap{
+						"producer_byte_rate": 1048576,
+						"consumer_byte_rate": 2097152,
+						"request_percentage": 200,
+					},
+					DeleteUndefinedQuotas: true,
+				},
+			},
+			wantErr: "",
+		},
+		{
+			name: "Tests a valid QuotaDefinition for entity ip",
+			quotaDef: QuotaDefinition{
+				ResourceDefinition: resDef,
+				Spec: QuotaSpecDefinition{
+					Entity: QuotaEntityDefinition{
+						IP: "10.0.0.1",
+					},
+					Quotas: QuotasMap{"connection_creation_rate": 10},
+				},
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.quotaDef.Validate(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("QuotaDefinition.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuotaEntityDefinition_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity QuotaEntityDefinition
+		want   string
+	}{
+		{
+			name:   "Tests user entity",
+			entity: QuotaEntityDefinition{User: "alice"},
+			want:   "user=alice",
+		},
+		{
+			name:   "Tests user and default client-id entity",
+			entity: QuotaEntityDefinition{User: "alice", ClientID: QuotaEntityDefault},
+			want:   "user=alice,client-id=<default>",
+		},
+		{
+			name:   "Tests ip entity",
+			entity: QuotaEntityDefinition{IP: "10.0.0.1"},
+			want:   "ip=10.0.0.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entity.String(); got != tt.want {
+				t.Errorf("QuotaEntityDefinition.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,24 @@
 package def
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/ghodss/yaml"
+	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/util/str"
 )
 
 var definitionKindVersions = map[string][]string{
-	KindACL:     {"v1"},
-	KindBroker:  {"v1"},
-	KindBrokers: {"v1"},
-	KindTopic:   {"v1"},
+	KindACL:           {"v1"},
+	KindBroker:        {"v1"},
+	KindBrokerLogger:  {"v1"},
+	KindBrokers:       {"v1"},
+	KindConsumerGroup: {"v1"},
+	KindQuota:         {"v1"},
+	KindTopic:         {"v1"},
+	KindTopicProfile:  {"v1"},
+	KindUser:          {"v1"},
 }
 
 // ResourceMetadataLabels represents resource metadata labels.
@@ -30,12 +38,17 @@ type ResourceDefinition struct {
 	APIVersion string                     `json:"apiVersion"`
 	Kind       string                     `json:"kind"`
 	Metadata   ResourceMetadataDefinition `json:"metadata"`
+
+	// lenient is true if the definition was loaded with a lenient apiVersion.
+	lenient bool
+	// ignoredFields are the paths of the unknown fields ignored when loading a definition with a lenient apiVersion.
+	ignoredFields []string
 }
 
 // ValidateResource validates the resource definition.
 func (r ResourceDefinition) ValidateResource() error {
 	if versions, ok := definitionKindVersions[r.Kind]; ok {
-		if !str.Contains(r.APIVersion, versions) {
+		if !str.Contains(r.APIVersion, versions) && !r.lenient {
 			return fmt.Errorf("invalid definition apiVersion %q", r.APIVersion)
 		}
 	} else {
@@ -48,3 +61,40 @@ func (r ResourceDefinition) ValidateResource() error {
 
 	return nil
 }
+
+// LoadResourceDefinition loads the resource definition of a document of any kind.
+// Fields of the document other than those of the resource definition are not checked.
+func LoadResourceDefinition(
+	defDoc string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) (ResourceDefinition, error) {
+	var def ResourceDefinition
+
+	switch format {
+	case opt.YAMLFormat:
+		if err := yaml.Unmarshal([]byte(defDoc), &def); err != nil {
+			return def, err
+		}
+	case opt.JSONFormat:
+		if err := json.Unmarshal([]byte(defDoc), &def); err != nil {
+			return def, err
+		}
+	default:
+		return def, fmt.Errorf("unsupported format")
+	}
+	def.lenient = isLenientAPIVersion(def.APIVersion, lenientAPIVersions)
+
+	return def, nil
+}
+
+// resource returns the resource definition.
+func (r *ResourceDefinition) resource() *ResourceDefinition {
+	return r
+}
+
+// IgnoredFields returns the paths of the unknown fields ignored when loading the definition
+// with a lenient apiVersion.
+func (r ResourceDefinition) IgnoredFields() []string {
+	return r.ignoredFields
+}
@@ -4,6 +4,7 @@ package def
 import (
 	"testing"
 
+	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/test/tutil"
 )
 
@@ -16,6 +17,7 @@ func TestResourceDefinition_ValidateResource(t *testing.T) {
 	tests := []struct {
 		name    string
 		fields  fields
+		lenient []string
 		wantErr string
 	}{
 		{
@@ -29,6 +31,29 @@ func TestResourceDefinition_ValidateResource(t *testing.T) {
 			},
 			wantErr: "invalid definition apiVersion \"foo\"",
 		},
+		{
+			name: "Tests a lenient apiVersion",
+			fields: fields{
+				APIVersion: "v2",
+				Kind:       KindTopic,
+				Metadata: ResourceMetadataDefinition{
+					Name: "foo",
+				},
+			},
+			lenient: []string{"v2"},
+			wantErr: "",
+		},
+		{
+			name: "Tests an empty apiVersion with empty lenient apiVersions",
+			fields: fields{
+				Kind: KindTopic,
+				Metadata: ResourceMetadataDefinition{
+					Name: "foo",
+				},
+			},
+			lenient: []string{"", " "},
+			wantErr: "invalid definition apiVersion \"\"",
+		},
 		{
 			name: "Tests invalid kind",
 			fields: fields{
@@ -66,6 +91,7 @@ func TestResourceDefinition_ValidateResource(t *testing.T) {
 				APIVersion: tt.fields.APIVersion,
 				Kind:       tt.fields.Kind,
 				Metadata:   tt.fields.Metadata,
+				lenient:    isLenientAPIVersion(tt.fields.APIVersion, tt.lenient),
 			}
 			err := r.ValidateResource()
 			if !tutil.ErrorContains(err, tt.wantErr) {
@@ -75,3 +101,42 @@ func TestResourceDefinition_ValidateResource(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadResourceDefinition(t *testing.T) {
+	defDoc := `
+apiVersion: v2
+kind: topic
+metadata:
+  name: foo
+spec:
+  tieredStorage: true
+`
+	tests := []struct {
+		name    string
+		lenient []string
+		wantErr string
+	}{
+		{
+			name:    "Tests an apiVersion that is not lenient",
+			lenient: nil,
+			wantErr: "invalid definition apiVersion \"v2\"",
+		},
+		{
+			name:    "Tests a lenient apiVersion",
+			lenient: []string{"v2"},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := LoadResourceDefinition(defDoc, opt.YAMLFormat, tt.lenient)
+			if err != nil {
+				t.Errorf("LoadResourceDefinition() error = %v", err)
+				return
+			}
+			if err := r.ValidateResource(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("ResourceDefinition.ValidateResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import "fmt"
+
+// Source represents the position of a definition document in its source.
+type Source struct {
+	// File is the path of the file the document was loaded from, if any.
+	File string `json:"file,omitempty"`
+	// Doc is the number of the document in its source, starting at 1.
+	Doc int `json:"doc"`
+	// Line is the line the document starts on, starting at 1, or 0 if unknown.
+	Line int `json:"line,omitempty"`
+}
+
+// String returns the position as "file:line (document N)".
+// The file and line are omitted if unknown.
+func (s Source) String() string {
+	switch {
+	case len(s.File) == 0 && s.Line == 0:
+		return fmt.Sprintf("document %d", s.Doc)
+	case len(s.File) == 0:
+		return fmt.Sprintf("line %d (document %d)", s.Line, s.Doc)
+	case s.Line == 0:
+		return fmt.Sprintf("%s (document %d)", s.File, s.Doc)
+	default:
+		return fmt.Sprintf("%s:%d (document %d)", s.File, s.Line, s.Doc)
+	}
+}
+
+// Errorf returns an error prefixed with the position of the source, if any.
+func (s *Source) Errorf(format string, a ...interface{}) error {
+	if s == nil {
+		return fmt.Errorf(format, a...)
+	}
+	return fmt.Errorf("%s: %s", s, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,42 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"testing"
+)
+
+func TestSource_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		source Source
+		want   string
+	}{
+		{
+			name:   "Tests a source with a file and line",
+			source: Source{File: "topics.yml", Doc: 3, Line: 17},
+			want:   "topics.yml:17 (document 3)",
+		},
+		{
+			name:   "Tests a source without a file",
+			source: Source{Doc: 3, Line: 17},
+			want:   "line 17 (document 3)",
+		},
+		{
+			name:   "Tests a source without a line",
+			source: Source{File: "plan.json", Doc: 3},
+			want:   "plan.json (document 3)",
+		},
+		{
+			name:   "Tests a source without a file or line",
+			source: Source{Doc: 3},
+			want:   "document 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.source.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,12 @@
 package def
 
 import (
-	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 
-	"github.com/ghodss/yaml"
 	"github.com/gotidy/copy"
-	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/meta"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/util/i32"
@@ -41,6 +39,17 @@ var selectionMethods = []string{
 	SelectionTopicUse,
 }
 
+// Resource states.
+const (
+	StatePresent = "present"
+	StateAbsent  = "absent"
+)
+
+var resourceStates = []string{
+	StatePresent,
+	StateAbsent,
+}
+
 // PartitionAssignments represents partition assignments by broker ID.
 type PartitionAssignments [][]int32
 
@@ -64,6 +73,8 @@ func (m ManagedAssignmentsDefinition) HasRackConstraints() bool {
 
 // TopicSpecDefinition represents a topic spec definition.
 type TopicSpecDefinition struct {
+	State                  string                        `json:"state,omitempty"`
+	Profiles               []string                      `json:"profiles,omitempty"`
 	Configs                ConfigsMap                    `json:"configs,omitempty"`
 	DeleteUndefinedConfigs bool                          `json:"deleteUndefinedConfigs"`
 	Partitions             int                           `json:"partitions"`
@@ -73,6 +84,11 @@ type TopicSpecDefinition struct {
 	MaintainLeaders        bool                          `json:"maintainLeaders"`
 }
 
+// IsAbsent determines if a spec declares that the topic should not exist.
+func (t TopicSpecDefinition) IsAbsent() bool {
+	return t.State == StateAbsent
+}
+
 // HasAssignments determines if a spec has assignments.
 func (t TopicSpecDefinition) HasAssignments() bool {
 	return len(t.Assignments) > 0
@@ -111,6 +127,19 @@ func (t TopicDefinition) Validate() error {
 		return err
 	}
 
+	if len(t.Spec.Profiles) > 0 {
+		return fmt.Errorf("profiles %q must be resolved", strings.Join(t.Spec.Profiles, ","))
+	}
+
+	if len(t.Spec.State) > 0 && !str.Contains(t.Spec.State, resourceStates) {
+		return fmt.Errorf("state must be one of %q", strings.Join(resourceStates, "|"))
+	}
+
+	if t.Spec.IsAbsent() {
+		// The remaining properties are irrelevant to a topic that should not exist.
+		return nil
+	}
+
 	if t.Spec.Partitions <= 0 {
 		return fmt.Errorf("partitions must be greater than 0")
 	}
@@ -278,20 +307,12 @@ func LoadTopicDefinition(
 	defDoc string,
 	format opt.DefinitionFormat,
 	propOverrides []string,
+	lenientAPIVersions []string,
 ) (TopicDefinition, error) {
 	var def TopicDefinition
 
-	switch format {
-	case opt.YAMLFormat:
-		if err := yaml.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	case opt.JSONFormat:
-		if err := json.Unmarshal([]byte(defDoc), &def); err != nil {
-			return def, err
-		}
-	default:
-		return def, fmt.Errorf("unsupported format")
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
 	}
 
 	// Set defaults
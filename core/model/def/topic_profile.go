@@ -0,0 +1,182 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// KindTopicProfile represents the topic profile definition kind.
+const KindTopicProfile string = "topicProfile"
+
+// minProfileTopics is the minimum number of topics sharing configs for a profile to be inferred.
+const minProfileTopics = 2
+
+// TopicProfileSpecDefinition represents a topic profile spec definition.
+type TopicProfileSpecDefinition struct {
+	Configs           ConfigsMap `json:"configs,omitempty"`
+	Partitions        int        `json:"partitions,omitempty"`
+	ReplicationFactor int        `json:"replicationFactor,omitempty"`
+}
+
+// TopicProfileDefinition represents a topic profile definition.
+type TopicProfileDefinition struct {
+	ResourceDefinition
+	Spec TopicProfileSpecDefinition `json:"spec"`
+}
+
+// Validate validates the definition.
+func (t TopicProfileDefinition) Validate() error {
+	if err := t.ValidateResource(); err != nil {
+		return err
+	}
+
+	if t.Spec.Partitions < 0 {
+		return fmt.Errorf("partitions must not be negative")
+	}
+
+	if t.Spec.ReplicationFactor < 0 {
+		return fmt.Errorf("replication factor must not be negative")
+	}
+
+	return nil
+}
+
+// LoadTopicProfileDefinition loads a topic profile definition from a document.
+func LoadTopicProfileDefinition(
+	defDoc string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) (TopicProfileDefinition, error) {
+	var def TopicProfileDefinition
+
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
+	}
+
+	return def, nil
+}
+
+// ResolveProfiles resolves the profiles referenced by the definition in order.
+// Configs of later profiles take precedence over earlier profiles, and configs of the definition take precedence over all profiles.
+// Partitions and replication factor are taken from the last profile defining them, unless defined by the definition.
+func (t *TopicDefinition) ResolveProfiles(profiles map[string]TopicProfileDefinition) error {
+	if len(t.Spec.Profiles) == 0 {
+		return nil
+	}
+
+	configs := ConfigsMap{}
+	var partitions, replicationFactor int
+	for _, name := range t.Spec.Profiles {
+		profile, ok := profiles[name]
+		if !ok {
+			return fmt.Errorf("topic profile %q is not defined", name)
+		}
+		for key, value := range profile.Spec.Configs {
+			configs[key] = value
+		}
+		if profile.Spec.Partitions > 0 {
+			partitions = profile.Spec.Partitions
+		}
+		if profile.Spec.ReplicationFactor > 0 {
+			replicationFactor = profile.Spec.ReplicationFactor
+		}
+	}
+	for key, value := range t.Spec.Configs {
+		configs[key] = value
+	}
+
+	if len(configs) > 0 {
+		t.Spec.Configs = configs
+	}
+	if t.Spec.Partitions == 0 {
+		t.Spec.Partitions = partitions
+	}
+	if t.Spec.ReplicationFactor == 0 {
+		t.Spec.ReplicationFactor = replicationFactor
+	}
+	t.Spec.Profiles = nil
+
+	return nil
+}
+
+// InferTopicProfiles factors identical configs shared by topic definitions out to topic profiles.
+// Topic definitions sharing the configs of a profile reference the profile instead of defining the configs.
+// Profiles are named "profile-1", "profile-2", etc. in descending order of the number of topics referencing them.
+func InferTopicProfiles(topicDefs []TopicDefinition) ([]TopicProfileDefinition, []TopicDefinition) {
+	type group struct {
+		configs ConfigsMap
+		topics  []int
+	}
+
+	groups := map[string]*group{}
+	var keys []string
+	for i, topicDef := range topicDefs {
+		if len(topicDef.Spec.Configs) == 0 {
+			continue
+		}
+		key := configsKey(topicDef.Spec.Configs)
+		if _, ok := groups[key]; !ok {
+			groups[key] = &group{configs: topicDef.Spec.Configs}
+			keys = append(keys, key)
+		}
+		groups[key].topics = append(groups[key].topics, i)
+	}
+
+	// Groups of equal size remain in the order of their first topic.
+	sort.SliceStable(keys, func(i, j int) bool {
+		return len(groups[keys[i]].topics) > len(groups[keys[j]].topics)
+	})
+
+	var profileDefs []TopicProfileDefinition
+	inferredDefs := make([]TopicDefinition, len(topicDefs))
+	copy(inferredDefs, topicDefs)
+	for _, key := range keys {
+		g := groups[key]
+		if len(g.topics) < minProfileTopics {
+			continue
+		}
+
+		name := fmt.Sprintf("profile-%d", len(profileDefs)+1)
+		profileDefs = append(profileDefs, TopicProfileDefinition{
+			ResourceDefinition: ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       KindTopicProfile,
+				Metadata: ResourceMetadataDefinition{
+					Name: name,
+				},
+			},
+			Spec: TopicProfileSpecDefinition{
+				Configs: g.configs,
+			},
+		})
+		for _, i := range g.topics {
+			inferredDefs[i].Spec.Configs = nil
+			inferredDefs[i].Spec.Profiles = []string{name}
+		}
+	}
+
+	return profileDefs, inferredDefs
+}
+
+// configsKey returns a key identifying the keys and values of configs.
+func configsKey(configs ConfigsMap) string {
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		if value := configs[key]; value != nil {
+			fmt.Fprintf(&sb, "%q=%q\n", key, *value)
+		} else {
+			fmt.Fprintf(&sb, "%q\n", key)
+		}
+	}
+	return sb.String()
+}
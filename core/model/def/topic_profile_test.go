@@ -0,0 +1,209 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestTopicProfileDefinition_Validate(t *testing.T) {
+	resDef := ResourceDefinition{
+		APIVersion: "v1",
+		Kind:       KindTopicProfile,
+		Metadata: ResourceMetadataDefinition{
+			Name: "compacted",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		profileDef TopicProfileDefinition
+		wantErr    string
+	}{
+		{
+			name: "Tests a valid profile without defaults",
+			profileDef: TopicProfileDefinition{
+				ResourceDefinition: resDef,
+				Spec: TopicProfileSpecDefinition{
+					Configs: ConfigsMap{"cleanup.policy": strPtr("compact")},
+				},
+			},
+		},
+		{
+			name: "Tests invalid partitions",
+			profileDef: TopicProfileDefinition{
+				ResourceDefinition: resDef,
+				Spec: TopicProfileSpecDefinition{
+					Partitions: -1,
+				},
+			},
+			wantErr: "partitions must not be negative",
+		},
+		{
+			name: "Tests invalid replication factor",
+			profileDef: TopicProfileDefinition{
+				ResourceDefinition: resDef,
+				Spec: TopicProfileSpecDefinition{
+					ReplicationFactor: -1,
+				},
+			},
+			wantErr: "replication factor must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.profileDef.Validate(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("TopicProfileDefinition.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTopicDefinition_ResolveProfiles(t *testing.T) {
+	profiles := map[string]TopicProfileDefinition{
+		"compacted": {
+			Spec: TopicProfileSpecDefinition{
+				Configs: ConfigsMap{
+					"cleanup.policy":      strPtr("compact"),
+					"min.insync.replicas": strPtr("2"),
+				},
+				ReplicationFactor: 3,
+			},
+		},
+		"large": {
+			Spec: TopicProfileSpecDefinition{
+				Configs: ConfigsMap{
+					"min.insync.replicas": strPtr("1"),
+					"segment.bytes":       strPtr("1073741824"),
+				},
+				Partitions: 24,
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    TopicSpecDefinition
+		want    TopicSpecDefinition
+		wantErr string
+	}{
+		{
+			name: "Tests resolving profiles in order with local overrides",
+			spec: TopicSpecDefinition{
+				Profiles:          []string{"compacted", "large"},
+				Configs:           ConfigsMap{"segment.bytes": strPtr("536870912")},
+				ReplicationFactor: 2,
+			},
+			want: TopicSpecDefinition{
+				Configs: ConfigsMap{
+					"cleanup.policy":      strPtr("compact"),
+					"min.insync.replicas": strPtr("1"),
+					"segment.bytes":       strPtr("536870912"),
+				},
+				Partitions:        24,
+				ReplicationFactor: 2,
+			},
+		},
+		{
+			name: "Tests a spec without profiles",
+			spec: TopicSpecDefinition{
+				Partitions:        3,
+				ReplicationFactor: 1,
+			},
+			want: TopicSpecDefinition{
+				Partitions:        3,
+				ReplicationFactor: 1,
+			},
+		},
+		{
+			name: "Tests an undefined profile",
+			spec: TopicSpecDefinition{
+				Profiles: []string{"compacted", "foo"},
+			},
+			wantErr: "topic profile \"foo\" is not defined",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topicDef := TopicDefinition{Spec: tt.spec}
+			err := topicDef.ResolveProfiles(profiles)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("TopicDefinition.ResolveProfiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(tt.wantErr) == 0 && !reflect.DeepEqual(topicDef.Spec, tt.want) {
+				t.Errorf("TopicDefinition.ResolveProfiles() = %v, want %v", topicDef.Spec, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferTopicProfiles(t *testing.T) {
+	compacted := ConfigsMap{"cleanup.policy": strPtr("compact")}
+	events := ConfigsMap{"retention.ms": strPtr("604800000"), "cleanup.policy": strPtr("delete")}
+	topic := func(name string, configs ConfigsMap) TopicDefinition {
+		return TopicDefinition{
+			ResourceDefinition: ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       KindTopic,
+				Metadata:   ResourceMetadataDefinition{Name: name},
+			},
+			Spec: TopicSpecDefinition{
+				Configs:           configs,
+				Partitions:        3,
+				ReplicationFactor: 2,
+			},
+		}
+	}
+	withProfile := func(topicDef TopicDefinition, profile string) TopicDefinition {
+		topicDef.Spec.Configs = nil
+		topicDef.Spec.Profiles = []string{profile}
+		return topicDef
+	}
+
+	topicDefs := []TopicDefinition{
+		topic("a", compacted),
+		topic("b", events),
+		topic("c", ConfigsMap{"cleanup.policy": strPtr("compact")}),
+		topic("d", ConfigsMap{"retention.ms": strPtr("604800000"), "cleanup.policy": strPtr("delete")}),
+		topic("e", ConfigsMap{"cleanup.policy": strPtr("compact"), "retention.ms": strPtr("1")}),
+		topic("f", events),
+		topic("g", nil),
+	}
+
+	profileDefs, inferredDefs := InferTopicProfiles(topicDefs)
+
+	wantProfiles := []string{"profile-1", "profile-2"}
+	var gotProfiles []string
+	for _, p := range profileDefs {
+		gotProfiles = append(gotProfiles, p.Metadata.Name)
+	}
+	if !reflect.DeepEqual(gotProfiles, wantProfiles) {
+		t.Fatalf("InferTopicProfiles() profiles = %v, want %v", gotProfiles, wantProfiles)
+	}
+	if !reflect.DeepEqual(profileDefs[0].Spec.Configs, events) || !reflect.DeepEqual(profileDefs[1].Spec.Configs, compacted) {
+		t.Errorf("InferTopicProfiles() profile configs = %v, %v", profileDefs[0].Spec.Configs, profileDefs[1].Spec.Configs)
+	}
+
+	wantDefs := []TopicDefinition{
+		withProfile(topicDefs[0], "profile-2"),
+		withProfile(topicDefs[1], "profile-1"),
+		withProfile(topicDefs[2], "profile-2"),
+		withProfile(topicDefs[3], "profile-1"),
+		topicDefs[4],
+		withProfile(topicDefs[5], "profile-1"),
+		topicDefs[6],
+	}
+	if !reflect.DeepEqual(inferredDefs, wantDefs) {
+		t.Errorf("InferTopicProfiles() = %v, want %v", inferredDefs, wantDefs)
+	}
+	if topicDefs[0].Spec.Configs == nil {
+		t.Errorf("InferTopicProfiles() modified the topic definitions")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
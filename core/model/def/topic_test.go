@@ -31,6 +31,18 @@ func TestTopicDefinition_Validate(t *testing.T) {
 			},
 			wantErr: "partitions must be greater than 0",
 		},
+		{
+			name: "Tests unresolved profiles",
+			topicDef: TopicDefinition{
+				ResourceDefinition: resDef,
+				Spec: TopicSpecDefinition{
+					Profiles:          []string{"compacted", "large"},
+					Partitions:        3,
+					ReplicationFactor: 2,
+				},
+			},
+			wantErr: "profiles \"compacted,large\" must be resolved",
+		},
 		{
 			name: "Tests invalid spec replication factor",
 			topicDef: TopicDefinition{
@@ -215,6 +227,28 @@ func TestTopicDefinition_Validate(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "Tests invalid spec state",
+			topicDef: TopicDefinition{
+				ResourceDefinition: resDef,
+				Spec: TopicSpecDefinition{
+					State:             "foo",
+					Partitions:        3,
+					ReplicationFactor: 2,
+				},
+			},
+			wantErr: "state must be one of",
+		},
+		{
+			name: "Tests a valid absent TopicDefinition",
+			topicDef: TopicDefinition{
+				ResourceDefinition: resDef,
+				Spec: TopicSpecDefinition{
+					State: StateAbsent,
+				},
+			},
+			wantErr: "",
+		},
 		{
 			name: "Tests a valid TopicDefinition with managed assignments default",
 			topicDef: TopicDefinition{
@@ -459,7 +493,7 @@ func TestLoadTopicDefinition(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := LoadTopicDefinition(tt.args.defDoc, tt.args.format, tt.args.propOverrides)
+			got, err := LoadTopicDefinition(tt.args.defDoc, tt.args.format, tt.args.propOverrides, nil)
 			if !tutil.ErrorContains(err, tt.wantErr) {
 				t.Errorf("LoadTopicDefinition() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,164 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gotidy/copy"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// KindUser represents the user definition kind.
+const KindUser string = "user"
+
+// SCRAM mechanisms.
+const (
+	SCRAMSHA256 = "SCRAM-SHA-256"
+	SCRAMSHA512 = "SCRAM-SHA-512"
+)
+
+var scramMechanisms = []string{
+	SCRAMSHA256,
+	SCRAMSHA512,
+}
+
+// SCRAM iteration bounds enforced by Kafka.
+const (
+	scramMinIterations = 4096
+	scramMaxIterations = 16384
+)
+
+// PasswordRefDefinition represents a reference to a password stored outside the definition.
+type PasswordRefDefinition struct {
+	FromEnv  string `json:"fromEnv,omitempty"`
+	FromFile string `json:"fromFile,omitempty"`
+}
+
+// SCRAMCredentialDefinition represents a SCRAM credential definition.
+type SCRAMCredentialDefinition struct {
+	Mechanism  string                 `json:"mechanism"`
+	Iterations int                    `json:"iterations"`
+	Password   *PasswordRefDefinition `json:"password,omitempty"`
+	// Rotate upserts the credential on every apply, setting the password from its current source.
+	// Kafka does not expose passwords, so changes to the password source are otherwise not detected.
+	Rotate bool `json:"rotate,omitempty"`
+}
+
+// SCRAMCredentials represents a slice of SCRAM credential definitions.
+type SCRAMCredentials []SCRAMCredentialDefinition
+
+// Get returns the credential for a mechanism if it exists.
+func (s SCRAMCredentials) Get(mechanism string) (SCRAMCredentialDefinition, bool) {
+	for _, c := range s {
+		if c.Mechanism == mechanism {
+			return c, true
+		}
+	}
+	return SCRAMCredentialDefinition{}, false
+}
+
+// Sort sorts SCRAM credentials by mechanism.
+func (s SCRAMCredentials) Sort() {
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Mechanism < s[j].Mechanism
+	})
+}
+
+// UserSpecDefinition represents a user spec definition.
+type UserSpecDefinition struct {
+	SCRAMCredentials           SCRAMCredentials `json:"scramCredentials,omitempty"`
+	DeleteUndefinedCredentials bool             `json:"deleteUndefinedCredentials"`
+}
+
+// UserDefinition represents a user resource definition.
+type UserDefinition struct {
+	ResourceDefinition
+	Spec UserSpecDefinition `json:"spec"`
+}
+
+// Copy creates a copy of this UserDefinition.
+func (u UserDefinition) Copy() UserDefinition {
+	copiers := copy.New()
+	copier := copiers.Get(&UserDefinition{}, &UserDefinition{})
+	var userDefCopy UserDefinition
+	copier.Copy(&userDefCopy, &u)
+	return userDefCopy
+}
+
+// Validate validates the definition.
+func (u UserDefinition) Validate() error {
+	if err := u.ValidateResource(); err != nil {
+		return err
+	}
+
+	mechanisms := map[string]bool{}
+	for _, c := range u.Spec.SCRAMCredentials {
+		if !str.Contains(c.Mechanism, scramMechanisms) {
+			return fmt.Errorf("scram credential mechanism must be one of %q", strings.Join(scramMechanisms, "|"))
+		}
+		if mechanisms[c.Mechanism] {
+			return fmt.Errorf("scram credential mechanism %q cannot be defined more than once", c.Mechanism)
+		}
+		mechanisms[c.Mechanism] = true
+
+		if c.Iterations < scramMinIterations || c.Iterations > scramMaxIterations {
+			return fmt.Errorf(
+				"scram credential iterations must be between %d and %d",
+				scramMinIterations,
+				scramMaxIterations,
+			)
+		}
+
+		if c.Password == nil || (len(c.Password.FromEnv) == 0) == (len(c.Password.FromFile) == 0) {
+			return fmt.Errorf("scram credential password must specify one of fromEnv or fromFile")
+		}
+	}
+
+	return nil
+}
+
+// NewUserDefinition creates a user definition from metadata and SCRAM credentials.
+func NewUserDefinition(
+	metadata ResourceMetadataDefinition,
+	scramCredentials SCRAMCredentials,
+) UserDefinition {
+	userDef := UserDefinition{
+		ResourceDefinition: ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       KindUser,
+			Metadata:   metadata,
+		},
+		Spec: UserSpecDefinition{
+			SCRAMCredentials: scramCredentials,
+		},
+	}
+
+	return userDef
+}
+
+// LoadUserDefinition loads a user definition from a document.
+func LoadUserDefinition(
+	defDoc string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) (UserDefinition, error) {
+	var def UserDefinition
+
+	if err := unmarshalDefinition(defDoc, format, lenientAPIVersions, &def); err != nil {
+		return def, err
+	}
+
+	// Set defaults
+	for i := range def.Spec.SCRAMCredentials {
+		if def.Spec.SCRAMCredentials[i].Iterations == 0 {
+			def.Spec.SCRAMCredentials[i].Iterations = scramMinIterations
+		}
+	}
+
+	def.Spec.SCRAMCredentials.Sort()
+
+	return def, nil
+}
@@ -0,0 +1,180 @@
+// Package def implements definitions for Kafka resources.
+package def
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestUserDefinition_Validate(t *testing.T) {
+	resDef := ResourceDefinition{
+		APIVersion: "v1",
+		Kind:       KindUser,
+		Metadata: ResourceMetadataDefinition{
+			Name: "foo",
+		},
+	}
+	password := &PasswordRefDefinition{FromEnv: "FOO_PASSWORD"}
+
+	tests := []struct {
+		name    string
+		userDef UserDefinition
+		wantErr string
+	}{
+		{
+			name: "Tests invalid mechanism",
+			userDef: UserDefinition{
+				ResourceDefinition: resDef,
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{Mechanism: "SCRAM-SHA-1", Iterations: 4096, Password: password},
+					},
+				},
+			},
+			wantErr: "scram credential mechanism must be one of",
+		},
+		{
+			name: "Tests duplicate mechanism",
+			userDef: UserDefinition{
+				ResourceDefinition: resDef,
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{Mechanism: SCRAMSHA256, Iterations: 4096, Password: password},
+						{Mechanism: SCRAMSHA256, Iterations: 8192, Password: password},
+					},
+				},
+			},
+			wantErr: "scram credential mechanism \"SCRAM-SHA-256\" cannot be defined more than once",
+		},
+		{
+			name: "Tests iterations out of bounds",
+			userDef: UserDefinition{
+				ResourceDefinition: resDef,
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{Mechanism: SCRAMSHA512, Iterations: 100, Password: password},
+					},
+				},
+			},
+			wantErr: "scram credential iterations must be between 4096 and 16384",
+		},
+		{
+			name: "Tests missing password",
+			userDef: UserDefinition{
+				ResourceDefinition: resDef,
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{Mechanism: SCRAMSHA512, Iterations: 4096},
+					},
+				},
+			},
+			wantErr: "scram credential password must specify one of fromEnv or fromFile",
+		},
+		{
+			name: "Tests password with both fromEnv and fromFile",
+			userDef: UserDefinition{
+				ResourceDefinition: resDef,
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{
+							Mechanism:  SCRAMSHA512,
+							Iterations: 4096,
+							Password: &PasswordRefDefinition{
+								FromEnv:  "FOO_PASSWORD",
+								FromFile: "/run/secrets/foo",
+							},
+						},
+					},
+				},
+			},
+			wantErr: "scram credential password must specify one of fromEnv or fromFile",
+		},
+		{
+			name: "Tests a valid UserDefinition",
+			userDef: UserDefinition{
+				ResourceDefinition: resDef,
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{Mechanism: SCRAMSHA256, Iterations: 4096, Password: password},
+						{
+							Mechanism:  SCRAMSHA512,
+							Iterations: 8192,
+							Password:   &PasswordRefDefinition{FromFile: "/run/secrets/foo"},
+						},
+					},
+					DeleteUndefinedCredentials: true,
+				},
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.userDef.Validate(); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("UserDefinition.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadUserDefinition(t *testing.T) {
+	type args struct {
+		defDoc string
+		format opt.DefinitionFormat
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    UserDefinition
+		wantErr string
+	}{
+		{
+			name: "Tests loading a valid user definition with defaults",
+			args: args{
+				defDoc: "apiVersion: v1\nkind: user\nmetadata:\n  name: foo\nspec:\n  scramCredentials:\n" +
+					"  - mechanism: SCRAM-SHA-512\n    password:\n      fromEnv: FOO_PASSWORD\n" +
+					"  - mechanism: SCRAM-SHA-256\n    iterations: 8192\n    password:\n      fromEnv: FOO_PASSWORD",
+				format: opt.YAMLFormat,
+			},
+			want: UserDefinition{
+				ResourceDefinition: ResourceDefinition{
+					APIVersion: "v1",
+					Kind:       KindUser,
+					Metadata: ResourceMetadataDefinition{
+						Name: "foo",
+					},
+				},
+				Spec: UserSpecDefinition{
+					SCRAMCredentials: SCRAMCredentials{
+						{
+							Mechanism:  SCRAMSHA256,
+							Iterations: 8192,
+							Password:   &PasswordRefDefinition{FromEnv: "FOO_PASSWORD"},
+						},
+						{
+							Mechanism:  SCRAMSHA512,
+							Iterations: 4096,
+							Password:   &PasswordRefDefinition{FromEnv: "FOO_PASSWORD"},
+						},
+					},
+				},
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadUserDefinition(tt.args.defDoc, tt.args.format, nil)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("LoadUserDefinition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadUserDefinition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
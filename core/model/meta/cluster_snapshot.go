@@ -0,0 +1,43 @@
+// Package meta implements metadata structures and related operations.
+package meta
+
+import "sync"
+
+// ClusterSnapshot represents a snapshot of cluster metadata shared between appliers.
+// Appliers update the replica counts as they plan operations so that balance decisions
+// are consistent across definitions. The snapshot must be locked while in use.
+type ClusterSnapshot struct {
+	mu sync.Mutex
+
+	Brokers          Brokers
+	TopicAssignments map[string][][]int32
+	ReplicaCounts    map[int32]int
+}
+
+// NewClusterSnapshot creates a cluster snapshot from brokers and the partition assignments of topics.
+func NewClusterSnapshot(brokers Brokers, topicAssignments map[string][][]int32) *ClusterSnapshot {
+	replicaCounts := make(map[int32]int)
+	for _, assignments := range topicAssignments {
+		for _, replicas := range assignments {
+			for _, brokerID := range replicas {
+				replicaCounts[brokerID]++
+			}
+		}
+	}
+
+	return &ClusterSnapshot{
+		Brokers:          brokers,
+		TopicAssignments: topicAssignments,
+		ReplicaCounts:    replicaCounts,
+	}
+}
+
+// Lock locks the snapshot for exclusive use.
+func (c *ClusterSnapshot) Lock() {
+	c.mu.Lock()
+}
+
+// Unlock unlocks the snapshot.
+func (c *ClusterSnapshot) Unlock() {
+	c.mu.Unlock()
+}
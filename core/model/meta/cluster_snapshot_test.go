@@ -0,0 +1,36 @@
+// Package meta implements metadata structures and related operations.
+package meta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewClusterSnapshot(t *testing.T) {
+	tests := []struct {
+		name             string
+		topicAssignments map[string][][]int32
+		want             map[int32]int
+	}{
+		{
+			name:             "Test replica counts of no topics",
+			topicAssignments: map[string][][]int32{},
+			want:             map[int32]int{},
+		},
+		{
+			name: "Test replica counts of topics",
+			topicAssignments: map[string][][]int32{
+				"foo": {{1, 2}, {2, 3}, {3, 1}},
+				"bar": {{1}, {1}},
+			},
+			want: map[int32]int{1: 4, 2: 2, 3: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClusterSnapshot(nil, tt.topicAssignments).ReplicaCounts; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewClusterSnapshot().ReplicaCounts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -37,3 +37,15 @@ func (d DefinitionFormat) Ext() string {
 		return "unsupported"
 	}
 }
+
+// String returns the name of the definition format.
+func (d DefinitionFormat) String() string {
+	switch d {
+	case YAMLFormat:
+		return "yaml"
+	case JSONFormat:
+		return "json"
+	default:
+		return "unsupported"
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/meta"
 )
 
@@ -16,6 +17,19 @@ type ApplyResult struct {
 	Diff      string      `json:"diff"`
 	Err       string      `json:"error"`
 	Applied   bool        `json:"applied"`
+	// The position of the definition document in its source, if known.
+	Source *def.Source `json:"source,omitempty"`
+	// The labels of the resource definition.
+	Labels def.ResourceMetadataLabels `json:"labels,omitempty"`
+	// The paths of the unknown fields ignored in a definition with a lenient apiVersion.
+	IgnoredFields []string `json:"ignoredFields,omitempty"`
+
+	// Plan fields.
+	Fingerprint string      `json:"-"`
+	Operations  interface{} `json:"-"`
+
+	// Drift fields.
+	Drift *Drift `json:"-"`
 }
 
 // GetErr returns the error of an apply.
@@ -0,0 +1,159 @@
+// Package res implements structures handling the result of operations.
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+// Drift statuses.
+const (
+	DriftStatusInSync     = "in-sync"
+	DriftStatusDrifted    = "drifted"
+	DriftStatusMissing    = "missing"
+	DriftStatusUnexpected = "unexpected"
+	DriftStatusError      = "error"
+)
+
+// ValueDrift represents a remote value that differs from the value defined locally.
+type ValueDrift struct {
+	Name   string      `json:"name"`
+	Local  interface{} `json:"local"`
+	Remote interface{} `json:"remote"`
+	Source string      `json:"source,omitempty"`
+}
+
+// String returns a human readable representation of the value drift.
+func (v ValueDrift) String() string {
+	s := fmt.Sprintf("%s: remote %s, local %s", v.Name, driftValueString(v.Remote), driftValueString(v.Local))
+	if len(v.Source) > 0 {
+		s += fmt.Sprintf(" (%s)", v.Source)
+	}
+	return s
+}
+
+func driftValueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "<none>"
+	case *string:
+		if val == nil {
+			return "<none>"
+		}
+		return *val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// Drift represents the drift of a remote resource from its definition.
+type Drift struct {
+	Kind              string             `json:"kind"`
+	Type              string             `json:"type,omitempty"`
+	Name              string             `json:"name"`
+	Status            string             `json:"status"`
+	Configs           []ValueDrift       `json:"configs,omitempty"`
+	Partitions        *ValueDrift        `json:"partitions,omitempty"`
+	ReplicationFactor *ValueDrift        `json:"replicationFactor,omitempty"`
+	Assignments       []ValueDrift       `json:"assignments,omitempty"`
+	Leaders           []ValueDrift       `json:"leaders,omitempty"`
+	MissingACLs       def.ACLEntryGroups `json:"missingAcls,omitempty"`
+	ExtraACLs         def.ACLEntryGroups `json:"extraAcls,omitempty"`
+	Values            []ValueDrift       `json:"values,omitempty"`
+	Error             string             `json:"error,omitempty"`
+}
+
+// HasDrift determines if the resource has drifted or its drift could not be determined.
+func (d Drift) HasDrift() bool {
+	return d.Status != DriftStatusInSync
+}
+
+// Details returns human readable lines describing the drift.
+func (d Drift) Details() []string {
+	var details []string
+	for _, v := range d.Configs {
+		details = append(details, "config "+v.String())
+	}
+	if d.Partitions != nil {
+		details = append(details, d.Partitions.String())
+	}
+	if d.ReplicationFactor != nil {
+		details = append(details, d.ReplicationFactor.String())
+	}
+	for _, v := range d.Assignments {
+		details = append(details, "assignment of partition "+v.String())
+	}
+	for _, v := range d.Leaders {
+		details = append(details, "leader of partition "+v.String())
+	}
+	for _, acl := range d.MissingACLs {
+		details = append(details, "missing acl "+aclEntryString(acl))
+	}
+	for _, acl := range d.ExtraACLs {
+		details = append(details, "extra acl "+aclEntryString(acl))
+	}
+	for _, v := range d.Values {
+		details = append(details, v.String())
+	}
+	if len(d.Error) > 0 {
+		details = append(details, d.Error)
+	}
+	return details
+}
+
+func aclEntryString(acl def.ACLEntryGroup) string {
+	return fmt.Sprintf(
+		"%s %s %s from %s",
+		strings.Join(acl.Principals, ","),
+		acl.PermissionType,
+		strings.Join(acl.Operations, ","),
+		strings.Join(acl.Hosts, ","),
+	)
+}
+
+// DriftReport represents a slice of Drift.
+type DriftReport []Drift
+
+// ContainsDrift determines if any resource has drifted or its drift could not be determined.
+func (d DriftReport) ContainsDrift() bool {
+	for _, drift := range d {
+		if drift.HasDrift() {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON converts the drift report to JSON.
+func (d DriftReport) JSON() (string, error) {
+	j, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	return string(j), nil
+}
+
+// Table converts the drift report to a table.
+func (d DriftReport) Table() string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Kind", "Name", "Status", "Details"})
+	for _, drift := range d {
+		name := drift.Name
+		if len(drift.Type) > 0 {
+			name = drift.Type + ":" + name
+		}
+		t.AppendRow(table.Row{
+			drift.Kind,
+			name,
+			drift.Status,
+			strings.Join(drift.Details(), "\n"),
+		})
+	}
+	t.SetStyle(table.StyleLight)
+	return t.Render()
+}
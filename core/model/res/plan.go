@@ -0,0 +1,75 @@
+// Package res implements structures handling the result of operations.
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+// PlanVersion represents the version of the plan file format.
+const PlanVersion = 1
+
+// PlannedApply represents the planned apply of a resource definition.
+type PlannedApply struct {
+	Kind              string   `json:"kind"`
+	Name              string   `json:"name"`
+	Format            string   `json:"format"`
+	Definition        string   `json:"definition"`
+	PropertyOverrides []string `json:"propertyOverrides,omitempty"`
+	// The lenient apiVersions the definition was loaded with, if any.
+	LenientAPIVersions []string        `json:"lenientApiVersions,omitempty"`
+	Fingerprint        string          `json:"fingerprint"`
+	Operations         json.RawMessage `json:"operations"`
+	Diff               string          `json:"diff"`
+	// The position of the definition document in its source, if known.
+	Source *def.Source `json:"source,omitempty"`
+}
+
+// Plan represents the planned applies of resource definitions against a cluster.
+type Plan struct {
+	Version   int            `json:"version"`
+	ClusterID string         `json:"clusterId"`
+	Applies   []PlannedApply `json:"applies"`
+}
+
+// ContainsChanges determines if any planned apply has changes.
+func (p Plan) ContainsChanges() bool {
+	for _, apply := range p.Applies {
+		if len(apply.Diff) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the plan to a file.
+func (p Plan) Save(path string) error {
+	j, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(j, '\n'), 0o600)
+}
+
+// LoadPlan reads a plan from a file.
+func LoadPlan(path string) (Plan, error) {
+	var plan Plan
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return plan, err
+	}
+
+	if err := json.Unmarshal(b, &plan); err != nil {
+		return plan, fmt.Errorf("invalid plan: %v", err)
+	}
+
+	if plan.Version != PlanVersion {
+		return plan, fmt.Errorf("unsupported plan version %d", plan.Version)
+	}
+
+	return plan, nil
+}
@@ -3,13 +3,17 @@ package acl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
 	"github.com/peter-evans/kdef/core/helpers/acls"
+	"github.com/peter-evans/kdef/core/helpers/drift"
 	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
 	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/model/res"
@@ -17,9 +21,16 @@ import (
 
 // ApplierOptions represents options to configure an applier.
 type ApplierOptions struct {
-	DefinitionFormat  opt.DefinitionFormat
-	PropertyOverrides []string
-	DryRun            bool
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
 }
 
 // NewApplier creates a new applier.
@@ -32,6 +43,8 @@ func NewApplier(
 		srv:    kafka.NewService(cl),
 		defDoc: defDoc,
 		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindACL, opts.DryRun),
 	}
 }
 
@@ -45,11 +58,24 @@ func (a applierOps) pending() bool {
 		len(a.deleteACLs) > 0
 }
 
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		AddACLs    def.ACLEntryGroups `json:"addAcls,omitempty"`
+		DeleteACLs def.ACLEntryGroups `json:"deleteAcls,omitempty"`
+	}{
+		AddACLs:    a.addACLs,
+		DeleteACLs: a.deleteACLs,
+	})
+}
+
 type applier struct {
 	// Constructor fields.
 	srv    *kafka.Service
 	defDoc string
 	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
 
 	// Internal fields.
 	localDef   def.ACLDefinition
@@ -63,13 +89,18 @@ type applier struct {
 
 // Execute executes the applier.
 func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
 	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
 		a.res.Err = err.Error()
-		log.Error(err)
+		a.log.Error(err)
 	} else if a.ops.pending() && !a.opts.DryRun {
 		a.res.Applied = true
 	}
 
+	a.events.Completed(ctx, &a.res)
+
 	return &a.res
 }
 
@@ -78,8 +109,9 @@ func (a *applier) apply(ctx context.Context) error {
 	if err := a.createLocal(); err != nil {
 		return err
 	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
 
-	log.Debugf("Validating acl definition")
+	a.log.Debugf("Validating acl definition")
 	if err := a.localDef.Validate(); err != nil {
 		return err
 	}
@@ -96,18 +128,27 @@ func (a *applier) apply(ctx context.Context) error {
 		return err
 	}
 
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
 	if a.ops.pending() {
-		if !log.Quiet {
-			a.displayPendingOps()
-		}
+		a.displayPendingOps()
 
 		if err := a.executeOps(ctx); err != nil {
 			return err
 		}
 
-		log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for acl definition %q", a.localDef.Metadata.Name)
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for acl definition %q", a.localDef.Metadata.Name)
 	} else {
-		log.Infof("No changes to apply for acl definition %q", a.localDef.Metadata.Name)
+		a.log.Infof("No changes to apply for acl definition %q", a.localDef.Metadata.Name)
 	}
 
 	return nil
@@ -116,11 +157,16 @@ func (a *applier) apply(ctx context.Context) error {
 // createLocal creates the local definition.
 func (a *applier) createLocal() error {
 	var err error
-	a.localDef, err = def.LoadACLDefinition(a.defDoc, a.opts.DefinitionFormat)
+	a.localDef, err = def.LoadACLDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.LenientAPIVersions)
 	if err != nil {
 		return err
 	}
 
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
 	// Explode the local acl entry groups to one entry per group.
 	var explodedACLs def.ACLEntryGroups
 	for _, group := range a.localDef.Spec.ACLs {
@@ -147,7 +193,7 @@ func (a *applier) createLocal() error {
 
 // fetchRemote fetches the remote definition and necessary metadata.
 func (a *applier) fetchRemote(ctx context.Context) error {
-	log.Infof("Fetching remote ACLs...")
+	a.log.Infof("Fetching remote ACLs...")
 	var err error
 	a.remoteACLs, err = a.srv.DescribeResourceACLs(
 		ctx,
@@ -187,6 +233,9 @@ func (a *applier) updateApplyResult() error {
 		remoteCopy.Spec.ACLs = intersection
 	}
 
+	// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+	remoteCopy.APIVersion = a.localDef.APIVersion
+
 	diff, err := jsondiff.Diff(&remoteCopy, &a.localDef)
 	if err != nil {
 		return fmt.Errorf("failed to compute diff: %v", err)
@@ -202,22 +251,34 @@ func (a *applier) updateApplyResult() error {
 	return nil
 }
 
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	return &res.Drift{
+		Kind:        def.KindACL,
+		Type:        a.localDef.Metadata.Type,
+		Name:        a.localDef.Metadata.Name,
+		Status:      drift.Status(a.ops.pending()),
+		MissingACLs: a.ops.addACLs,
+		ExtraACLs:   a.ops.deleteACLs,
+	}
+}
+
 // displayPendingOps displays pending operations.
 func (a *applier) displayPendingOps() {
-	log.Infof("acl definition %q diff (local -> remote):", a.localDef.Metadata.Name)
-	fmt.Println(a.res.Diff)
+	a.log.Infof("acl definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
 }
 
 // executeOps executes update operations.
 func (a *applier) executeOps(ctx context.Context) error {
 	if len(a.ops.addACLs) > 0 {
-		if err := a.addACLs(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "addACLs", a.addACLs); err != nil {
 			return err
 		}
 	}
 
 	if len(a.ops.deleteACLs) > 0 {
-		if err := a.deleteACLs(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "deleteACLs", a.deleteACLs); err != nil {
 			return err
 		}
 	}
@@ -227,19 +288,23 @@ func (a *applier) executeOps(ctx context.Context) error {
 
 // buildACLOps builds acl operations.
 func (a *applier) buildACLOps() error {
-	log.Debugf("Comparing local and remote ACLs for acl definition %q", a.localDef.Metadata.Name)
+	a.log.Debugf("Comparing local and remote ACLs for acl definition %q", a.localDef.Metadata.Name)
 
 	a.ops.addACLs, _ = acls.DiffPatchIntersection(a.localDef.Spec.ACLs, a.remoteACLs)
 	if a.localDef.Spec.DeleteUndefinedACLs {
 		a.ops.deleteACLs, _ = acls.DiffPatchIntersection(a.remoteACLs, a.localDef.Spec.ACLs)
 	}
 
+	// Sort to make operations independent of the order of remote ACLs.
+	a.ops.addACLs.Sort()
+	a.ops.deleteACLs.Sort()
+
 	return nil
 }
 
 // addACLs adds ACLs.
 func (a *applier) addACLs(ctx context.Context) error {
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Adding ACLs...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Adding ACLs...")
 
 	if !a.opts.DryRun {
 		if err := a.srv.CreateACLs(
@@ -252,14 +317,14 @@ func (a *applier) addACLs(ctx context.Context) error {
 			return err
 		}
 	}
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Added ACLs for acl definition %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Added ACLs for acl definition %q", a.localDef.Metadata.Name)
 
 	return nil
 }
 
 // deleteACLs deletes ACLs.
 func (a *applier) deleteACLs(ctx context.Context) error {
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Deleting ACLs...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Deleting ACLs...")
 
 	if !a.opts.DryRun {
 		if err := a.srv.DeleteACLs(
@@ -272,7 +337,7 @@ func (a *applier) deleteACLs(ctx context.Context) error {
 			return err
 		}
 	}
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Deleted ACLs for acl definition %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Deleted ACLs for acl definition %q", a.localDef.Metadata.Name)
 
 	return nil
 }
@@ -5,10 +5,10 @@ import (
 	"context"
 	"regexp"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
 	"github.com/peter-evans/kdef/core/helpers/acls"
 	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/res"
 )
@@ -19,6 +19,7 @@ type ExporterOptions struct {
 	Exclude      string
 	ResourceType string
 	AutoGroup    bool
+	Logger       *log.Logger
 }
 
 // NewExporter creates a new exporter.
@@ -29,17 +30,19 @@ func NewExporter(
 	return &exporter{
 		srv:  kafka.NewService(cl),
 		opts: opts,
+		log:  opts.Logger,
 	}
 }
 
 type exporter struct {
 	srv  *kafka.Service
 	opts ExporterOptions
+	log  *log.Logger
 }
 
 // Execute executes the export operation.
 func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
-	log.Infof("Fetching remote ACLs...")
+	e.log.Infof("Fetching remote ACLs...")
 	aclDefs, err := e.getACLDefinitions(ctx)
 	if err != nil {
 		return nil, err
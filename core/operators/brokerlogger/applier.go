@@ -0,0 +1,350 @@
+// Package brokerlogger implements operators for broker logger definition operations.
+package brokerlogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/helpers/drift"
+	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// loggerLevelUnset represents the level of a logger that does not exist on a broker.
+const loggerLevelUnset = "UNSET"
+
+// ApplierOptions represents options to configure an applier.
+type ApplierOptions struct {
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
+}
+
+// NewApplier creates a new applier.
+func NewApplier(
+	cl *client.Client,
+	defDoc string,
+	opts ApplierOptions,
+) *applier { //revive:disable-line:unexported-return
+	return &applier{
+		srv:    kafka.NewService(cl),
+		defDoc: defDoc,
+		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindBrokerLogger, opts.DryRun),
+	}
+}
+
+type applierOps struct {
+	// Logger operations by broker ID.
+	logger map[string]kafka.ConfigOperations
+}
+
+func (a applierOps) pending() bool {
+	for _, ops := range a.logger {
+		if len(ops) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Logger map[string]kafka.ConfigOperations `json:"logger,omitempty"`
+	}{
+		Logger: a.logger,
+	})
+}
+
+type applier struct {
+	// Constructor fields.
+	srv    *kafka.Service
+	defDoc string
+	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
+
+	// Internal fields.
+	localDef      def.BrokerLoggerDefinition
+	remoteDef     def.BrokerLoggerDefinition
+	brokerIDs     []string
+	remoteLoggers map[string]def.LoggersMap
+	ops           applierOps
+
+	// Result fields.
+	res res.ApplyResult
+}
+
+// Execute executes the applier.
+func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
+	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
+		a.res.Err = err.Error()
+		a.log.Error(err)
+	} else if a.ops.pending() && !a.opts.DryRun {
+		a.res.Applied = true
+	}
+
+	a.events.Completed(ctx, &a.res)
+
+	return &a.res
+}
+
+// apply performs the apply operation sequence.
+func (a *applier) apply(ctx context.Context) error {
+	if err := a.createLocal(); err != nil {
+		return err
+	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
+
+	a.log.Debugf("Validating broker logger definition")
+	if err := a.localDef.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.fetchRemote(ctx); err != nil {
+		return err
+	}
+
+	a.buildOps()
+
+	if err := a.updateApplyResult(); err != nil {
+		return err
+	}
+
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
+	if a.ops.pending() {
+		a.displayPendingOps()
+
+		if err := a.events.ExecuteOp(ctx, "alterLoggers", a.executeOps); err != nil {
+			return err
+		}
+
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for broker logger definition %q", a.localDef.Metadata.Name)
+	} else {
+		a.log.Infof("No changes to apply for broker logger definition %q", a.localDef.Metadata.Name)
+	}
+
+	return nil
+}
+
+// createLocal creates the local definition.
+func (a *applier) createLocal() error {
+	var err error
+	a.localDef, err = def.LoadBrokerLoggerDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.LenientAPIVersions)
+	if err != nil {
+		return err
+	}
+
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
+	a.res.LocalDef = &a.localDef
+
+	return nil
+}
+
+// fetchRemote fetches the remote definition and necessary metadata.
+func (a *applier) fetchRemote(ctx context.Context) error {
+	a.log.Infof("Fetching cluster metadata...")
+	metadata, err := a.srv.DescribeMetadata(ctx, []string{}, true)
+	if err != nil {
+		return err
+	}
+
+	a.log.Debugf("Validating broker logger definition using cluster metadata")
+	if err := a.localDef.ValidateWithMetadata(metadata.Brokers); err != nil {
+		return err
+	}
+
+	if a.localDef.IsAllBrokers() {
+		for _, id := range metadata.Brokers.IDs() {
+			a.brokerIDs = append(a.brokerIDs, fmt.Sprint(id))
+		}
+	} else {
+		a.brokerIDs = []string{a.localDef.Metadata.Name}
+	}
+
+	a.log.Infof("Fetching remote broker loggers...")
+	a.remoteLoggers = map[string]def.LoggersMap{}
+	for _, brokerID := range a.brokerIDs {
+		configs, err := a.srv.DescribeBrokerLoggerConfigs(ctx, brokerID)
+		if err != nil {
+			return err
+		}
+		loggers := def.LoggersMap{}
+		for _, config := range configs {
+			if config.Value != nil {
+				loggers[config.Name] = *config.Value
+			}
+		}
+		a.remoteLoggers[brokerID] = loggers
+	}
+
+	a.remoteDef = def.NewBrokerLoggerDefinition(a.localDef.Metadata, a.aggregateRemoteLoggers())
+
+	return nil
+}
+
+// aggregateRemoteLoggers returns the levels of locally defined loggers across the target brokers.
+// Differing levels are joined with "|" so that brokers out of step with each other are shown in the diff.
+func (a *applier) aggregateRemoteLoggers() def.LoggersMap {
+	loggers := def.LoggersMap{}
+	for logger := range a.localDef.Spec.Loggers {
+		levelSet := map[string]bool{}
+		for _, brokerID := range a.brokerIDs {
+			if level, ok := a.remoteLoggers[brokerID][logger]; ok {
+				levelSet[level] = true
+			} else {
+				levelSet[loggerLevelUnset] = true
+			}
+		}
+
+		// The logger does not exist on any broker.
+		if len(levelSet) == 1 && levelSet[loggerLevelUnset] {
+			continue
+		}
+
+		levels := make([]string, 0, len(levelSet))
+		for level := range levelSet {
+			levels = append(levels, level)
+		}
+		sort.Strings(levels)
+		loggers[logger] = strings.Join(levels, "|")
+	}
+	return loggers
+}
+
+// buildOps builds broker logger operations.
+func (a *applier) buildOps() {
+	a.log.Debugf("Comparing local and remote loggers for broker logger definition %q", a.localDef.Metadata.Name)
+
+	a.ops.logger = map[string]kafka.ConfigOperations{}
+	for _, brokerID := range a.brokerIDs {
+		var ops kafka.ConfigOperations
+		for logger, level := range a.localDef.Spec.Loggers {
+			remoteLevel, ok := a.remoteLoggers[brokerID][logger]
+			if ok && remoteLevel == level {
+				continue
+			}
+			a.log.Debugf("Level of logger %q on broker %s will be set to %q", logger, brokerID, level)
+			value := level
+			ops = append(ops, kafka.ConfigOperation{
+				Name:  logger,
+				Value: &value,
+				Op:    kafka.SetConfigOperation,
+			})
+		}
+		ops.Sort()
+		a.ops.logger[brokerID] = ops
+	}
+}
+
+// updateApplyResult updates the apply result with the remote definition and human readable diff.
+func (a *applier) updateApplyResult() error {
+	remoteCopy := a.remoteDef.Copy()
+
+	// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+	remoteCopy.APIVersion = a.localDef.APIVersion
+
+	diff, err := jsondiff.Diff(&remoteCopy, &a.localDef)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %v", err)
+	}
+
+	if diffExists := (len(diff) > 0); diffExists != a.ops.pending() {
+		return fmt.Errorf("existence of diff was %v, but expected %v", diffExists, a.ops.pending())
+	}
+
+	a.res.RemoteDef = remoteCopy
+	a.res.Diff = diff
+
+	return nil
+}
+
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	var values []res.ValueDrift
+	for _, brokerID := range a.brokerIDs {
+		for _, op := range a.ops.logger[brokerID] {
+			var remote interface{}
+			if level, ok := a.remoteLoggers[brokerID][op.Name]; ok {
+				remote = level
+			}
+			values = append(values, res.ValueDrift{
+				Name:   fmt.Sprintf("%s (broker %s)", op.Name, brokerID),
+				Local:  op.Value,
+				Remote: remote,
+			})
+		}
+	}
+
+	return &res.Drift{
+		Kind:   def.KindBrokerLogger,
+		Name:   a.localDef.Metadata.Name,
+		Status: drift.Status(a.ops.pending()),
+		Values: values,
+	}
+}
+
+// displayPendingOps displays pending operations.
+func (a *applier) displayPendingOps() {
+	a.log.Infof("broker logger definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
+}
+
+// executeOps executes update operations.
+func (a *applier) executeOps(ctx context.Context) error {
+	for _, brokerID := range a.brokerIDs {
+		ops := a.ops.logger[brokerID]
+		if len(ops) == 0 {
+			continue
+		}
+
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering loggers of broker %s...", brokerID)
+		if err := a.srv.AlterBrokerLoggerConfigs(
+			ctx,
+			brokerID,
+			ops,
+			a.opts.DryRun,
+		); err != nil {
+			return err
+		}
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered loggers of broker %s", brokerID)
+	}
+
+	return nil
+}
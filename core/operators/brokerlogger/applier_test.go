@@ -0,0 +1,225 @@
+//go:build integration
+
+// Package brokerlogger implements operators for broker logger definition operations.
+package brokerlogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/compose"
+	"github.com/peter-evans/kdef/core/test/harness"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+// VERBOSE_TESTS=1 go test --tags=integration -run ^Test_applier_Execute$ ./core/operators/brokerlogger -v
+func Test_applier_Execute(t *testing.T) {
+	_, log.Verbose = os.LookupEnv("VERBOSE_TESTS")
+
+	type fields struct {
+		cl      *client.Client
+		yamlDoc string
+		opts    ApplierOptions
+	}
+	type testCase struct {
+		name        string
+		fields      fields
+		wantDiff    string
+		wantErr     string
+		wantApplied bool
+	}
+
+	ctx := context.Background()
+
+	runTests := func(t *testing.T, tests []testCase) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				a := NewApplier(tt.fields.cl, tt.fields.yamlDoc, tt.fields.opts)
+				got := a.Execute(ctx)
+
+				if log.Verbose {
+					// Output apply result JSON
+					jsonOut, err := json.MarshalIndent(got, "", "  ")
+					if err != nil {
+						t.Errorf("failed to convert apply result to json: %v", err)
+						t.FailNow()
+					}
+					fmt.Println("[test] ApplyResult JSON:")
+					fmt.Println(string(jsonOut))
+				}
+
+				if got.Diff != tt.wantDiff {
+					t.Errorf("applier.Execute().Diff = %v, want %v", got.Diff, tt.wantDiff)
+				}
+				if !tutil.ErrorContains(got.GetErr(), tt.wantErr) {
+					t.Errorf("applier.Execute() error = %v, wantErr %v", got.GetErr(), tt.wantErr)
+				}
+				if got.Applied != tt.wantApplied {
+					t.Errorf("applier.Execute().Applied = %v, want %v", got.Applied, tt.wantApplied)
+				}
+
+				// Sleep to give Kafka time to update internally
+				time.Sleep(2 * time.Second)
+			})
+		}
+	}
+
+	getDiffsFixture := func(t *testing.T, path string) []string {
+		var diffs []string
+		if err := json.Unmarshal(tutil.Fixture(t, path), &diffs); err != nil {
+			t.Errorf("failed to unmarshal JSON test fixture: %v", err)
+			t.FailNow()
+		}
+		return diffs
+	}
+
+	// Create client
+	cl := tutil.CreateClient(t,
+		[]string{fmt.Sprintf("seedBrokers=localhost:%d", harness.BrokerLoggerApplier.BrokerPort)},
+	)
+
+	// Create the test cluster
+	srv := kafka.NewService(cl)
+	maxTries := 3
+	try := 1
+	for {
+		start := time.Now()
+		c := compose.Up(
+			t,
+			harness.BrokerLoggerApplier.ComposeFilePaths,
+			harness.BrokerLoggerApplier.Env(),
+		)
+		if srv.IsKafkaReady(ctx, harness.BrokerLoggerApplier.Brokers, 90) {
+			duration := time.Since(start)
+			log.Infof("kafka cluster ready in %v", duration)
+			break
+		} else {
+			log.Warnf("kafka failed to be ready within timeout")
+			compose.Down(t, c)
+			try++
+		}
+		if try > maxTries {
+			t.Errorf("kafka failed to be ready within timeout after %d tries", maxTries)
+			t.FailNow()
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	// Tests changes to logger levels
+	brokerLogger1Docs := tutil.FileToYAMLDocs(t, "../../test/fixtures/brokerlogger/core.operators.brokerlogger.applier.1.yml")
+	brokerLogger1Diffs := getDiffsFixture(t, "../../test/fixtures/brokerlogger/core.operators.brokerlogger.applier.1.json")
+	runTests(t, []testCase{
+		// NOTE: Execution of tests is ordered
+		{
+			// Raise logger level
+			name: "1: Dry-run broker logger 1 version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[0],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Raise logger level
+			name: "2: Apply broker logger 1 version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[0],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "3: Dry-run broker logger 1 version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[2],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Revert logger level on all brokers
+			name: "4: Dry-run broker logger all version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[1],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Revert logger level on all brokers
+			name: "5: Apply broker logger all version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[1],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "6: Dry-run broker logger all version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[2],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Unavailable broker
+			name: "7: Dry-run broker logger 9 version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: brokerLogger1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    brokerLogger1Diffs[2],
+			wantErr:     "metadata name must be the id of an available broker",
+			wantApplied: false,
+		},
+	})
+}
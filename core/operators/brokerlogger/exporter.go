@@ -0,0 +1,109 @@
+// Package brokerlogger implements operators for broker logger definition operations.
+package brokerlogger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ExporterOptions represents options to configure an exporter.
+type ExporterOptions struct {
+	Logger *log.Logger
+}
+
+// NewExporter creates a new exporter.
+func NewExporter(
+	cl *client.Client,
+	opts ExporterOptions,
+) *exporter { //revive:disable-line:unexported-return
+	return &exporter{
+		srv: kafka.NewService(cl),
+		log: opts.Logger,
+	}
+}
+
+type exporter struct {
+	// constructor params
+	srv *kafka.Service
+	log *log.Logger
+}
+
+// Execute executes the export operation.
+func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
+	e.log.Infof("Fetching remote broker loggers...")
+	brokerLoggerDefs, err := e.getBrokerLoggerDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(brokerLoggerDefs) == 0 {
+		// Should not happen. There should always be at least one broker.
+		return nil, nil
+	}
+
+	results := make(res.ExportResults, len(brokerLoggerDefs))
+	for i, brokerLoggerDef := range brokerLoggerDefs {
+		results[i] = res.ExportResult{
+			ID:  brokerLoggerDef.Metadata.Name,
+			Def: brokerLoggerDef,
+		}
+	}
+
+	results.Sort()
+
+	return results, nil
+}
+
+func (e *exporter) getBrokerLoggerDefinitions(ctx context.Context) ([]def.BrokerLoggerDefinition, error) {
+	metadata, err := e.srv.DescribeMetadata(ctx, []string{}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	brokerLoggerDefs := []def.BrokerLoggerDefinition{}
+	for _, broker := range metadata.Brokers {
+		brokerIDStr := fmt.Sprint(broker.ID)
+		configs, err := e.srv.DescribeBrokerLoggerConfigs(ctx, brokerIDStr)
+		if err != nil {
+			return nil, err
+		}
+		brokerLoggerDefs = append(
+			brokerLoggerDefs, def.NewBrokerLoggerDefinition(
+				def.ResourceMetadataDefinition{
+					Name: brokerIDStr,
+				},
+				exportableLoggers(configs),
+			),
+		)
+	}
+
+	return brokerLoggerDefs, nil
+}
+
+// exportableLoggers returns the root logger and loggers with a level that differs from the root logger.
+// Brokers report hundreds of loggers, the vast majority of which inherit the root level.
+func exportableLoggers(configs def.Configs) def.LoggersMap {
+	loggers := configs.ToMap()
+
+	var rootLevel string
+	if level, ok := loggers[def.BrokerLoggerRoot]; ok && level != nil {
+		rootLevel = *level
+	}
+
+	exportable := def.LoggersMap{}
+	for logger, level := range loggers {
+		if level == nil {
+			continue
+		}
+		if logger == def.BrokerLoggerRoot || *level != rootLevel {
+			exportable[logger] = *level
+		}
+	}
+	return exportable
+}
@@ -0,0 +1,111 @@
+//go:build integration
+
+// Package brokerlogger implements operators for broker logger definition operations.
+package brokerlogger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/compose"
+	"github.com/peter-evans/kdef/core/test/harness"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+// VERBOSE_TESTS=1 go test --tags=integration -run ^Test_exporter_Execute$ ./core/operators/brokerlogger -v
+func Test_exporter_Execute(t *testing.T) {
+	_, log.Verbose = os.LookupEnv("VERBOSE_TESTS")
+
+	// Create client
+	cl := tutil.CreateClient(t,
+		[]string{fmt.Sprintf("seedBrokers=localhost:%d", harness.BrokerLoggerExporter.BrokerPort)},
+	)
+
+	ctx := context.Background()
+
+	// Create the test cluster
+	srv := kafka.NewService(cl)
+	maxTries := 3
+	try := 1
+	for {
+		start := time.Now()
+		c := compose.Up(
+			t,
+			harness.BrokerLoggerExporter.ComposeFilePaths,
+			harness.BrokerLoggerExporter.Env(),
+		)
+		if srv.IsKafkaReady(ctx, harness.BrokerLoggerExporter.Brokers, 90) {
+			duration := time.Since(start)
+			log.Infof("kafka cluster ready in %v", duration)
+			break
+		} else {
+			log.Warnf("kafka failed to be ready within timeout")
+			compose.Down(t, c)
+			try++
+		}
+		if try > maxTries {
+			t.Errorf("kafka failed to be ready within timeout after %d tries", maxTries)
+			t.FailNow()
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	// Load YAML doc test fixtures
+	yamlDocs := tutil.FileToYAMLDocs(t, "../../test/fixtures/brokerlogger/core.operators.brokerlogger.applier.1.yml")
+
+	// Apply the fixture raising the level of a logger
+	applier := NewApplier(cl, yamlDocs[0], ApplierOptions{
+		DefinitionFormat: opt.YAMLFormat,
+	})
+	if err := applier.Execute(ctx).GetErr(); err != nil {
+		t.Errorf("failed to apply fixture: %v", err)
+		t.FailNow()
+	}
+
+	// Sleep to give Kafka time to update internally
+	time.Sleep(2 * time.Second)
+
+	// The full set of loggers depends on the broker's log4j configuration,
+	// so only the root logger and the raised logger are checked.
+	e := NewExporter(cl, ExporterOptions{})
+	got, err := e.Execute(ctx)
+	if err != nil {
+		t.Errorf("exporter.Execute() error = %v", err)
+		t.FailNow()
+	}
+	if len(got) != 1 {
+		t.Errorf("exporter.Execute() returned %d result(s), want %d", len(got), 1)
+		t.FailNow()
+	}
+	if got[0].ID != "1" {
+		t.Errorf("exporter.Execute()[0].ID = %v, want %v", got[0].ID, "1")
+	}
+	brokerLoggerDef, ok := got[0].Def.(def.BrokerLoggerDefinition)
+	if !ok {
+		t.Errorf("exporter.Execute()[0].Def is not a broker logger definition")
+		t.FailNow()
+	}
+	if level := brokerLoggerDef.Spec.Loggers["kafka.log.LogCleaner"]; level != "DEBUG" {
+		t.Errorf("exported level of logger %q = %v, want %v", "kafka.log.LogCleaner", level, "DEBUG")
+	}
+	if _, ok := brokerLoggerDef.Spec.Loggers[def.BrokerLoggerRoot]; !ok {
+		t.Errorf("exported loggers do not contain the root logger")
+	}
+
+	if log.Verbose {
+		j, err := got.JSON()
+		if err != nil {
+			t.Errorf("failed to convert export result to json: %v", err)
+			t.FailNow()
+		}
+		fmt.Println("[test] ExportResults JSON:")
+		fmt.Println(j)
+	}
+}
@@ -3,13 +3,17 @@ package brokers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/helpers/drift"
 	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
 	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/model/res"
@@ -17,9 +21,16 @@ import (
 
 // ApplierOptions represents options to configure an applier.
 type ApplierOptions struct {
-	DefinitionFormat  opt.DefinitionFormat
-	PropertyOverrides []string
-	DryRun            bool
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
 }
 
 // NewApplier creates a new applier.
@@ -32,6 +43,8 @@ func NewApplier(
 		srv:    kafka.NewService(cl),
 		defDoc: defDoc,
 		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindBrokers, opts.DryRun),
 	}
 }
 
@@ -43,11 +56,22 @@ func (a applierOps) pending() bool {
 	return len(a.config) > 0
 }
 
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Config kafka.ConfigOperations `json:"config,omitempty"`
+	}{
+		Config: a.config,
+	})
+}
+
 type applier struct {
 	// Constructor fields.
 	srv    *kafka.Service
 	defDoc string
 	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
 
 	// Internal fields.
 	localDef      def.BrokersDefinition
@@ -61,13 +85,18 @@ type applier struct {
 
 // Execute executes the applier.
 func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
 	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
 		a.res.Err = err.Error()
-		log.Error(err)
+		a.log.Error(err)
 	} else if a.ops.pending() && !a.opts.DryRun {
 		a.res.Applied = true
 	}
 
+	a.events.Completed(ctx, &a.res)
+
 	return &a.res
 }
 
@@ -76,8 +105,9 @@ func (a *applier) apply(ctx context.Context) error {
 	if err := a.createLocal(); err != nil {
 		return err
 	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
 
-	log.Debugf("Validating brokers definition")
+	a.log.Debugf("Validating brokers definition")
 	if err := a.localDef.Validate(); err != nil {
 		return err
 	}
@@ -94,18 +124,27 @@ func (a *applier) apply(ctx context.Context) error {
 		return err
 	}
 
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
 	if a.ops.pending() {
-		if !log.Quiet {
-			a.displayPendingOps()
-		}
+		a.displayPendingOps()
 
 		if err := a.executeOps(ctx); err != nil {
 			return err
 		}
 
-		log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for brokers definition %q", a.localDef.Metadata.Name)
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for brokers definition %q", a.localDef.Metadata.Name)
 	} else {
-		log.Infof("No changes to apply for brokers definition %q", a.localDef.Metadata.Name)
+		a.log.Infof("No changes to apply for brokers definition %q", a.localDef.Metadata.Name)
 	}
 
 	return nil
@@ -114,11 +153,16 @@ func (a *applier) apply(ctx context.Context) error {
 // createLocal creates the local definition.
 func (a *applier) createLocal() error {
 	var err error
-	a.localDef, err = def.LoadBrokersDefinition(a.defDoc, a.opts.DefinitionFormat)
+	a.localDef, err = def.LoadBrokersDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.LenientAPIVersions)
 	if err != nil {
 		return err
 	}
 
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
 	a.res.LocalDef = &a.localDef
 
 	return nil
@@ -126,7 +170,7 @@ func (a *applier) createLocal() error {
 
 // fetchRemote fetches the remote definition and necessary metadata.
 func (a *applier) fetchRemote(ctx context.Context) error {
-	log.Infof("Fetching remote cluster-wide broker configuration...")
+	a.log.Infof("Fetching remote cluster-wide broker configuration...")
 	var err error
 	a.remoteConfigs, err = a.srv.DescribeAllBrokerConfigs(ctx)
 	if err != nil {
@@ -166,6 +210,9 @@ func (a *applier) updateApplyResult() error {
 
 	remoteCopy.Spec.DeleteUndefinedConfigs = a.localDef.Spec.DeleteUndefinedConfigs
 
+	// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+	remoteCopy.APIVersion = a.localDef.APIVersion
+
 	diff, err := jsondiff.Diff(&remoteCopy, &a.localDef)
 	if err != nil {
 		return fmt.Errorf("failed to compute diff: %v", err)
@@ -181,16 +228,26 @@ func (a *applier) updateApplyResult() error {
 	return nil
 }
 
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	return &res.Drift{
+		Kind:    def.KindBrokers,
+		Name:    a.localDef.Metadata.Name,
+		Status:  drift.Status(a.ops.pending()),
+		Configs: drift.Configs(a.ops.config, a.remoteConfigs),
+	}
+}
+
 // displayPendingOps displays pending operations.
 func (a *applier) displayPendingOps() {
-	log.Infof("brokers definition %q diff (local -> remote):", a.localDef.Metadata.Name)
-	fmt.Println(a.res.Diff)
+	a.log.Infof("brokers definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
 }
 
 // executeOps executes update operations.
 func (a *applier) executeOps(ctx context.Context) error {
 	if len(a.ops.config) > 0 {
-		if err := a.updateConfigs(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "alterConfigs", a.updateConfigs); err != nil {
 			return err
 		}
 	}
@@ -200,7 +257,7 @@ func (a *applier) executeOps(ctx context.Context) error {
 
 // buildConfigOps builds alter configs operations.
 func (a *applier) buildConfigOps(ctx context.Context) error {
-	log.Debugf("Comparing local and remote configs for brokers definition %q", a.localDef.Metadata.Name)
+	a.log.Debugf("Comparing local and remote configs for brokers definition %q", a.localDef.Metadata.Name)
 
 	var err error
 	a.ops.config, err = a.srv.NewConfigOps(
@@ -224,7 +281,7 @@ func (a *applier) updateConfigs(ctx context.Context) error {
 		return errors.New("cannot apply configs because deletion of undefined configs is not enabled")
 	}
 
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering configs...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering configs...")
 	if err := a.srv.AlterAllBrokerConfigs(
 		ctx,
 		a.ops.config,
@@ -232,7 +289,7 @@ func (a *applier) updateConfigs(ctx context.Context) error {
 	); err != nil {
 		return err
 	}
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered configs for brokers definition %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered configs for brokers definition %q", a.localDef.Metadata.Name)
 
 	return nil
 }
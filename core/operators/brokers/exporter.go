@@ -4,29 +4,38 @@ package brokers
 import (
 	"context"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
 	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/res"
 )
 
+// ExporterOptions represents options to configure an exporter.
+type ExporterOptions struct {
+	Logger *log.Logger
+}
+
 // NewExporter creates a new exporter.
 func NewExporter(
 	cl *client.Client,
+	opts ExporterOptions,
 ) *exporter { //revive:disable-line:unexported-return
 	return &exporter{
 		srv: kafka.NewService(cl),
+		log: opts.Logger,
 	}
 }
 
 type exporter struct {
+	// constructor params
 	srv *kafka.Service
+	log *log.Logger
 }
 
 // Execute executes the export operation.
 func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
-	log.Infof("Fetching remote cluster-wide broker configuration...")
+	e.log.Infof("Fetching remote cluster-wide broker configuration...")
 	brokersDef, err := e.getBrokersDefinition(ctx)
 	if err != nil {
 		return nil, err
@@ -95,7 +95,7 @@ func Test_exporter_Execute(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			e := NewExporter(tt.fields.cl)
+			e := NewExporter(tt.fields.cl, ExporterOptions{})
 			got, err := e.Execute(ctx)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("exporter.Execute() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,395 @@
+// Package consumergroup implements operators for consumer group definition operations.
+package consumergroup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/helpers/drift"
+	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ApplierOptions represents options to configure an applier.
+type ApplierOptions struct {
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
+}
+
+// NewApplier creates a new applier.
+func NewApplier(
+	cl *client.Client,
+	defDoc string,
+	opts ApplierOptions,
+) *applier { //revive:disable-line:unexported-return
+	return &applier{
+		srv:    kafka.NewService(cl),
+		defDoc: defDoc,
+		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindConsumerGroup, opts.DryRun),
+	}
+}
+
+type applierOps struct {
+	commit kafka.PartitionOffsets
+}
+
+func (a applierOps) pending() bool {
+	return len(a.commit) > 0
+}
+
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Commit kafka.PartitionOffsets `json:"commit,omitempty"`
+	}{
+		Commit: a.commit,
+	})
+}
+
+type applier struct {
+	// Constructor fields.
+	srv    *kafka.Service
+	defDoc string
+	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
+
+	// Internal fields.
+	localDef  def.ConsumerGroupDefinition
+	remoteDef def.ConsumerGroupDefinition
+	group     kafka.GroupDescription
+	ops       applierOps
+
+	// Result fields.
+	res res.ApplyResult
+}
+
+// Execute executes the applier.
+func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
+	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
+		a.res.Err = err.Error()
+		a.log.Error(err)
+	} else if a.ops.pending() && !a.opts.DryRun {
+		a.res.Applied = true
+	}
+
+	a.events.Completed(ctx, &a.res)
+
+	return &a.res
+}
+
+// apply performs the apply operation sequence.
+func (a *applier) apply(ctx context.Context) error {
+	if err := a.createLocal(); err != nil {
+		return err
+	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
+
+	a.log.Debugf("Validating consumer group definition")
+	if err := a.localDef.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.fetchRemote(ctx); err != nil {
+		return err
+	}
+
+	if err := a.resolveTargetOffsets(ctx); err != nil {
+		return err
+	}
+
+	a.buildOps()
+
+	if err := a.updateApplyResult(); err != nil {
+		return err
+	}
+
+	if err := a.checkMembers(); err != nil {
+		return err
+	}
+
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
+	if a.ops.pending() {
+		a.displayPendingOps()
+
+		if err := a.events.ExecuteOp(ctx, "commitOffsets", a.executeOps); err != nil {
+			return err
+		}
+
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for consumer group definition %q", a.localDef.Metadata.Name)
+	} else {
+		a.log.Infof("No changes to apply for consumer group definition %q", a.localDef.Metadata.Name)
+	}
+
+	return nil
+}
+
+// checkMembers checks that offsets are not reset while the group has active members.
+// Offsets committed while the group has members would be overwritten by the members' own commits.
+// Active members are reported as a warning in dry-run mode so that resets can be reviewed in advance.
+func (a *applier) checkMembers() error {
+	if !a.ops.pending() || a.group.Members == 0 || a.opts.ReadOnly {
+		return nil
+	}
+
+	err := fmt.Errorf(
+		"consumer group %q has %d active member(s); offsets can only be reset when the group has no members",
+		a.localDef.Metadata.Name,
+		a.group.Members,
+	)
+	if !a.opts.DryRun {
+		return err
+	}
+	a.log.Warnf("%v", err)
+	return nil
+}
+
+// createLocal creates the local definition.
+func (a *applier) createLocal() error {
+	var err error
+	a.localDef, err = def.LoadConsumerGroupDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.LenientAPIVersions)
+	if err != nil {
+		return err
+	}
+
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
+	a.res.LocalDef = &a.localDef
+
+	return nil
+}
+
+// fetchRemote fetches the remote definition.
+func (a *applier) fetchRemote(ctx context.Context) error {
+	a.log.Infof("Fetching consumer group...")
+	var err error
+	a.group, err = a.srv.DescribeGroup(ctx, a.localDef.Metadata.Name)
+	if err != nil {
+		return err
+	}
+
+	a.log.Infof("Fetching committed offsets...")
+	remoteTopics, err := a.srv.FetchCommittedOffsets(ctx, a.localDef.Metadata.Name)
+	if err != nil {
+		return err
+	}
+
+	a.remoteDef = def.NewConsumerGroupDefinition(a.localDef.Metadata, remoteTopics)
+
+	return nil
+}
+
+// resolveTargetOffsets resolves the target offsets of partitions with earliest, latest and timestamp resets.
+func (a *applier) resolveTargetOffsets(ctx context.Context) error {
+	timestamps := kafka.PartitionOffsets{}
+	for _, t := range a.localDef.Spec.Topics {
+		for _, p := range t.Partitions {
+			switch p.ResetMode() {
+			case def.OffsetResetEarliest:
+				timestamps.Set(t.Name, p.Partition, kafka.ListOffsetsEarliest)
+			case def.OffsetResetLatest:
+				timestamps.Set(t.Name, p.Partition, kafka.ListOffsetsLatest)
+			case def.OffsetResetTimestamp:
+				timestamp, err := p.TimestampMillis()
+				if err != nil {
+					return err
+				}
+				timestamps.Set(t.Name, p.Partition, timestamp)
+			}
+		}
+	}
+
+	if len(timestamps) == 0 {
+		return nil
+	}
+
+	a.log.Infof("Listing target offsets...")
+	offsets, err := a.srv.ListOffsets(ctx, timestamps)
+	if err != nil {
+		return err
+	}
+
+	// A timestamp later than the newest record has no offset, so the latest offset is used instead.
+	latestTimestamps := kafka.PartitionOffsets{}
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			if offset < 0 {
+				latestTimestamps.Set(topic, partition, kafka.ListOffsetsLatest)
+			}
+		}
+	}
+	if len(latestTimestamps) > 0 {
+		latestOffsets, err := a.srv.ListOffsets(ctx, latestTimestamps)
+		if err != nil {
+			return err
+		}
+		for topic, partitions := range latestOffsets {
+			for partition, offset := range partitions {
+				offsets.Set(topic, partition, offset)
+			}
+		}
+	}
+
+	for i := range a.localDef.Spec.Topics {
+		t := &a.localDef.Spec.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if p.ResetMode() == def.OffsetResetAbsolute {
+				continue
+			}
+			offset, ok := offsets[t.Name][p.Partition]
+			if !ok {
+				return fmt.Errorf("failed to resolve target offset for topic %q partition %d", t.Name, p.Partition)
+			}
+			p.Offset = &offset
+		}
+	}
+
+	return nil
+}
+
+// buildOps builds offset commit operations.
+func (a *applier) buildOps() {
+	a.log.Debugf("Comparing local and remote offsets for consumer group %q", a.localDef.Metadata.Name)
+
+	a.ops.commit = kafka.PartitionOffsets{}
+	for _, t := range a.localDef.Spec.Topics {
+		for _, local := range t.Partitions {
+			remote, ok := a.remoteDef.Spec.Topics.GetPartition(t.Name, local.Partition)
+			if !ok || *remote.Offset != *local.Offset {
+				a.log.Debugf("Offset of topic %q partition %d will be committed", t.Name, local.Partition)
+				a.ops.commit.Set(t.Name, local.Partition, *local.Offset)
+			}
+		}
+	}
+}
+
+// updateApplyResult updates the apply result with the remote definition and human readable diff.
+func (a *applier) updateApplyResult() error {
+	remoteCopy := a.remoteDef.Copy()
+
+	// Modify the remote definition to remove optional properties not specified in local.
+	// Further, set properties that are local only and have no remote state.
+	var topics def.ConsumerGroupTopics
+	for _, t := range remoteCopy.Spec.Topics {
+		var partitions []def.ConsumerGroupPartitionDefinition
+		for _, p := range t.Partitions {
+			local, existsInLocal := a.localDef.Spec.Topics.GetPartition(t.Name, p.Partition)
+			if !existsInLocal {
+				continue
+			}
+			p.Reset = local.Reset
+			p.Timestamp = local.Timestamp
+			partitions = append(partitions, p)
+		}
+		if len(partitions) > 0 {
+			topics = append(topics, def.ConsumerGroupTopicDefinition{
+				Name:       t.Name,
+				Partitions: partitions,
+			})
+		}
+	}
+	remoteCopy.Spec.Topics = topics
+
+	// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+	remoteCopy.APIVersion = a.localDef.APIVersion
+
+	diff, err := jsondiff.Diff(&remoteCopy, &a.localDef)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %v", err)
+	}
+
+	if diffExists := (len(diff) > 0); diffExists != a.ops.pending() {
+		return fmt.Errorf("existence of diff was %v, but expected %v", diffExists, a.ops.pending())
+	}
+
+	a.res.RemoteDef = remoteCopy
+	a.res.Diff = diff
+
+	return nil
+}
+
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	var values []res.ValueDrift
+	for _, t := range a.localDef.Spec.Topics {
+		for _, local := range t.Partitions {
+			offset, ok := a.ops.commit[t.Name][local.Partition]
+			if !ok {
+				continue
+			}
+			var remote interface{}
+			if p, ok := a.remoteDef.Spec.Topics.GetPartition(t.Name, local.Partition); ok && p.Offset != nil {
+				remote = *p.Offset
+			}
+			values = append(values, res.ValueDrift{
+				Name:   fmt.Sprintf("%s/%d offset", t.Name, local.Partition),
+				Local:  offset,
+				Remote: remote,
+			})
+		}
+	}
+
+	return &res.Drift{
+		Kind:   def.KindConsumerGroup,
+		Name:   a.localDef.Metadata.Name,
+		Status: drift.Status(a.ops.pending()),
+		Values: values,
+	}
+}
+
+// displayPendingOps displays pending operations.
+func (a *applier) displayPendingOps() {
+	a.log.Infof("consumer group definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
+}
+
+// executeOps executes update operations.
+func (a *applier) executeOps(ctx context.Context) error {
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Committing offsets...")
+
+	// OffsetCommit has no 'ValidateOnly' for dry-run mode so the request is skipped.
+	if !a.opts.DryRun {
+		if err := a.srv.CommitOffsets(ctx, a.localDef.Metadata.Name, a.ops.commit); err != nil {
+			return err
+		}
+	}
+
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Committed offsets for consumer group %q", a.localDef.Metadata.Name)
+
+	return nil
+}
@@ -0,0 +1,259 @@
+//go:build integration
+
+// Package consumergroup implements operators for consumer group definition operations.
+package consumergroup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/operators/topic"
+	"github.com/peter-evans/kdef/core/test/compose"
+	"github.com/peter-evans/kdef/core/test/harness"
+	"github.com/peter-evans/kdef/core/test/tutil"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// VERBOSE_TESTS=1 go test --tags=integration -run ^Test_applier_Execute$ ./core/operators/consumergroup -v
+func Test_applier_Execute(t *testing.T) {
+	_, log.Verbose = os.LookupEnv("VERBOSE_TESTS")
+
+	type fields struct {
+		cl      *client.Client
+		yamlDoc string
+		opts    ApplierOptions
+	}
+	type testCase struct {
+		name        string
+		fields      fields
+		wantDiff    string
+		wantErr     string
+		wantApplied bool
+	}
+
+	ctx := context.Background()
+
+	runTests := func(t *testing.T, tests []testCase) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				a := NewApplier(tt.fields.cl, tt.fields.yamlDoc, tt.fields.opts)
+				got := a.Execute(ctx)
+
+				if log.Verbose {
+					// Output apply result JSON
+					jsonOut, err := json.MarshalIndent(got, "", "  ")
+					if err != nil {
+						t.Errorf("failed to convert apply result to json: %v", err)
+						t.FailNow()
+					}
+					fmt.Println("[test] ApplyResult JSON:")
+					fmt.Println(string(jsonOut))
+				}
+
+				if got.Diff != tt.wantDiff {
+					t.Errorf("applier.Execute().Diff = %v, want %v", got.Diff, tt.wantDiff)
+				}
+				if !tutil.ErrorContains(got.GetErr(), tt.wantErr) {
+					t.Errorf("applier.Execute() error = %v, wantErr %v", got.GetErr(), tt.wantErr)
+				}
+				if got.Applied != tt.wantApplied {
+					t.Errorf("applier.Execute().Applied = %v, want %v", got.Applied, tt.wantApplied)
+				}
+
+				// Sleep to give Kafka time to update internally
+				time.Sleep(2 * time.Second)
+			})
+		}
+	}
+
+	getDiffsFixture := func(t *testing.T, path string) []string {
+		var diffs []string
+		if err := json.Unmarshal(tutil.Fixture(t, path), &diffs); err != nil {
+			t.Errorf("failed to unmarshal JSON test fixture: %v", err)
+			t.FailNow()
+		}
+		return diffs
+	}
+
+	// Create client
+	cl := tutil.CreateClient(t,
+		[]string{fmt.Sprintf("seedBrokers=localhost:%d", harness.ConsumerGroupApplier.BrokerPort)},
+	)
+
+	// Create the test cluster
+	srv := kafka.NewService(cl)
+	maxTries := 3
+	try := 1
+	for {
+		start := time.Now()
+		c := compose.Up(
+			t,
+			harness.ConsumerGroupApplier.ComposeFilePaths,
+			harness.ConsumerGroupApplier.Env(),
+		)
+		if srv.IsKafkaReady(ctx, harness.ConsumerGroupApplier.Brokers, 90) {
+			duration := time.Since(start)
+			log.Infof("kafka cluster ready in %v", duration)
+			break
+		} else {
+			log.Warnf("kafka failed to be ready within timeout")
+			compose.Down(t, c)
+			try++
+		}
+		if try > maxTries {
+			t.Errorf("kafka failed to be ready within timeout after %d tries", maxTries)
+			t.FailNow()
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	// Create a topic containing records
+	createTopicWithRecords(ctx, t, cl, "orders", 10)
+
+	// Tests changes to committed offsets
+	group1Docs := tutil.FileToYAMLDocs(t, "../../test/fixtures/consumergroup/core.operators.consumergroup.applier.1.yml")
+	group1Diffs := getDiffsFixture(t, "../../test/fixtures/consumergroup/core.operators.consumergroup.applier.1.json")
+	runTests(t, []testCase{
+		// NOTE: Execution of tests is ordered
+		{
+			// Commit absolute offset
+			name: "1: Dry-run consumer group store-orders version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    group1Diffs[0],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Commit absolute offset
+			name: "2: Apply consumer group store-orders version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    group1Diffs[0],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "3: Dry-run consumer group store-orders version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    group1Diffs[3],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Reset to earliest
+			name: "4: Dry-run consumer group store-orders version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    group1Diffs[1],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Reset to earliest
+			name: "5: Apply consumer group store-orders version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    group1Diffs[1],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// Reset to latest
+			name: "6: Apply consumer group store-orders version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    group1Diffs[2],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// Reset to a timestamp later than the newest record resolves to latest
+			name: "7: Dry-run consumer group store-orders version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: group1Docs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    group1Diffs[3],
+			wantErr:     "",
+			wantApplied: false,
+		},
+	})
+}
+
+// createTopicWithRecords creates a single partition topic and produces records to it.
+func createTopicWithRecords(ctx context.Context, t *testing.T, cl *client.Client, name string, records int) {
+	topicDoc := fmt.Sprintf(
+		"apiVersion: v1\nkind: topic\nmetadata:\n  name: %s\nspec:\n  partitions: 1\n  replicationFactor: 1",
+		name,
+	)
+	applier := topic.NewApplier(cl, topicDoc, topic.ApplierOptions{
+		DefinitionFormat: opt.YAMLFormat,
+	})
+	if err := applier.Execute(ctx).GetErr(); err != nil {
+		t.Errorf("failed to create topic: %v", err)
+		t.FailNow()
+	}
+
+	// Sleep to give Kafka time to update internally
+	time.Sleep(2 * time.Second)
+
+	krecords := make([]*kgo.Record, records)
+	for i := range krecords {
+		krecords[i] = &kgo.Record{
+			Topic: name,
+			Value: []byte(fmt.Sprintf("record-%d", i)),
+		}
+	}
+	if err := cl.Client.ProduceSync(ctx, krecords...).FirstErr(); err != nil {
+		t.Errorf("failed to produce records: %v", err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,107 @@
+// Package consumergroup implements operators for consumer group definition operations.
+package consumergroup
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ExporterOptions represents options to configure an exporter.
+type ExporterOptions struct {
+	Match   string
+	Exclude string
+	Logger  *log.Logger
+}
+
+// NewExporter creates a new exporter.
+func NewExporter(
+	cl *client.Client,
+	opts ExporterOptions,
+) *exporter { //revive:disable-line:unexported-return
+	return &exporter{
+		srv:  kafka.NewService(cl),
+		opts: opts,
+		log:  opts.Logger,
+	}
+}
+
+type exporter struct {
+	srv  *kafka.Service
+	opts ExporterOptions
+	log  *log.Logger
+}
+
+// Execute executes the export operation.
+func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
+	e.log.Infof("Fetching consumer groups...")
+	consumerGroupDefs, err := e.getConsumerGroupDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(consumerGroupDefs) == 0 {
+		return nil, nil
+	}
+
+	results := make(res.ExportResults, len(consumerGroupDefs))
+	for i, consumerGroupDef := range consumerGroupDefs {
+		results[i] = res.ExportResult{
+			ID:  consumerGroupDef.Metadata.Name,
+			Def: consumerGroupDef,
+		}
+	}
+
+	results.Sort()
+
+	return results, nil
+}
+
+func (e *exporter) getConsumerGroupDefinitions(ctx context.Context) ([]def.ConsumerGroupDefinition, error) {
+	groups, err := e.srv.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matchRegExp, err := regexp.Compile(e.opts.Match)
+	if err != nil {
+		return nil, err
+	}
+	excludeRegExp, err := regexp.Compile(e.opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	consumerGroupDefs := []def.ConsumerGroupDefinition{}
+	for _, group := range groups {
+		if !matchRegExp.MatchString(group) {
+			continue
+		}
+		if excludeRegExp.MatchString(group) {
+			continue
+		}
+
+		topics, err := e.srv.FetchCommittedOffsets(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		// Groups without committed offsets have nothing to export.
+		if len(topics) == 0 {
+			continue
+		}
+
+		consumerGroupDefs = append(consumerGroupDefs, def.NewConsumerGroupDefinition(
+			def.ResourceMetadataDefinition{
+				Name: group,
+			},
+			topics,
+		))
+	}
+
+	return consumerGroupDefs, nil
+}
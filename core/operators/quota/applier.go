@@ -0,0 +1,313 @@
+// Package quota implements operators for quota definition operations.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/helpers/drift"
+	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ApplierOptions represents options to configure an applier.
+type ApplierOptions struct {
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
+}
+
+// NewApplier creates a new applier.
+func NewApplier(
+	cl *client.Client,
+	defDoc string,
+	opts ApplierOptions,
+) *applier { //revive:disable-line:unexported-return
+	return &applier{
+		srv:    kafka.NewService(cl),
+		defDoc: defDoc,
+		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindQuota, opts.DryRun),
+	}
+}
+
+type applierOps struct {
+	quota kafka.QuotaOperations
+}
+
+func (a applierOps) pending() bool {
+	return len(a.quota) > 0
+}
+
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Quota kafka.QuotaOperations `json:"quota,omitempty"`
+	}{
+		Quota: a.quota,
+	})
+}
+
+type applier struct {
+	// Constructor fields.
+	srv    *kafka.Service
+	defDoc string
+	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
+
+	// Internal fields.
+	localDef  def.QuotaDefinition
+	remoteDef def.QuotaDefinition
+	ops       applierOps
+
+	// Result fields.
+	res res.ApplyResult
+}
+
+// Execute executes the applier.
+func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
+	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
+		a.res.Err = err.Error()
+		a.log.Error(err)
+	} else if a.ops.pending() && !a.opts.DryRun {
+		a.res.Applied = true
+	}
+
+	a.events.Completed(ctx, &a.res)
+
+	return &a.res
+}
+
+// apply performs the apply operation sequence.
+func (a *applier) apply(ctx context.Context) error {
+	if err := a.createLocal(); err != nil {
+		return err
+	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
+
+	a.log.Debugf("Validating quota definition")
+	if err := a.localDef.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.fetchRemote(ctx); err != nil {
+		return err
+	}
+
+	a.buildOps()
+
+	if err := a.updateApplyResult(); err != nil {
+		return err
+	}
+
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
+	if a.ops.pending() {
+		a.displayPendingOps()
+
+		if err := a.executeOps(ctx); err != nil {
+			return err
+		}
+
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for quota definition %q", a.localDef.Metadata.Name)
+	} else {
+		a.log.Infof("No changes to apply for quota definition %q", a.localDef.Metadata.Name)
+	}
+
+	return nil
+}
+
+// createLocal creates the local definition.
+func (a *applier) createLocal() error {
+	var err error
+	a.localDef, err = def.LoadQuotaDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.LenientAPIVersions)
+	if err != nil {
+		return err
+	}
+
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
+	a.res.LocalDef = &a.localDef
+
+	return nil
+}
+
+// fetchRemote fetches the remote definition.
+func (a *applier) fetchRemote(ctx context.Context) error {
+	a.log.Infof("Fetching remote quotas for entity %q...", a.localDef.Spec.Entity)
+	remoteQuotas, err := a.srv.DescribeClientQuotas(ctx, a.localDef.Spec.Entity)
+	if err != nil {
+		return err
+	}
+
+	a.remoteDef = def.NewQuotaDefinition(a.localDef.Metadata, a.localDef.Spec.Entity, remoteQuotas)
+
+	return nil
+}
+
+// buildOps builds quota operations.
+func (a *applier) buildOps() {
+	a.log.Debugf("Comparing local and remote quotas for quota definition %q", a.localDef.Metadata.Name)
+
+	for _, k := range sortedKeys(a.localDef.Spec.Quotas) {
+		v := a.localDef.Spec.Quotas[k]
+		if rv, ok := a.remoteDef.Spec.Quotas[k]; ok {
+			if v != rv {
+				a.log.Debugf("Value of quota key %q has changed from %v to %v and will be updated", k, rv, v)
+				a.ops.quota = append(a.ops.quota, kafka.QuotaOperation{Key: k, Value: v})
+			}
+		} else {
+			a.log.Debugf("Quota key %q is missing from remote quotas and will be added", k)
+			a.ops.quota = append(a.ops.quota, kafka.QuotaOperation{Key: k, Value: v})
+		}
+	}
+
+	if a.localDef.Spec.DeleteUndefinedQuotas {
+		for _, k := range sortedKeys(a.remoteDef.Spec.Quotas) {
+			if _, ok := a.localDef.Spec.Quotas[k]; !ok {
+				a.log.Debugf("Quota key %q is missing from local definition and will be deleted", k)
+				a.ops.quota = append(a.ops.quota, kafka.QuotaOperation{Key: k, Remove: true})
+			}
+		}
+	}
+}
+
+// updateApplyResult updates the apply result with the remote definition and human readable diff.
+func (a *applier) updateApplyResult() error {
+	remoteCopy := a.remoteDef.Copy()
+
+	// Modify the remote definition to remove optional properties not specified in local.
+	// Further, set properties that are local only and have no remote state.
+
+	// The only quotas we want to see are those specified in local and those in quotaOps.
+	// quotaOps could contain key deletions that should be shown in the diff.
+	for k := range remoteCopy.Spec.Quotas {
+		_, existsInLocal := a.localDef.Spec.Quotas[k]
+		existsInOps := a.ops.quota.Contains(k)
+
+		if !existsInLocal && !existsInOps {
+			delete(remoteCopy.Spec.Quotas, k)
+		}
+	}
+
+	remoteCopy.Spec.DeleteUndefinedQuotas = a.localDef.Spec.DeleteUndefinedQuotas
+
+	// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+	remoteCopy.APIVersion = a.localDef.APIVersion
+
+	diff, err := jsondiff.Diff(&remoteCopy, &a.localDef)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %v", err)
+	}
+
+	if diffExists := (len(diff) > 0); diffExists != a.ops.pending() {
+		return fmt.Errorf("existence of diff was %v, but expected %v", diffExists, a.ops.pending())
+	}
+
+	a.res.RemoteDef = remoteCopy
+	a.res.Diff = diff
+
+	return nil
+}
+
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	var values []res.ValueDrift
+	for _, op := range a.ops.quota {
+		var local, remote interface{}
+		if !op.Remove {
+			local = op.Value
+		}
+		if v, ok := a.remoteDef.Spec.Quotas[op.Key]; ok {
+			remote = v
+		}
+		values = append(values, res.ValueDrift{
+			Name:   op.Key,
+			Local:  local,
+			Remote: remote,
+		})
+	}
+
+	return &res.Drift{
+		Kind:   def.KindQuota,
+		Name:   a.localDef.Metadata.Name,
+		Status: drift.Status(a.ops.pending()),
+		Values: values,
+	}
+}
+
+// displayPendingOps displays pending operations.
+func (a *applier) displayPendingOps() {
+	a.log.Infof("quota definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
+}
+
+// executeOps executes update operations.
+func (a *applier) executeOps(ctx context.Context) error {
+	if len(a.ops.quota) > 0 {
+		if err := a.events.ExecuteOp(ctx, "alterQuotas", a.updateQuotas); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateQuotas updates entity quotas.
+func (a *applier) updateQuotas(ctx context.Context) error {
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering quotas...")
+	if err := a.srv.AlterClientQuotas(
+		ctx,
+		a.localDef.Spec.Entity,
+		a.ops.quota,
+		a.opts.DryRun,
+	); err != nil {
+		return err
+	}
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered quotas for quota definition %q", a.localDef.Metadata.Name)
+
+	return nil
+}
+
+// sortedKeys returns the keys of a quotas map in sorted order.
+func sortedKeys(quotas def.QuotasMap) []string {
+	keys := make([]string, 0, len(quotas))
+	for k := range quotas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+//go:build integration
+
+// Package quota implements operators for quota definition operations.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/compose"
+	"github.com/peter-evans/kdef/core/test/harness"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+// VERBOSE_TESTS=1 go test --tags=integration -run ^Test_applier_Execute$ ./core/operators/quota -v
+func Test_applier_Execute(t *testing.T) {
+	_, log.Verbose = os.LookupEnv("VERBOSE_TESTS")
+
+	type fields struct {
+		cl      *client.Client
+		yamlDoc string
+		opts    ApplierOptions
+	}
+	type testCase struct {
+		name        string
+		fields      fields
+		wantDiff    string
+		wantErr     string
+		wantApplied bool
+	}
+
+	ctx := context.Background()
+
+	runTests := func(t *testing.T, tests []testCase) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				a := NewApplier(tt.fields.cl, tt.fields.yamlDoc, tt.fields.opts)
+				got := a.Execute(ctx)
+
+				if log.Verbose {
+					// Output apply result JSON
+					jsonOut, err := json.MarshalIndent(got, "", "  ")
+					if err != nil {
+						t.Errorf("failed to convert apply result to json: %v", err)
+						t.FailNow()
+					}
+					fmt.Println("[test] ApplyResult JSON:")
+					fmt.Println(string(jsonOut))
+				}
+
+				if got.Diff != tt.wantDiff {
+					t.Errorf("applier.Execute().Diff = %v, want %v", got.Diff, tt.wantDiff)
+				}
+				if !tutil.ErrorContains(got.GetErr(), tt.wantErr) {
+					t.Errorf("applier.Execute() error = %v, wantErr %v", got.GetErr(), tt.wantErr)
+				}
+				if got.Applied != tt.wantApplied {
+					t.Errorf("applier.Execute().Applied = %v, want %v", got.Applied, tt.wantApplied)
+				}
+
+				// Sleep to give Kafka time to update internally
+				time.Sleep(2 * time.Second)
+			})
+		}
+	}
+
+	getDiffsFixture := func(t *testing.T, path string) []string {
+		var diffs []string
+		if err := json.Unmarshal(tutil.Fixture(t, path), &diffs); err != nil {
+			t.Errorf("failed to unmarshal JSON test fixture: %v", err)
+			t.FailNow()
+		}
+		return diffs
+	}
+
+	// Create client
+	cl := tutil.CreateClient(t,
+		[]string{fmt.Sprintf("seedBrokers=localhost:%d", harness.QuotaApplier.BrokerPort)},
+	)
+
+	// Create the test cluster
+	srv := kafka.NewService(cl)
+	maxTries := 3
+	try := 1
+	for {
+		start := time.Now()
+		c := compose.Up(
+			t,
+			harness.QuotaApplier.ComposeFilePaths,
+			harness.QuotaApplier.Env(),
+		)
+		if srv.IsKafkaReady(ctx, harness.QuotaApplier.Brokers, 90) {
+			duration := time.Since(start)
+			log.Infof("kafka cluster ready in %v", duration)
+			break
+		} else {
+			log.Warnf("kafka failed to be ready within timeout")
+			compose.Down(t, c)
+			try++
+		}
+		if try > maxTries {
+			t.Errorf("kafka failed to be ready within timeout after %d tries", maxTries)
+			t.FailNow()
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	// Tests changes to quotas
+	quota1Docs := tutil.FileToYAMLDocs(t, "../../test/fixtures/quota/core.operators.quota.applier.1.yml")
+	quota1Diffs := getDiffsFixture(t, "../../test/fixtures/quota/core.operators.quota.applier.1.json")
+	runTests(t, []testCase{
+		// NOTE: Execution of tests is ordered
+		{
+			// Add quotas
+			name: "1: Dry-run quota user-alice version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    quota1Diffs[0],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Add quotas
+			name: "2: Apply quota user-alice version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    quota1Diffs[0],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// Update and delete quotas
+			name: "3: Dry-run quota user-alice version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    quota1Diffs[1],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Update and delete quotas
+			name: "4: Apply quota user-alice version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    quota1Diffs[1],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "5: Dry-run quota user-alice version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    quota1Diffs[2],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Default client-id entity
+			name: "6: Dry-run quota user-alice_default-client version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    quota1Diffs[3],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Default client-id entity
+			name: "7: Apply quota user-alice_default-client version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: quota1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    quota1Diffs[3],
+			wantErr:     "",
+			wantApplied: true,
+		},
+	})
+}
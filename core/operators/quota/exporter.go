@@ -0,0 +1,108 @@
+// Package quota implements operators for quota definition operations.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ExporterOptions represents options to configure an exporter.
+type ExporterOptions struct {
+	Logger *log.Logger
+}
+
+// NewExporter creates a new exporter.
+func NewExporter(
+	cl *client.Client,
+	opts ExporterOptions,
+) *exporter { //revive:disable-line:unexported-return
+	return &exporter{
+		srv: kafka.NewService(cl),
+		log: opts.Logger,
+	}
+}
+
+type exporter struct {
+	// constructor params
+	srv *kafka.Service
+	log *log.Logger
+}
+
+// Execute executes the export operation.
+func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
+	e.log.Infof("Fetching remote quotas...")
+	quotaDefs, err := e.getQuotaDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quotaDefs) == 0 {
+		return nil, nil
+	}
+
+	results := make(res.ExportResults, len(quotaDefs))
+	for i, quotaDef := range quotaDefs {
+		results[i] = res.ExportResult{
+			ID:  quotaDef.Metadata.Name,
+			Def: quotaDef,
+		}
+	}
+
+	results.Sort()
+
+	return results, nil
+}
+
+func (e *exporter) getQuotaDefinitions(ctx context.Context) ([]def.QuotaDefinition, error) {
+	entityQuotas, err := e.srv.DescribeAllClientQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaDefs := []def.QuotaDefinition{}
+	for _, eq := range entityQuotas {
+		if len(eq.Quotas) == 0 {
+			continue
+		}
+		quotaDef := def.NewQuotaDefinition(
+			def.ResourceMetadataDefinition{
+				Name: entityName(eq.Entity),
+			},
+			eq.Entity,
+			eq.Quotas,
+		)
+		// Default to delete undefined quotas.
+		quotaDef.Spec.DeleteUndefinedQuotas = true
+
+		quotaDefs = append(quotaDefs, quotaDef)
+	}
+
+	return quotaDefs, nil
+}
+
+// entityName creates a definition name from the components of an entity.
+// e.g. "user-alice", "default-user" and "user-alice_default-client".
+func entityName(entity def.QuotaEntityDefinition) string {
+	var components []string
+	add := func(prefix string, name string) {
+		switch name {
+		case "":
+			return
+		case def.QuotaEntityDefault:
+			components = append(components, fmt.Sprintf("default-%s", prefix))
+		default:
+			components = append(components, fmt.Sprintf("%s-%s", prefix, name))
+		}
+	}
+	add("user", entity.User)
+	add("client", entity.ClientID)
+	add("ip", entity.IP)
+	return strings.Join(components, "_")
+}
@@ -3,18 +3,21 @@ package topic
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/jedib0t/go-pretty/v6/table"
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
 	"github.com/peter-evans/kdef/core/helpers/assignments"
+	"github.com/peter-evans/kdef/core/helpers/drift"
 	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
 	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/meta"
 	"github.com/peter-evans/kdef/core/model/opt"
@@ -24,10 +27,18 @@ import (
 
 // ApplierOptions represents options to configure an applier.
 type ApplierOptions struct {
-	DefinitionFormat  opt.DefinitionFormat
-	PropertyOverrides []string
-	DryRun            bool
-	ReassAwaitTimeout int
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	ReassAwaitTimeout  int
+	ClusterSnapshot    *meta.ClusterSnapshot
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
 }
 
 // NewApplier creates a new applier.
@@ -40,11 +51,14 @@ func NewApplier(
 		srv:    kafka.NewService(cl),
 		defDoc: defDoc,
 		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindTopic, opts.DryRun),
 	}
 }
 
 type applierOps struct {
 	create            bool
+	delete            bool
 	createAssignments def.PartitionAssignments
 	config            kafka.ConfigOperations
 	partitions        def.PartitionAssignments
@@ -57,17 +71,43 @@ type applierOps struct {
 
 func (a applierOps) pending() bool {
 	return a.create ||
+		a.delete ||
 		len(a.config) > 0 ||
 		len(a.partitions) > 0 ||
 		len(a.assignments) > 0 ||
 		len(a.leaderElection.partitions) > 0
 }
 
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Create                   bool                     `json:"create,omitempty"`
+		Delete                   bool                     `json:"delete,omitempty"`
+		CreateAssignments        def.PartitionAssignments `json:"createAssignments,omitempty"`
+		Config                   kafka.ConfigOperations   `json:"config,omitempty"`
+		Partitions               def.PartitionAssignments `json:"partitions,omitempty"`
+		Assignments              def.PartitionAssignments `json:"assignments,omitempty"`
+		LeaderElectionLeaders    []int32                  `json:"leaderElectionLeaders,omitempty"`
+		LeaderElectionPartitions []int32                  `json:"leaderElectionPartitions,omitempty"`
+	}{
+		Create:                   a.create,
+		Delete:                   a.delete,
+		CreateAssignments:        a.createAssignments,
+		Config:                   a.config,
+		Partitions:               a.partitions,
+		Assignments:              a.assignments,
+		LeaderElectionLeaders:    a.leaderElection.leaders,
+		LeaderElectionPartitions: a.leaderElection.partitions,
+	})
+}
+
 type applier struct {
 	// Constructor fields.
 	srv    *kafka.Service
 	defDoc string
 	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
 
 	// Internal fields.
 	localDef             def.TopicDefinition
@@ -75,6 +115,7 @@ type applier struct {
 	remoteConfigs        def.Configs
 	remotePartitionISR   def.PartitionAssignments
 	brokers              meta.Brokers
+	clusterSnapshot      *meta.ClusterSnapshot
 	clusterReplicaCounts map[int32]int
 	ops                  applierOps
 
@@ -85,9 +126,12 @@ type applier struct {
 
 // Execute executes the applier.
 func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
 	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
 		a.res.Err = err.Error()
-		log.Error(err)
+		a.log.Error(err)
 	} else if a.ops.pending() && !a.opts.DryRun {
 		a.res.Applied = true
 	}
@@ -96,6 +140,8 @@ func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
 		PartitionReassignments: a.reassignments,
 	}
 
+	a.events.Completed(ctx, &a.res)
+
 	return &a.res
 }
 
@@ -104,8 +150,9 @@ func (a *applier) apply(ctx context.Context) error {
 	if err := a.createLocal(); err != nil {
 		return err
 	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
 
-	log.Debugf("Validating topic definition")
+	a.log.Debugf("Validating topic definition")
 	if err := a.localDef.Validate(); err != nil {
 		return err
 	}
@@ -114,9 +161,11 @@ func (a *applier) apply(ctx context.Context) error {
 		return err
 	}
 
-	log.Debugf("Validating topic definition using cluster metadata")
-	if err := a.localDef.ValidateWithMetadata(a.brokers); err != nil {
-		return err
+	if !a.localDef.Spec.IsAbsent() {
+		a.log.Debugf("Validating topic definition using cluster metadata")
+		if err := a.localDef.ValidateWithMetadata(a.brokers); err != nil {
+			return err
+		}
 	}
 
 	if err := a.buildOps(ctx); err != nil {
@@ -129,10 +178,19 @@ func (a *applier) apply(ctx context.Context) error {
 		return err
 	}
 
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
 	if a.ops.pending() {
-		if !log.Quiet {
-			a.displayPendingOps()
-		}
+		a.displayPendingOps()
 
 		if err := a.executeOps(ctx); err != nil {
 			return err
@@ -148,15 +206,16 @@ func (a *applier) apply(ctx context.Context) error {
 					if err := a.awaitReassignments(ctx, a.opts.ReassAwaitTimeout); err != nil {
 						return err
 					}
-				} else if !log.Quiet {
+				} else {
 					a.displayPartitionReassignments()
+					a.events.ReassignmentProgress(ctx, a.reassignments)
 				}
 			}
 		}
 
-		log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for topic definition %q", a.localDef.Metadata.Name)
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for topic definition %q", a.localDef.Metadata.Name)
 	} else {
-		log.Infof("No changes to apply for topic definition %q", a.localDef.Metadata.Name)
+		a.log.Infof("No changes to apply for topic definition %q", a.localDef.Metadata.Name)
 	}
 
 	return nil
@@ -165,11 +224,16 @@ func (a *applier) apply(ctx context.Context) error {
 // createLocal creates the local definition.
 func (a *applier) createLocal() error {
 	var err error
-	a.localDef, err = def.LoadTopicDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.PropertyOverrides)
+	a.localDef, err = def.LoadTopicDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.PropertyOverrides, a.opts.LenientAPIVersions)
 	if err != nil {
 		return err
 	}
 
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
 	a.res.LocalDef = &a.localDef
 
 	return nil
@@ -177,32 +241,37 @@ func (a *applier) createLocal() error {
 
 // tryFetchRemote fetches the remote definition and necessary metadata.
 func (a *applier) tryFetchRemote(ctx context.Context) error {
-	log.Infof("Fetching remote topic...")
+	a.log.Infof("Fetching remote topic...")
 	var err error
 	a.remoteDef, a.remoteConfigs, a.remotePartitionISR, a.brokers, err = a.srv.TryRequestTopic(ctx, a.localDef.Metadata)
 	if err != nil {
 		return err
 	}
 
-	if a.localDef.Spec.HasManagedAssignments() && a.localDef.Spec.ManagedAssignments.Selection == def.SelectionTopicClusterUse {
-		// Describe metadata for all topics in the cluster.
-		metadata, err := a.srv.DescribeMetadata(ctx, nil, true)
-		if err != nil {
-			return err
+	if a.localDef.Spec.IsAbsent() {
+		a.ops.delete = (a.remoteDef != nil)
+		if !a.ops.delete {
+			a.log.Debugf("Topic %q does not exist", a.localDef.Metadata.Name)
 		}
-		a.clusterReplicaCounts = make(map[int32]int)
-		for _, t := range metadata.Topics {
-			for _, replicas := range t.PartitionAssignments {
-				for _, brokerID := range replicas {
-					a.clusterReplicaCounts[brokerID]++
-				}
+		return nil
+	}
+
+	if a.localDef.Spec.HasManagedAssignments() && a.localDef.Spec.ManagedAssignments.Selection == def.SelectionTopicClusterUse {
+		a.clusterSnapshot = a.opts.ClusterSnapshot
+		if a.clusterSnapshot == nil {
+			// Describe metadata for all topics in the cluster.
+			a.clusterSnapshot, err = a.srv.DescribeClusterSnapshot(ctx)
+			if err != nil {
+				return err
 			}
 		}
+		// Replica counts are shared with other appliers using the snapshot and updated as operations are built.
+		a.clusterReplicaCounts = a.clusterSnapshot.ReplicaCounts
 	}
 
 	a.ops.create = (a.remoteDef == nil)
 	if a.ops.create {
-		log.Debugf("Topic %q does not exist", a.localDef.Metadata.Name)
+		a.log.Debugf("Topic %q does not exist", a.localDef.Metadata.Name)
 	}
 
 	return nil
@@ -210,21 +279,47 @@ func (a *applier) tryFetchRemote(ctx context.Context) error {
 
 // buildOps builds topic operations.
 func (a *applier) buildOps(ctx context.Context) error {
+	if a.localDef.Spec.IsAbsent() {
+		// The delete operation, if any, is determined when fetching the remote definition.
+		return nil
+	}
+
 	if a.ops.create {
+		a.lockClusterSnapshot()
 		a.buildCreateOp()
+		a.unlockClusterSnapshot()
 	} else {
 		if err := a.buildConfigOps(ctx); err != nil {
 			return err
 		}
-		if err := a.buildPartitionsOp(); err != nil {
+		a.lockClusterSnapshot()
+		err := a.buildPartitionsOp()
+		if err == nil {
+			a.buildAssignmentsOp()
+		}
+		a.unlockClusterSnapshot()
+		if err != nil {
 			return err
 		}
-		a.buildAssignmentsOp()
 		a.buildLeaderElectionOp()
 	}
 	return nil
 }
 
+// lockClusterSnapshot locks the cluster snapshot, if any, while assignments are built.
+func (a *applier) lockClusterSnapshot() {
+	if a.clusterSnapshot != nil {
+		a.clusterSnapshot.Lock()
+	}
+}
+
+// unlockClusterSnapshot unlocks the cluster snapshot, if any.
+func (a *applier) unlockClusterSnapshot() {
+	if a.clusterSnapshot != nil {
+		a.clusterSnapshot.Unlock()
+	}
+}
+
 // updateLocalState updates the state property group of the local definition.
 func (a *applier) updateLocalState() {
 	// The state property group of the local definition is updated to show the underlying state changes.
@@ -260,6 +355,10 @@ func (a *applier) updateLocalState() {
 
 // updateApplyResult updates the apply result with the remote definition and human readable diff.
 func (a *applier) updateApplyResult() error {
+	if a.localDef.Spec.IsAbsent() {
+		return a.updateDeleteApplyResult()
+	}
+
 	var remoteCopy *def.TopicDefinition
 	if !a.ops.create {
 		c := a.remoteDef.Copy()
@@ -294,8 +393,11 @@ func (a *applier) updateApplyResult() error {
 			}
 		}
 
+		remoteCopy.Spec.State = a.localDef.Spec.State
 		remoteCopy.Spec.DeleteUndefinedConfigs = a.localDef.Spec.DeleteUndefinedConfigs
 		remoteCopy.Spec.MaintainLeaders = a.localDef.Spec.MaintainLeaders
+		// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+		remoteCopy.APIVersion = a.localDef.APIVersion
 
 		if a.localDef.State == nil {
 			remoteCopy.State = nil
@@ -324,44 +426,154 @@ func (a *applier) updateApplyResult() error {
 	return nil
 }
 
+// updateDeleteApplyResult updates the apply result for a topic that should not exist.
+func (a *applier) updateDeleteApplyResult() error {
+	var remoteCopy *def.TopicDefinition
+	if a.ops.delete {
+		c := a.remoteDef.Copy()
+		remoteCopy = &c
+
+		// Show the remote topic as it would be defined, omitting state and derived properties.
+		remoteCopy.Spec.Assignments = nil
+		remoteCopy.Spec.ManagedAssignments = nil
+		remoteCopy.State = nil
+
+		remoteCopy.Spec.Configs = def.ConfigsMap{}
+		for _, config := range a.remoteConfigs {
+			if config.Source == def.ConfigSourceDynamicTopicConfig && !config.IsSensitive {
+				remoteCopy.Spec.Configs[config.Name] = config.Value
+			}
+		}
+	}
+
+	// The local definition is shown as null because the topic will no longer exist.
+	diff, err := jsondiff.Diff(remoteCopy, nil)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %v", err)
+	}
+
+	if diffExists := (len(diff) > 0); diffExists != a.ops.pending() {
+		return fmt.Errorf("existence of diff was %v, but expected %v", diffExists, a.ops.pending())
+	}
+
+	a.res.RemoteDef = remoteCopy
+	a.res.Diff = diff
+
+	return nil
+}
+
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	d := &res.Drift{
+		Kind:   def.KindTopic,
+		Name:   a.localDef.Metadata.Name,
+		Status: drift.Status(a.ops.pending()),
+	}
+
+	switch {
+	case a.ops.create:
+		d.Status = res.DriftStatusMissing
+		return d
+	case a.ops.delete:
+		d.Status = res.DriftStatusUnexpected
+		return d
+	case a.remoteDef == nil:
+		return d
+	}
+
+	d.Configs = drift.Configs(a.ops.config, a.remoteConfigs)
+
+	if len(a.ops.partitions) > 0 {
+		d.Partitions = &res.ValueDrift{
+			Name:   "partitions",
+			Local:  a.localDef.Spec.Partitions,
+			Remote: a.remoteDef.Spec.Partitions,
+		}
+	}
+
+	if a.localDef.Spec.ReplicationFactor != a.remoteDef.Spec.ReplicationFactor {
+		d.ReplicationFactor = &res.ValueDrift{
+			Name:   "replicationFactor",
+			Local:  a.localDef.Spec.ReplicationFactor,
+			Remote: a.remoteDef.Spec.ReplicationFactor,
+		}
+	}
+
+	for partition, replicas := range a.ops.assignments {
+		var remote interface{}
+		if partition < len(a.remoteDef.Spec.Assignments) {
+			if cmp.Equal(a.remoteDef.Spec.Assignments[partition], replicas) {
+				continue
+			}
+			remote = a.remoteDef.Spec.Assignments[partition]
+		}
+		d.Assignments = append(d.Assignments, res.ValueDrift{
+			Name:   fmt.Sprint(partition),
+			Local:  replicas,
+			Remote: remote,
+		})
+	}
+
+	for _, partition := range a.ops.leaderElection.partitions {
+		var remote interface{}
+		if a.remoteDef.State != nil && int(partition) < len(a.remoteDef.State.Leaders) {
+			remote = a.remoteDef.State.Leaders[partition]
+		}
+		d.Leaders = append(d.Leaders, res.ValueDrift{
+			Name:   fmt.Sprint(partition),
+			Local:  a.ops.leaderElection.leaders[partition],
+			Remote: remote,
+		})
+	}
+
+	return d
+}
+
 // displayPendingOps displays pending operations.
 func (a *applier) displayPendingOps() {
 	if a.ops.create {
-		log.Infof("Topic %q does not exist and will be created", a.localDef.Metadata.Name)
+		a.log.Infof("Topic %q does not exist and will be created", a.localDef.Metadata.Name)
+	}
+	if a.ops.delete {
+		a.log.Infof("Topic %q exists and will be deleted", a.localDef.Metadata.Name)
 	}
 
-	log.Infof("topic definition %q diff (local -> remote):", a.localDef.Metadata.Name)
-	fmt.Println(a.res.Diff)
+	a.log.Infof("topic definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
 }
 
 // executeOps executes update operations.
 func (a *applier) executeOps(ctx context.Context) error {
+	if a.ops.delete {
+		return a.events.ExecuteOp(ctx, "deleteTopic", a.deleteTopic)
+	}
+
 	if a.ops.create {
-		if err := a.createTopic(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "createTopic", a.createTopic); err != nil {
 			return err
 		}
 	}
 
 	if len(a.ops.config) > 0 {
-		if err := a.updateConfigs(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "alterConfigs", a.updateConfigs); err != nil {
 			return err
 		}
 	}
 
 	if len(a.ops.partitions) > 0 {
-		if err := a.updatePartitions(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "createPartitions", a.updatePartitions); err != nil {
 			return err
 		}
 	}
 
 	if len(a.ops.assignments) > 0 {
-		if err := a.updateAssignments(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "alterPartitionAssignments", a.updateAssignments); err != nil {
 			return err
 		}
 	}
 
 	if len(a.ops.leaderElection.partitions) > 0 {
-		if err := a.electPartitionLeaders(ctx); err != nil {
+		if err := a.events.ExecuteOp(ctx, "electLeaders", a.electPartitionLeaders); err != nil {
 			return err
 		}
 	}
@@ -400,7 +612,7 @@ func (a *applier) buildCreateOp() {
 
 // createTopic executes a request to create a topic.
 func (a *applier) createTopic(ctx context.Context) error {
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Creating topic...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Creating topic...")
 
 	if err := a.srv.CreateTopic(
 		ctx,
@@ -410,14 +622,30 @@ func (a *applier) createTopic(ctx context.Context) error {
 	); err != nil {
 		return err
 	}
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Created topic %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Created topic %q", a.localDef.Metadata.Name)
+
+	return nil
+}
+
+// deleteTopic executes a request to delete a topic.
+func (a *applier) deleteTopic(ctx context.Context) error {
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Deleting topic...")
+
+	// DeleteTopics has no 'ValidateOnly' for dry-run mode so the request is skipped.
+	if !a.opts.DryRun {
+		if err := a.srv.DeleteTopic(ctx, a.localDef.Metadata.Name); err != nil {
+			return err
+		}
+	}
+
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Deleted topic %q", a.localDef.Metadata.Name)
 
 	return nil
 }
 
 // buildConfigOps builds alter configs operations.
 func (a *applier) buildConfigOps(ctx context.Context) error {
-	log.Debugf("Comparing local and remote configs for topic %q", a.localDef.Metadata.Name)
+	a.log.Debugf("Comparing local and remote configs for topic %q", a.localDef.Metadata.Name)
 
 	var err error
 	a.ops.config, err = a.srv.NewConfigOps(
@@ -441,7 +669,7 @@ func (a *applier) updateConfigs(ctx context.Context) error {
 		return errors.New("cannot apply configs because deletion of undefined configs is not enabled")
 	}
 
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering configs...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering configs...")
 	if err := a.srv.AlterTopicConfigs(
 		ctx,
 		a.localDef.Metadata.Name,
@@ -450,7 +678,7 @@ func (a *applier) updateConfigs(ctx context.Context) error {
 	); err != nil {
 		return err
 	}
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered configs for topic %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered configs for topic %q", a.localDef.Metadata.Name)
 
 	return nil
 }
@@ -462,7 +690,7 @@ func (a *applier) buildPartitionsOp() error {
 	}
 
 	if a.localDef.Spec.Partitions > a.remoteDef.Spec.Partitions {
-		log.Debugf(
+		a.log.Debugf(
 			"The number of partitions has changed and will be increased from %d to %d",
 			a.remoteDef.Spec.Partitions,
 			a.localDef.Spec.Partitions,
@@ -484,7 +712,7 @@ func (a *applier) buildPartitionsOp() error {
 
 // updatePartitions executes a request to create partitions.
 func (a *applier) updatePartitions(ctx context.Context) error {
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Creating partitions...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Creating partitions...")
 
 	if err := a.srv.CreatePartitions(
 		ctx,
@@ -495,7 +723,7 @@ func (a *applier) updatePartitions(ctx context.Context) error {
 	); err != nil {
 		return err
 	}
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Created partitions for topic %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Created partitions for topic %q", a.localDef.Metadata.Name)
 
 	return nil
 }
@@ -504,7 +732,7 @@ func (a *applier) updatePartitions(ctx context.Context) error {
 func (a *applier) buildAssignmentsOp() {
 	if a.localDef.Spec.HasAssignments() {
 		if !cmp.Equal(a.remoteDef.Spec.Assignments, a.localDef.Spec.Assignments) {
-			log.Debugf("Partition assignments have changed and will be updated")
+			a.log.Debugf("Partition assignments have changed and will be updated")
 			a.ops.assignments = a.localDef.Spec.Assignments
 		}
 	} else { // Managed assignments.
@@ -521,11 +749,11 @@ func (a *applier) buildAssignmentsOp() {
 				a.clusterReplicaCounts,
 			)
 			if !cmp.Equal(a.remoteDef.Spec.Assignments, newAssignments) {
-				log.Debugf("Partition assignments are out of sync with defined racks and will be updated")
+				a.log.Debugf("Partition assignments are out of sync with defined racks and will be updated")
 				a.ops.assignments = newAssignments
 			}
 		} else if a.localDef.Spec.ReplicationFactor != a.remoteDef.Spec.ReplicationFactor {
-			log.Debugf("Replication factor has changed and will be updated")
+			a.log.Debugf("Replication factor has changed and will be updated")
 			var newAssignments def.PartitionAssignments
 			newAssignments = assignments.Copy(a.remoteDef.Spec.Assignments)
 			if len(a.ops.partitions) > 0 {
@@ -562,7 +790,7 @@ func (a *applier) buildAssignmentsOp() {
 			}
 
 			if !cmp.Equal(prebalancedAssignments, rebalancedAssignments) {
-				log.Debugf("Partition assignments have been rebalanced and will be updated")
+				a.log.Debugf("Partition assignments have been rebalanced and will be updated")
 				a.ops.assignments = rebalancedAssignments
 			}
 		}
@@ -572,7 +800,7 @@ func (a *applier) buildAssignmentsOp() {
 // fetchPartitionReassignments executes a request to list partition reassignments.
 func (a *applier) fetchPartitionReassignments(ctx context.Context, suppressLog bool) error {
 	if !(suppressLog) {
-		log.Debugf("Fetching in-progress partition reassignments for topic %q", a.localDef.Metadata.Name)
+		a.log.Debugf("Fetching in-progress partition reassignments for topic %q", a.localDef.Metadata.Name)
 	}
 
 	partitions := make([]int32, a.localDef.Spec.Partitions)
@@ -595,9 +823,8 @@ func (a *applier) fetchPartitionReassignments(ctx context.Context, suppressLog b
 
 // displayPartitionReassignments displays in-progress partition reassignments.
 func (a *applier) displayPartitionReassignments() {
-	log.Infof("In-progress partition reassignments for topic %q:", a.localDef.Metadata.Name)
+	a.log.Infof("In-progress partition reassignments for topic %q:", a.localDef.Metadata.Name)
 	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
 	t.AppendHeader(table.Row{"Partition", "Replicas", "Adding Replicas", "Removing Replicas"})
 	for _, r := range a.reassignments {
 		t.AppendRow([]interface{}{
@@ -608,12 +835,12 @@ func (a *applier) displayPartitionReassignments() {
 		})
 	}
 	t.SetStyle(table.StyleLight)
-	t.Render()
+	a.log.Println(t.Render())
 }
 
 // updateAssignments executes a request to alter assignments.
 func (a *applier) updateAssignments(ctx context.Context) error {
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering partition assignments...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering partition assignments...")
 
 	if a.opts.DryRun {
 		// AlterPartitionAssignments has no 'ValidateOnly' for dry-run mode so we check
@@ -633,33 +860,35 @@ func (a *applier) updateAssignments(ctx context.Context) error {
 		return err
 	}
 
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered partition assignments for topic %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered partition assignments for topic %q", a.localDef.Metadata.Name)
 
 	return nil
 }
 
 // awaitReassignments awaits the completion of in-progress partition reassignments.
 func (a *applier) awaitReassignments(ctx context.Context, timeoutSec int) error {
-	log.Infof("Awaiting completion of partition reassignments (timeout: %d seconds)...", timeoutSec)
+	a.log.Infof("Awaiting completion of partition reassignments (timeout: %d seconds)...", timeoutSec)
 	timeout := time.After(time.Duration(timeoutSec) * time.Second)
 
 	remaining := 0
 	for {
 		select {
 		case <-timeout:
-			log.Infof("Awaiting completion of partition reassignments timed out after %d seconds", timeoutSec)
+			a.log.Infof("Awaiting completion of partition reassignments timed out after %d seconds", timeoutSec)
 			return nil
 		default:
 			if err := a.fetchPartitionReassignments(ctx, true); err != nil {
 				return err
 			}
 			if len(a.reassignments) > 0 {
-				if !log.Quiet && len(a.reassignments) != remaining {
+				if len(a.reassignments) != remaining {
 					a.displayPartitionReassignments()
+					a.events.ReassignmentProgress(ctx, a.reassignments)
 				}
 				remaining = len(a.reassignments)
 			} else {
-				log.Infof("Partition reassignments completed")
+				a.log.Infof("Partition reassignments completed")
+				a.events.ReassignmentProgress(ctx, a.reassignments)
 				return nil
 			}
 
@@ -695,7 +924,7 @@ func (a *applier) buildLeaderElectionOp() {
 				if i32.Contains(preferredLeader, a.remotePartitionISR[partition]) {
 					a.ops.leaderElection.partitions = append(a.ops.leaderElection.partitions, int32(partition))
 				} else {
-					log.Warnf(
+					a.log.Warnf(
 						"Cannot elect preferred leader %q of partition %q because it is not an in-sync replica.",
 						fmt.Sprint(preferredLeader),
 						partition,
@@ -708,7 +937,7 @@ func (a *applier) buildLeaderElectionOp() {
 
 // electPartitionLeaders executes a request to elect partition leaders.
 func (a *applier) electPartitionLeaders(ctx context.Context) error {
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Electing partition leaders...")
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Electing partition leaders...")
 
 	if !a.opts.DryRun {
 		if err := a.srv.ElectLeaders(
@@ -720,7 +949,7 @@ func (a *applier) electPartitionLeaders(ctx context.Context) error {
 		}
 	}
 
-	log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Elected partition leaders for topic %q", a.localDef.Metadata.Name)
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Elected partition leaders for topic %q", a.localDef.Metadata.Name)
 
 	return nil
 }
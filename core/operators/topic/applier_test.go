@@ -857,5 +857,49 @@ func Test_applier_Execute(t *testing.T) {
 			wantErr:     "",
 			wantApplied: false,
 		},
+		{
+			// Delete topic
+			name: "6: Dry-run topic corge version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: corgeDocs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    corgeDiffs[3],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Delete topic
+			name: "7: Apply topic corge version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: corgeDocs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    corgeDiffs[3],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "8: Dry-run topic corge version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: corgeDocs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    corgeDiffs[4],
+			wantErr:     "",
+			wantApplied: false,
+		},
 	})
 }
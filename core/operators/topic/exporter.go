@@ -6,9 +6,9 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/peter-evans/kdef/cli/log"
 	"github.com/peter-evans/kdef/core/client"
 	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/opt"
 	"github.com/peter-evans/kdef/core/model/res"
@@ -20,6 +20,8 @@ type ExporterOptions struct {
 	Exclude         string
 	IncludeInternal bool
 	Assignments     opt.Assignments
+	InferProfiles   bool
+	Logger          *log.Logger
 }
 
 // NewExporter creates a new exporter.
@@ -30,17 +32,19 @@ func NewExporter(
 	return &exporter{
 		srv:  kafka.NewService(cl),
 		opts: opts,
+		log:  opts.Logger,
 	}
 }
 
 type exporter struct {
 	srv  *kafka.Service
 	opts ExporterOptions
+	log  *log.Logger
 }
 
 // Execute executes the export operation.
 func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
-	log.Infof("Fetching remote topics...")
+	e.log.Infof("Fetching remote topics...")
 	topicDefs, err := e.getTopicDefinitions(ctx)
 	if err != nil {
 		return nil, err
@@ -50,12 +54,25 @@ func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
 		return nil, nil
 	}
 
-	results := make(res.ExportResults, len(topicDefs))
-	for i, topicDef := range topicDefs {
-		results[i] = res.ExportResult{
+	var profileDefs []def.TopicProfileDefinition
+	if e.opts.InferProfiles {
+		profileDefs, topicDefs = def.InferTopicProfiles(topicDefs)
+		e.log.Infof("Inferred %d topic profile(s)", len(profileDefs))
+	}
+
+	results := make(res.ExportResults, 0, len(topicDefs)+len(profileDefs))
+	for _, topicDef := range topicDefs {
+		results = append(results, res.ExportResult{
 			ID:  topicDef.Metadata.Name,
 			Def: topicDef,
-		}
+		})
+	}
+	for _, profileDef := range profileDefs {
+		results = append(results, res.ExportResult{
+			ID:   profileDef.Metadata.Name,
+			Type: def.KindTopicProfile,
+			Def:  profileDef,
+		})
 	}
 
 	results.Sort()
@@ -0,0 +1,359 @@
+// Package user implements operators for user definition operations.
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/helpers/drift"
+	"github.com/peter-evans/kdef/core/helpers/jsondiff"
+	"github.com/peter-evans/kdef/core/helpers/plan"
+	"github.com/peter-evans/kdef/core/helpers/scram"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// ApplierOptions represents options to configure an applier.
+type ApplierOptions struct {
+	DefinitionFormat   opt.DefinitionFormat
+	PropertyOverrides  []string
+	LenientAPIVersions []string
+	DryRun             bool
+	Plan               *res.PlannedApply
+	ReadOnly           bool
+	Logger             *log.Logger
+	Events             event.Handler
+	Source             *def.Source
+	Labels             def.ResourceMetadataLabels
+}
+
+// NewApplier creates a new applier.
+func NewApplier(
+	cl *client.Client,
+	defDoc string,
+	opts ApplierOptions,
+) *applier { //revive:disable-line:unexported-return
+	return &applier{
+		srv:    kafka.NewService(cl),
+		defDoc: defDoc,
+		opts:   opts,
+		log:    opts.Logger,
+		events: event.NewEmitter(opts.Events, def.KindUser, opts.DryRun),
+	}
+}
+
+type applierOps struct {
+	upsert def.SCRAMCredentials
+	delete []string
+	// Mechanisms of credentials upserted only because they rotate.
+	rotate []string
+}
+
+func (a applierOps) pending() bool {
+	return len(a.upsert) > 0 ||
+		len(a.delete) > 0
+}
+
+// MarshalJSON marshals the operations for inclusion in a plan.
+func (a applierOps) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Upsert def.SCRAMCredentials `json:"upsert,omitempty"`
+		Delete []string             `json:"delete,omitempty"`
+	}{
+		Upsert: a.upsert,
+		Delete: a.delete,
+	})
+}
+
+type applier struct {
+	// Constructor fields.
+	srv    *kafka.Service
+	defDoc string
+	opts   ApplierOptions
+	log    *log.Logger
+	events *event.Emitter
+
+	// Internal fields.
+	localDef  def.UserDefinition
+	remoteDef def.UserDefinition
+	ops       applierOps
+
+	// Result fields.
+	res res.ApplyResult
+}
+
+// Execute executes the applier.
+func (a *applier) Execute(ctx context.Context) *res.ApplyResult {
+	a.res.Source = a.opts.Source
+	a.res.Labels = a.opts.Labels
+	if err := a.apply(ctx); err != nil {
+		err = a.opts.Source.Errorf("%v", err)
+		a.res.Err = err.Error()
+		a.log.Error(err)
+	} else if a.ops.pending() && !a.opts.DryRun {
+		a.res.Applied = true
+	}
+
+	a.events.Completed(ctx, &a.res)
+
+	return &a.res
+}
+
+// apply performs the apply operation sequence.
+func (a *applier) apply(ctx context.Context) error {
+	if err := a.createLocal(); err != nil {
+		return err
+	}
+	a.events.DefinitionLoaded(ctx, a.localDef.Metadata.Name)
+
+	a.log.Debugf("Validating user definition")
+	if err := a.localDef.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.fetchRemote(ctx); err != nil {
+		return err
+	}
+
+	a.buildOps()
+
+	if err := a.updateApplyResult(); err != nil {
+		return err
+	}
+
+	if err := plan.Record(&a.res, a.remoteDef, a.ops, a.opts.Plan); err != nil {
+		return err
+	}
+
+	a.res.Drift = a.buildDrift()
+	if a.opts.ReadOnly {
+		return nil
+	}
+
+	a.events.OpsPlanned(ctx, &a.res)
+
+	if a.ops.pending() {
+		a.displayPendingOps()
+
+		if err := a.events.ExecuteOp(ctx, "alterSCRAMCredentials", a.executeOps); err != nil {
+			return err
+		}
+
+		a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Completed apply for user definition %q", a.localDef.Metadata.Name)
+	} else {
+		a.log.Infof("No changes to apply for user definition %q", a.localDef.Metadata.Name)
+	}
+
+	return nil
+}
+
+// createLocal creates the local definition.
+func (a *applier) createLocal() error {
+	var err error
+	a.localDef, err = def.LoadUserDefinition(a.defDoc, a.opts.DefinitionFormat, a.opts.LenientAPIVersions)
+	if err != nil {
+		return err
+	}
+
+	a.res.IgnoredFields = a.localDef.IgnoredFields()
+	for _, field := range a.res.IgnoredFields {
+		a.log.Warnf("Ignored unknown field %q of definition with lenient apiVersion %q", field, a.localDef.APIVersion)
+	}
+
+	a.res.LocalDef = &a.localDef
+
+	return nil
+}
+
+// fetchRemote fetches the remote definition.
+func (a *applier) fetchRemote(ctx context.Context) error {
+	a.log.Infof("Fetching remote SCRAM credentials...")
+	remoteCredentials, err := a.srv.DescribeUserSCRAMCredentials(ctx, a.localDef.Metadata.Name)
+	if err != nil {
+		return err
+	}
+
+	a.remoteDef = def.NewUserDefinition(a.localDef.Metadata, remoteCredentials)
+
+	return nil
+}
+
+// buildOps builds SCRAM credential operations.
+func (a *applier) buildOps() {
+	a.log.Debugf("Comparing local and remote SCRAM credentials for user %q", a.localDef.Metadata.Name)
+
+	for _, local := range a.localDef.Spec.SCRAMCredentials {
+		remote, ok := a.remoteDef.Spec.SCRAMCredentials.Get(local.Mechanism)
+		switch {
+		case !ok:
+			a.log.Debugf("SCRAM credential %q is missing from remote and will be created", local.Mechanism)
+			a.ops.upsert = append(a.ops.upsert, local)
+		case remote.Iterations != local.Iterations:
+			a.log.Debugf(
+				"Iterations of SCRAM credential %q have changed from %d to %d and will be updated",
+				local.Mechanism,
+				remote.Iterations,
+				local.Iterations,
+			)
+			a.ops.upsert = append(a.ops.upsert, local)
+		case local.Rotate && !a.opts.ReadOnly:
+			a.log.Debugf("SCRAM credential %q is set to rotate and will be updated", local.Mechanism)
+			a.ops.upsert = append(a.ops.upsert, local)
+			a.ops.rotate = append(a.ops.rotate, local.Mechanism)
+		}
+	}
+
+	if a.localDef.Spec.DeleteUndefinedCredentials {
+		for _, remote := range a.remoteDef.Spec.SCRAMCredentials {
+			if _, ok := a.localDef.Spec.SCRAMCredentials.Get(remote.Mechanism); !ok {
+				a.log.Debugf("SCRAM credential %q is missing from local definition and will be deleted", remote.Mechanism)
+				a.ops.delete = append(a.ops.delete, remote.Mechanism)
+			}
+		}
+	}
+}
+
+// updateApplyResult updates the apply result with the remote definition and human readable diff.
+func (a *applier) updateApplyResult() error {
+	remoteCopy := a.remoteDef.Copy()
+
+	// Modify the remote definition to remove optional properties not specified in local.
+	// Further, set properties that are local only and have no remote state.
+	var credentials def.SCRAMCredentials
+	for _, remote := range remoteCopy.Spec.SCRAMCredentials {
+		local, existsInLocal := a.localDef.Spec.SCRAMCredentials.Get(remote.Mechanism)
+		if existsInLocal {
+			// Password references are local only and have no remote state.
+			remote.Password = local.Password
+			// Rotation is local only, and is shown in the diff if the credential will be rotated.
+			remote.Rotate = local.Rotate && !str.Contains(remote.Mechanism, a.ops.rotate)
+		} else if !a.localDef.Spec.DeleteUndefinedCredentials {
+			continue
+		}
+		credentials = append(credentials, remote)
+	}
+	remoteCopy.Spec.SCRAMCredentials = credentials
+
+	remoteCopy.Spec.DeleteUndefinedCredentials = a.localDef.Spec.DeleteUndefinedCredentials
+
+	// Definitions with a lenient apiVersion keep their apiVersion, which has no remote state.
+	remoteCopy.APIVersion = a.localDef.APIVersion
+
+	diff, err := jsondiff.Diff(&remoteCopy, &a.localDef)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %v", err)
+	}
+
+	if diffExists := (len(diff) > 0); diffExists != a.ops.pending() {
+		return fmt.Errorf("existence of diff was %v, but expected %v", diffExists, a.ops.pending())
+	}
+
+	a.res.RemoteDef = remoteCopy
+	a.res.Diff = diff
+
+	return nil
+}
+
+// buildDrift builds the drift of the remote resource from the local definition.
+func (a *applier) buildDrift() *res.Drift {
+	var values []res.ValueDrift
+	for _, local := range a.ops.upsert {
+		if str.Contains(local.Mechanism, a.ops.rotate) {
+			// Rotation is not drift.
+			continue
+		}
+		var remote interface{}
+		if credential, ok := a.remoteDef.Spec.SCRAMCredentials.Get(local.Mechanism); ok {
+			remote = credential.Iterations
+		}
+		values = append(values, res.ValueDrift{
+			Name:   local.Mechanism + " iterations",
+			Local:  local.Iterations,
+			Remote: remote,
+		})
+	}
+	for _, mechanism := range a.ops.delete {
+		credential, _ := a.remoteDef.Spec.SCRAMCredentials.Get(mechanism)
+		values = append(values, res.ValueDrift{
+			Name:   mechanism + " iterations",
+			Local:  nil,
+			Remote: credential.Iterations,
+		})
+	}
+
+	return &res.Drift{
+		Kind:   def.KindUser,
+		Name:   a.localDef.Metadata.Name,
+		Status: drift.Status(a.ops.pending()),
+		Values: values,
+	}
+}
+
+// displayPendingOps displays pending operations.
+func (a *applier) displayPendingOps() {
+	a.log.Infof("user definition %q diff (local -> remote):", a.localDef.Metadata.Name)
+	a.log.Println(a.res.Diff)
+}
+
+// executeOps executes update operations.
+func (a *applier) executeOps(ctx context.Context) error {
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altering SCRAM credentials...")
+
+	// Passwords are resolved in dry-run mode to validate their references.
+	upsertions := make([]kafka.SCRAMUpsertion, len(a.ops.upsert))
+	for i, credential := range a.ops.upsert {
+		upsertion, err := newUpsertion(credential)
+		if err != nil {
+			return fmt.Errorf("scram credential %q: %v", credential.Mechanism, err)
+		}
+		upsertions[i] = upsertion
+	}
+
+	// AlterUserScramCredentials has no 'ValidateOnly' for dry-run mode so the request is skipped.
+	if !a.opts.DryRun {
+		if err := a.srv.AlterUserSCRAMCredentials(
+			ctx,
+			a.localDef.Metadata.Name,
+			upsertions,
+			a.ops.delete,
+		); err != nil {
+			return err
+		}
+	}
+
+	a.log.InfoMaybeWithKeyf("dry-run", a.opts.DryRun, "Altered SCRAM credentials for user %q", a.localDef.Metadata.Name)
+
+	return nil
+}
+
+// newUpsertion creates a SCRAM credential upsertion with a newly salted password.
+func newUpsertion(credential def.SCRAMCredentialDefinition) (kafka.SCRAMUpsertion, error) {
+	password, err := scram.ResolvePassword(credential.Password)
+	if err != nil {
+		return kafka.SCRAMUpsertion{}, err
+	}
+
+	salt, err := scram.NewSalt()
+	if err != nil {
+		return kafka.SCRAMUpsertion{}, err
+	}
+
+	saltedPassword, err := scram.SaltedPassword(credential.Mechanism, password, salt, credential.Iterations)
+	if err != nil {
+		return kafka.SCRAMUpsertion{}, err
+	}
+
+	return kafka.SCRAMUpsertion{
+		Mechanism:      credential.Mechanism,
+		Iterations:     credential.Iterations,
+		Salt:           salt,
+		SaltedPassword: saltedPassword,
+	}, nil
+}
@@ -0,0 +1,298 @@
+//go:build integration
+
+// Package user implements operators for user definition operations.
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/peter-evans/kdef/cli/log"
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/compose"
+	"github.com/peter-evans/kdef/core/test/harness"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+// VERBOSE_TESTS=1 go test --tags=integration -run ^Test_applier_Execute$ ./core/operators/user -v
+func Test_applier_Execute(t *testing.T) {
+	_, log.Verbose = os.LookupEnv("VERBOSE_TESTS")
+	t.Setenv("KDEF_TEST_ALICE_PASSWORD", "alice-secret")
+
+	type fields struct {
+		cl      *client.Client
+		yamlDoc string
+		opts    ApplierOptions
+	}
+	type testCase struct {
+		name        string
+		fields      fields
+		wantDiff    string
+		wantErr     string
+		wantApplied bool
+	}
+
+	ctx := context.Background()
+
+	runTests := func(t *testing.T, tests []testCase) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				a := NewApplier(tt.fields.cl, tt.fields.yamlDoc, tt.fields.opts)
+				got := a.Execute(ctx)
+
+				if log.Verbose {
+					// Output apply result JSON
+					jsonOut, err := json.MarshalIndent(got, "", "  ")
+					if err != nil {
+						t.Errorf("failed to convert apply result to json: %v", err)
+						t.FailNow()
+					}
+					fmt.Println("[test] ApplyResult JSON:")
+					fmt.Println(string(jsonOut))
+				}
+
+				if got.Diff != tt.wantDiff {
+					t.Errorf("applier.Execute().Diff = %v, want %v", got.Diff, tt.wantDiff)
+				}
+				if !tutil.ErrorContains(got.GetErr(), tt.wantErr) {
+					t.Errorf("applier.Execute() error = %v, wantErr %v", got.GetErr(), tt.wantErr)
+				}
+				if got.Applied != tt.wantApplied {
+					t.Errorf("applier.Execute().Applied = %v, want %v", got.Applied, tt.wantApplied)
+				}
+
+				// Sleep to give Kafka time to update internally
+				time.Sleep(2 * time.Second)
+			})
+		}
+	}
+
+	getDiffsFixture := func(t *testing.T, path string) []string {
+		var diffs []string
+		if err := json.Unmarshal(tutil.Fixture(t, path), &diffs); err != nil {
+			t.Errorf("failed to unmarshal JSON test fixture: %v", err)
+			t.FailNow()
+		}
+		return diffs
+	}
+
+	// Create client
+	cl := tutil.CreateClient(t,
+		[]string{fmt.Sprintf("seedBrokers=localhost:%d", harness.UserApplier.BrokerPort)},
+	)
+
+	// Create the test cluster
+	srv := kafka.NewService(cl)
+	maxTries := 3
+	try := 1
+	for {
+		start := time.Now()
+		c := compose.Up(
+			t,
+			harness.UserApplier.ComposeFilePaths,
+			harness.UserApplier.Env(),
+		)
+		if srv.IsKafkaReady(ctx, harness.UserApplier.Brokers, 90) {
+			duration := time.Since(start)
+			log.Infof("kafka cluster ready in %v", duration)
+			break
+		} else {
+			log.Warnf("kafka failed to be ready within timeout")
+			compose.Down(t, c)
+			try++
+		}
+		if try > maxTries {
+			t.Errorf("kafka failed to be ready within timeout after %d tries", maxTries)
+			t.FailNow()
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	// Tests changes to SCRAM credentials
+	user1Docs := tutil.FileToYAMLDocs(t, "../../test/fixtures/user/core.operators.user.applier.1.yml")
+	user1Diffs := getDiffsFixture(t, "../../test/fixtures/user/core.operators.user.applier.1.json")
+	runTests(t, []testCase{
+		// NOTE: Execution of tests is ordered
+		{
+			// Create credentials
+			name: "1: Dry-run user alice version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    user1Diffs[0],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Create credentials
+			name: "2: Apply user alice version 0",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[0],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    user1Diffs[0],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// Add credentials
+			name: "3: Dry-run user alice version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    user1Diffs[1],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Add credentials
+			name: "4: Apply user alice version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    user1Diffs[1],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "5: Dry-run user alice version 1",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[1],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    user1Diffs[2],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Delete undefined credentials
+			name: "6: Dry-run user alice version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    user1Diffs[3],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Delete undefined credentials
+			name: "7: Apply user alice version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    user1Diffs[3],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// No changes
+			name: "8: Dry-run user alice version 2",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[2],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    user1Diffs[2],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Rotate credentials
+			name: "9: Dry-run user alice version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					DryRun:           true,
+				},
+			},
+			wantDiff:    user1Diffs[4],
+			wantErr:     "",
+			wantApplied: false,
+		},
+		{
+			// Rotate credentials
+			name: "10: Apply user alice version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    user1Diffs[4],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// Rotate credentials on every apply
+			name: "11: Apply user alice version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+				},
+			},
+			wantDiff:    user1Diffs[4],
+			wantErr:     "",
+			wantApplied: true,
+		},
+		{
+			// Credentials are not rotated in read-only mode
+			name: "12: Read-only user alice version 3",
+			fields: fields{
+				cl:      cl,
+				yamlDoc: user1Docs[3],
+				opts: ApplierOptions{
+					DefinitionFormat: opt.YAMLFormat,
+					ReadOnly:         true,
+				},
+			},
+			wantDiff:    user1Diffs[2],
+			wantErr:     "",
+			wantApplied: false,
+		},
+	})
+}
@@ -0,0 +1,83 @@
+// Package user implements operators for user definition operations.
+package user
+
+import (
+	"context"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ExporterOptions represents options to configure an exporter.
+type ExporterOptions struct {
+	Logger *log.Logger
+}
+
+// NewExporter creates a new exporter.
+func NewExporter(
+	cl *client.Client,
+	opts ExporterOptions,
+) *exporter { //revive:disable-line:unexported-return
+	return &exporter{
+		srv: kafka.NewService(cl),
+		log: opts.Logger,
+	}
+}
+
+type exporter struct {
+	// constructor params
+	srv *kafka.Service
+	log *log.Logger
+}
+
+// Execute executes the export operation.
+func (e *exporter) Execute(ctx context.Context) (res.ExportResults, error) {
+	e.log.Infof("Fetching remote SCRAM credentials...")
+	userDefs, err := e.getUserDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userDefs) == 0 {
+		return nil, nil
+	}
+
+	results := make(res.ExportResults, len(userDefs))
+	for i, userDef := range userDefs {
+		results[i] = res.ExportResult{
+			ID:  userDef.Metadata.Name,
+			Def: userDef,
+		}
+	}
+
+	results.Sort()
+
+	return results, nil
+}
+
+func (e *exporter) getUserDefinitions(ctx context.Context) ([]def.UserDefinition, error) {
+	userCredentials, err := e.srv.DescribeAllUserSCRAMCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userDefs := []def.UserDefinition{}
+	for _, uc := range userCredentials {
+		// Passwords cannot be read from the cluster and are not exported.
+		userDef := def.NewUserDefinition(
+			def.ResourceMetadataDefinition{
+				Name: uc.User,
+			},
+			uc.Credentials,
+		)
+		// Default to delete undefined credentials.
+		userDef.Spec.DeleteUndefinedCredentials = true
+
+		userDefs = append(userDefs, userDef)
+	}
+
+	return userDefs, nil
+}
@@ -0,0 +1,260 @@
+// Package policy implements the validation of definitions against the rules of a policy.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operand represents the operand of a condition, either a value or a reference to the value of a path.
+type Operand struct {
+	Value interface{}
+	Path  string
+
+	path path
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// An object of the form {"path": "..."} references the value of a path.
+func (o *Operand) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '{' {
+		var ref struct {
+			Path string `json:"path"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&ref); err != nil || len(ref.Path) == 0 {
+			return fmt.Errorf("operand objects must only contain a path")
+		}
+		o.Path = ref.Path
+		return nil
+	}
+	return json.Unmarshal(b, &o.Value)
+}
+
+// resolve returns the value of the operand in a scope.
+// A referenced path must have exactly one value.
+func (o Operand) resolve(s scope) (interface{}, error) {
+	if o.path == nil {
+		return o.Value, nil
+	}
+	values := s.resolve(o.path)
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf("%s must be set", o.path)
+	case 1:
+		return values[0], nil
+	default:
+		return nil, fmt.Errorf("%s must have exactly one value", o.path)
+	}
+}
+
+// describe returns a description of the operand and its resolved value.
+func (o Operand) describe(value interface{}) string {
+	if o.path == nil {
+		return formatValue(value)
+	}
+	return fmt.Sprintf("%s (%s)", o.path, formatValue(value))
+}
+
+// Condition represents a condition on the values of a path.
+// Exactly one operator must be specified.
+type Condition struct {
+	Path string `json:"path"`
+
+	Exists             *bool         `json:"exists,omitempty"`
+	Equals             *Operand      `json:"equals,omitempty"`
+	NotEquals          *Operand      `json:"notEquals,omitempty"`
+	In                 []interface{} `json:"in,omitempty"`
+	NotIn              []interface{} `json:"notIn,omitempty"`
+	Matches            string        `json:"matches,omitempty"`
+	LessThan           *Operand      `json:"lt,omitempty"`
+	LessThanOrEqual    *Operand      `json:"lte,omitempty"`
+	GreaterThan        *Operand      `json:"gt,omitempty"`
+	GreaterThanOrEqual *Operand      `json:"gte,omitempty"`
+
+	path    path
+	matches *regexp.Regexp
+	// The comparison operator and its operand, if any.
+	comparison string
+	operand    *Operand
+}
+
+// Comparison operators.
+var comparisons = map[string]struct {
+	desc    string
+	compare func(a float64, b float64) bool
+}{
+	"lt":  {"less than", func(a, b float64) bool { return a < b }},
+	"lte": {"less than or equal to", func(a, b float64) bool { return a <= b }},
+	"gt":  {"greater than", func(a, b float64) bool { return a > b }},
+	"gte": {"greater than or equal to", func(a, b float64) bool { return a >= b }},
+}
+
+// compile validates the condition and compiles its paths and regular expression.
+func (c *Condition) compile() error {
+	var err error
+	if c.path, err = parsePath(c.Path); err != nil {
+		return err
+	}
+
+	operators := 0
+	count := func(specified bool) {
+		if specified {
+			operators++
+		}
+	}
+	count(c.Exists != nil)
+	count(c.In != nil)
+	count(c.NotIn != nil)
+	count(len(c.Matches) > 0)
+	operands := map[string]*Operand{
+		"equals":    c.Equals,
+		"notEquals": c.NotEquals,
+		"lt":        c.LessThan,
+		"lte":       c.LessThanOrEqual,
+		"gt":        c.GreaterThan,
+		"gte":       c.GreaterThanOrEqual,
+	}
+	for op, operand := range operands {
+		if operand == nil {
+			continue
+		}
+		operators++
+		if len(operand.Path) > 0 {
+			if operand.path, err = parsePath(operand.Path); err != nil {
+				return fmt.Errorf("%s: %v", op, err)
+			}
+		}
+		c.comparison = op
+		c.operand = operand
+	}
+	if operators != 1 {
+		return fmt.Errorf("condition must specify exactly one operator")
+	}
+
+	if len(c.Matches) > 0 {
+		if c.matches, err = regexp.Compile(c.Matches); err != nil {
+			return fmt.Errorf("matches must be a valid regular expression: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// evaluate evaluates the condition in a scope.
+// The condition holds if it holds for all values of its path, and does not hold if the path has no values,
+// unless it is an exists condition. A message describing the failure is returned if the condition does not hold.
+func (c Condition) evaluate(s scope) (string, bool) {
+	values := s.resolve(c.path)
+
+	if c.Exists != nil {
+		if *c.Exists && len(values) == 0 {
+			return fmt.Sprintf("%s must be set", c.path), false
+		}
+		if !*c.Exists && len(values) > 0 {
+			return fmt.Sprintf("%s must not be set", c.path), false
+		}
+		return "", true
+	}
+
+	if len(values) == 0 {
+		return fmt.Sprintf("%s must be set", c.path), false
+	}
+
+	var operand interface{}
+	if c.operand != nil {
+		var err error
+		if operand, err = c.operand.resolve(s); err != nil {
+			return err.Error(), false
+		}
+	}
+
+	for _, v := range values {
+		if desc, ok := c.evaluateValue(v, operand); !ok {
+			return fmt.Sprintf("%s must %s but is %s", c.path, desc, formatValue(v)), false
+		}
+	}
+	return "", true
+}
+
+// evaluateValue evaluates the condition for a value and the resolved operand, returning a description of the condition.
+func (c Condition) evaluateValue(v interface{}, operand interface{}) (string, bool) {
+	switch {
+	case c.In != nil:
+		return fmt.Sprintf("be one of %s", formatValue(c.In)), contains(c.In, v)
+	case c.NotIn != nil:
+		return fmt.Sprintf("not be one of %s", formatValue(c.NotIn)), !contains(c.NotIn, v)
+	case c.matches != nil:
+		desc := fmt.Sprintf("match %q", c.Matches)
+		str, ok := v.(string)
+		return desc, ok && c.matches.MatchString(str)
+	}
+
+	desc := c.operand.describe(operand)
+
+	switch c.comparison {
+	case "equals":
+		return "equal " + desc, equal(v, operand)
+	case "notEquals":
+		return "not equal " + desc, !equal(v, operand)
+	}
+
+	comparison := comparisons[c.comparison]
+	desc = fmt.Sprintf("be %s %s", comparison.desc, desc)
+	a, aOK := number(v)
+	b, bOK := number(operand)
+	return desc, aOK && bOK && comparison.compare(a, b)
+}
+
+// contains determines if a value is equal to any value of a list.
+func contains(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if equal(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal determines if two values are equal.
+// Numbers are compared numerically, including numeric strings such as config values.
+func equal(a interface{}, b interface{}) bool {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x == y
+		}
+	}
+	return formatValue(a) == formatValue(b)
+}
+
+// number returns the numeric value of a number or numeric string.
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatValue formats a value decoded from JSON for messages.
+func formatValue(v interface{}) string {
+	switch t := v.(type) {
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case string:
+		return strconv.Quote(t)
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(j)
+}
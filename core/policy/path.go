@@ -0,0 +1,155 @@
+// Package policy implements the validation of definitions against the rules of a policy.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Path roots that do not refer to the definition document.
+const (
+	rootEach = "each"
+	rootEnv  = "env"
+)
+
+// segment represents a segment of a path.
+type segment struct {
+	key      string
+	wildcard bool
+}
+
+// path represents a parsed path to values of a definition document.
+type path []segment
+
+// parsePath parses a path of dot separated keys.
+// Keys containing dots can be quoted in brackets, e.g. spec.configs["retention.ms"],
+// and [*] selects all elements of a list or values of a map.
+func parsePath(p string) (path, error) {
+	var segs path
+	s := p
+	expectKey := true
+	for len(s) > 0 {
+		switch {
+		case s[0] == '[':
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", p)
+			}
+			inner := s[1:end]
+			switch {
+			case inner == "*":
+				segs = append(segs, segment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0]:
+				segs = append(segs, segment{key: inner[1 : len(inner)-1]})
+			default:
+				return nil, fmt.Errorf("invalid path %q: brackets must contain a quoted key or *", p)
+			}
+			s = s[end+1:]
+			expectKey = false
+		case s[0] == '.':
+			if expectKey {
+				return nil, fmt.Errorf("invalid path %q: empty key", p)
+			}
+			s = s[1:]
+			expectKey = true
+			if len(s) == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", p)
+			}
+		default:
+			if !expectKey {
+				return nil, fmt.Errorf("invalid path %q: missing dot before key", p)
+			}
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			segs = append(segs, segment{key: s[:end]})
+			s = s[end:]
+			expectKey = false
+		}
+	}
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("invalid path %q: empty path", p)
+	}
+	return segs, nil
+}
+
+// String returns the path as a string.
+func (p path) String() string {
+	var b strings.Builder
+	for i, seg := range p {
+		switch {
+		case seg.wildcard:
+			b.WriteString("[*]")
+		case strings.ContainsAny(seg.key, ".[]"):
+			fmt.Fprintf(&b, "[%q]", seg.key)
+		default:
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(seg.key)
+		}
+	}
+	return b.String()
+}
+
+// scope represents the values that paths are resolved against.
+type scope struct {
+	doc  interface{}
+	each interface{}
+}
+
+// resolve returns the values of a path in the scope.
+func (s scope) resolve(p path) []interface{} {
+	if !p[0].wildcard {
+		switch p[0].key {
+		case rootEnv:
+			if len(p) != 2 || p[1].wildcard {
+				return nil
+			}
+			if v, ok := os.LookupEnv(p[1].key); ok {
+				return []interface{}{v}
+			}
+			return nil
+		case rootEach:
+			if s.each == nil {
+				return nil
+			}
+			return resolve(s.each, p[1:])
+		}
+	}
+	return resolve(s.doc, p)
+}
+
+// resolve returns the values of a path in a value decoded from JSON.
+func resolve(v interface{}, p path) []interface{} {
+	values := []interface{}{v}
+	for _, seg := range p {
+		var next []interface{}
+		for _, value := range values {
+			switch t := value.(type) {
+			case map[string]interface{}:
+				if seg.wildcard {
+					keys := make([]string, 0, len(t))
+					for k := range t {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, t[k])
+					}
+				} else if e, ok := t[seg.key]; ok && e != nil {
+					next = append(next, e)
+				}
+			case []interface{}:
+				if seg.wildcard {
+					next = append(next, t...)
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
@@ -0,0 +1,124 @@
+// Package policy implements the validation of definitions against the rules of a policy.
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func Test_parsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    path
+		wantErr string
+	}{
+		{
+			name: "Tests dot separated keys",
+			path: "metadata.labels.env",
+			want: path{{key: "metadata"}, {key: "labels"}, {key: "env"}},
+		},
+		{
+			name: "Tests quoted keys and wildcards",
+			path: `spec.configs["retention.ms"].acls[*]['a b']`,
+			want: path{{key: "spec"}, {key: "configs"}, {key: "retention.ms"}, {key: "acls"}, {wildcard: true}, {key: "a b"}},
+		},
+		{
+			name:    "Tests an empty key",
+			path:    "spec..partitions",
+			wantErr: "empty key",
+		},
+		{
+			name:    "Tests a trailing dot",
+			path:    "spec.",
+			wantErr: "empty key",
+		},
+		{
+			name:    "Tests an unclosed bracket",
+			path:    `spec.configs["retention.ms"`,
+			wantErr: "unclosed bracket",
+		},
+		{
+			name:    "Tests an unquoted key in brackets",
+			path:    "spec.acls[0]",
+			wantErr: "brackets must contain a quoted key or *",
+		},
+		{
+			name:    "Tests a key following brackets without a dot",
+			path:    "spec.acls[*]operations",
+			wantErr: "missing dot before key",
+		},
+		{
+			name:    "Tests an empty path",
+			path:    "",
+			wantErr: "empty path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePath(tt.path)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("parsePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolve(t *testing.T) {
+	doc := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"configs": map[string]interface{}{
+				"retention.ms":   "86400000",
+				"cleanup.policy": "delete",
+			},
+			"acls": []interface{}{
+				map[string]interface{}{"operations": []interface{}{"READ", "WRITE"}},
+				map[string]interface{}{"operations": []interface{}{"ALL"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want []interface{}
+	}{
+		{
+			name: "Tests a quoted key",
+			path: `spec.configs["retention.ms"]`,
+			want: []interface{}{"86400000"},
+		},
+		{
+			name: "Tests wildcards over lists",
+			path: "spec.acls[*].operations[*]",
+			want: []interface{}{"READ", "WRITE", "ALL"},
+		},
+		{
+			name: "Tests a wildcard over a map in key order",
+			path: "spec.configs[*]",
+			want: []interface{}{"delete", "86400000"},
+		},
+		{
+			name: "Tests a missing key",
+			path: "spec.partitions",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resolve(doc, p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package policy implements the validation of definitions against the rules of a policy.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/util/str"
+)
+
+// Severities of rule violations.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var severities = []string{SeverityError, SeverityWarning}
+
+// Policy represents a set of rules that definitions must comply with.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule represents a policy rule.
+// A definition violates a rule if all of its when conditions hold and any of its assert conditions do not.
+type Rule struct {
+	Name string `json:"name"`
+	// The kinds of definitions the rule applies to. The rule applies to all kinds if empty.
+	Kinds []string `json:"kinds,omitempty"`
+	// A path to a list or map; the conditions are evaluated for each element, referenced by the path root "each".
+	ForEach string `json:"forEach,omitempty"`
+	// Conditions that must hold for the rule to apply.
+	When []Condition `json:"when,omitempty"`
+	// Conditions that must hold for a definition to comply with the rule.
+	Assert []Condition `json:"assert"`
+	// A message reported for violations instead of the message of the failed condition.
+	Message string `json:"message,omitempty"`
+	// The severity of violations (error, warning). Violations with warning severity do not prevent an apply.
+	Severity string `json:"severity,omitempty"`
+
+	forEach path
+}
+
+// Violation represents a violation of a policy rule by a definition.
+type Violation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Message  string `json:"message"`
+	// The position of the definition document in its source, if known.
+	Source *def.Source `json:"source,omitempty"`
+}
+
+// String returns a description of the violation.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s definition %q violates policy rule %q: %s", v.Kind, v.Name, v.Rule, v.Message)
+}
+
+// Load loads a policy from a YAML or JSON file.
+func Load(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %v", err)
+	}
+	p, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %q: %v", path, err)
+	}
+	return p, nil
+}
+
+// Parse parses and validates a YAML or JSON policy.
+func Parse(b []byte) (*Policy, error) {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Policy
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// compile validates the rules of the policy and compiles their paths and expressions.
+func (p *Policy) compile() error {
+	names := map[string]bool{}
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if len(r.Name) == 0 {
+			return fmt.Errorf("rules[%d].name must be supplied", i)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("rules[%d].name %q is not unique", i, r.Name)
+		}
+		names[r.Name] = true
+
+		if len(r.Severity) == 0 {
+			r.Severity = SeverityError
+		}
+		if !str.Contains(r.Severity, severities) {
+			return fmt.Errorf("rules[%d].severity must be one of %q", i, strings.Join(severities, "|"))
+		}
+
+		if len(r.ForEach) > 0 {
+			var err error
+			if r.forEach, err = parsePath(r.ForEach); err != nil {
+				return fmt.Errorf("rules[%d].forEach: %v", i, err)
+			}
+		}
+
+		if len(r.Assert) == 0 {
+			return fmt.Errorf("rules[%d].assert must contain at least one condition", i)
+		}
+		for j := range r.When {
+			if err := r.When[j].compile(); err != nil {
+				return fmt.Errorf("rules[%d].when[%d]: %v", i, j, err)
+			}
+		}
+		for j := range r.Assert {
+			if err := r.Assert[j].compile(); err != nil {
+				return fmt.Errorf("rules[%d].assert[%d]: %v", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Errors returns the violations with error severity.
+func Errors(violations []Violation) []Violation {
+	var errs []Violation
+	for _, v := range violations {
+		if v.Severity == SeverityError {
+			errs = append(errs, v)
+		}
+	}
+	return errs
+}
+
+// Evaluate evaluates a definition document decoded from JSON or YAML against the rules of the policy.
+// All violations are returned.
+func (p *Policy) Evaluate(doc interface{}) []Violation {
+	var kind, name string
+	if m, ok := doc.(map[string]interface{}); ok {
+		kind, _ = m["kind"].(string)
+		if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+			name, _ = metadata["name"].(string)
+		}
+	}
+
+	var violations []Violation
+	for _, r := range p.Rules {
+		if len(r.Kinds) > 0 && !str.Contains(kind, r.Kinds) {
+			continue
+		}
+		for _, msg := range r.evaluate(doc) {
+			violations = append(violations, Violation{
+				Rule:     r.Name,
+				Severity: r.Severity,
+				Kind:     kind,
+				Name:     name,
+				Message:  msg,
+			})
+		}
+	}
+	return violations
+}
+
+// evaluate evaluates a definition document against the rule and returns the messages of violations.
+func (r Rule) evaluate(doc interface{}) []string {
+	scopes := []scope{{doc: doc}}
+	if r.forEach != nil {
+		scopes = nil
+		for _, each := range resolve(doc, r.forEach) {
+			scopes = append(scopes, scope{doc: doc, each: each})
+		}
+	}
+
+	var msgs []string
+	reported := map[string]bool{}
+	for _, s := range scopes {
+		if !r.applies(s) {
+			continue
+		}
+		for _, c := range r.Assert {
+			msg, ok := c.evaluate(s)
+			if ok {
+				continue
+			}
+			if len(r.Message) > 0 {
+				msg = r.Message
+			}
+			if !reported[msg] {
+				reported[msg] = true
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	return msgs
+}
+
+// applies determines if all when conditions of the rule hold in a scope.
+func (r Rule) applies(s scope) bool {
+	for _, c := range r.When {
+		if _, ok := c.evaluate(s); !ok {
+			return false
+		}
+	}
+	return true
+}
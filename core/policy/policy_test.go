@@ -0,0 +1,326 @@
+// Package policy implements the validation of definitions against the rules of a policy.
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+var testPolicy = `
+rules:
+  - name: topic-name
+    kinds: [topic]
+    assert:
+      - path: metadata.name
+        matches: '^[a-z]+\.[a-z-]+\.v[0-9]+$'
+  - name: prod-replication-factor
+    kinds: [topic]
+    when:
+      - path: env.KDEF_ENV
+        equals: prod
+    assert:
+      - path: spec.replicationFactor
+        gte: 3
+  - name: min-insync-replicas
+    kinds: [topic]
+    when:
+      - path: spec.configs["min.insync.replicas"]
+        exists: true
+    assert:
+      - path: spec.configs["min.insync.replicas"]
+        lt:
+          path: spec.replicationFactor
+  - name: retention
+    kinds: [topic]
+    when:
+      - path: metadata.labels.retention-exception
+        exists: false
+      - path: spec.configs["retention.ms"]
+        exists: true
+    assert:
+      - path: spec.configs["retention.ms"]
+        gte: 0
+      - path: spec.configs["retention.ms"]
+        lte: 2592000000
+    message: retention.ms must be at most 30 days unless labelled retention-exception
+  - name: no-all-on-prefixed-wildcard
+    kinds: [acl]
+    forEach: spec.acls[*]
+    when:
+      - path: metadata.resourcePatternType
+        equals: prefixed
+      - path: metadata.name
+        equals: "*"
+      - path: each.permissionType
+        equals: ALLOW
+    assert:
+      - path: each.operations[*]
+        notEquals: ALL
+`
+
+func TestPolicy_Evaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		env  string
+		doc  string
+		want []Violation
+	}{
+		{
+			name: "Tests a compliant topic",
+			env:  "prod",
+			doc: `
+apiVersion: v1
+kind: topic
+metadata:
+  name: store.orders-created.v1
+spec:
+  configs:
+    min.insync.replicas: "2"
+    retention.ms: "86400000"
+  partitions: 3
+  replicationFactor: 3
+`,
+			want: nil,
+		},
+		{
+			name: "Tests a topic violating all rules",
+			env:  "prod",
+			doc: `
+apiVersion: v1
+kind: topic
+metadata:
+  name: orders
+spec:
+  configs:
+    min.insync.replicas: "2"
+    retention.ms: "-1"
+  partitions: 3
+  replicationFactor: 2
+`,
+			want: []Violation{
+				{
+					Rule:     "topic-name",
+					Severity: SeverityError,
+					Kind:     "topic",
+					Name:     "orders",
+					Message:  `metadata.name must match "^[a-z]+\\.[a-z-]+\\.v[0-9]+$" but is "orders"`,
+				},
+				{
+					Rule:     "prod-replication-factor",
+					Severity: SeverityError,
+					Kind:     "topic",
+					Name:     "orders",
+					Message:  "spec.replicationFactor must be greater than or equal to 3 but is 2",
+				},
+				{
+					Rule:     "min-insync-replicas",
+					Severity: SeverityError,
+					Kind:     "topic",
+					Name:     "orders",
+					Message:  `spec.configs["min.insync.replicas"] must be less than spec.replicationFactor (2) but is "2"`,
+				},
+				{
+					Rule:     "retention",
+					Severity: SeverityError,
+					Kind:     "topic",
+					Name:     "orders",
+					Message:  "retention.ms must be at most 30 days unless labelled retention-exception",
+				},
+			},
+		},
+		{
+			name: "Tests rules that do not apply",
+			env:  "dev",
+			doc: `
+apiVersion: v1
+kind: topic
+metadata:
+  name: store.orders.v1
+  labels:
+    retention-exception: "true"
+spec:
+  configs:
+    retention.ms: "-1"
+  partitions: 3
+  replicationFactor: 1
+`,
+			want: nil,
+		},
+		{
+			name: "Tests an acl granting all operations on a prefixed wildcard resource",
+			doc: `
+apiVersion: v1
+kind: acl
+metadata:
+  name: "*"
+  type: topic
+  resourcePatternType: prefixed
+spec:
+  acls:
+    - principals: ["User:foo"]
+      hosts: ["*"]
+      operations: ["ALL"]
+      permissionType: DENY
+    - principals: ["User:bar"]
+      hosts: ["*"]
+      operations: ["READ", "ALL"]
+      permissionType: ALLOW
+`,
+			want: []Violation{
+				{
+					Rule:     "no-all-on-prefixed-wildcard",
+					Severity: SeverityError,
+					Kind:     "acl",
+					Name:     "*",
+					Message:  `each.operations[*] must not equal "ALL" but is "ALL"`,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KDEF_ENV", tt.env)
+
+			var doc interface{}
+			if err := yaml.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Evaluate(doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "Tests a valid policy",
+			policy: `
+rules:
+  - name: foo
+    assert:
+      - path: spec.partitions
+        in: [1, 3]
+`,
+			wantErr: "",
+		},
+		{
+			name: "Tests an unknown field",
+			policy: `
+rules:
+  - name: foo
+    assert:
+      - path: spec.partitions
+        equal: 3
+`,
+			wantErr: "unknown field \"equal\"",
+		},
+		{
+			name: "Tests a rule without a name",
+			policy: `
+rules:
+  - assert:
+      - path: spec.partitions
+        gt: 0
+`,
+			wantErr: "rules[0].name must be supplied",
+		},
+		{
+			name: "Tests duplicate rule names",
+			policy: `
+rules:
+  - name: foo
+    assert:
+      - path: spec.partitions
+        gt: 0
+  - name: foo
+    assert:
+      - path: spec.partitions
+        gt: 0
+`,
+			wantErr: "rules[1].name \"foo\" is not unique",
+		},
+		{
+			name: "Tests an invalid severity",
+			policy: `
+rules:
+  - name: foo
+    severity: info
+    assert:
+      - path: spec.partitions
+        gt: 0
+`,
+			wantErr: "rules[0].severity must be one of \"error|warning\"",
+		},
+		{
+			name: "Tests a rule without assertions",
+			policy: `
+rules:
+  - name: foo
+    when:
+      - path: spec.partitions
+        gt: 0
+`,
+			wantErr: "rules[0].assert must contain at least one condition",
+		},
+		{
+			name: "Tests a condition with multiple operators",
+			policy: `
+rules:
+  - name: foo
+    assert:
+      - path: spec.partitions
+        gt: 0
+        lt: 10
+`,
+			wantErr: "rules[0].assert[0]: condition must specify exactly one operator",
+		},
+		{
+			name: "Tests an invalid regular expression",
+			policy: `
+rules:
+  - name: foo
+    when:
+      - path: metadata.name
+        matches: "("
+    assert:
+      - path: spec.partitions
+        gt: 0
+`,
+			wantErr: "rules[0].when[0]: matches must be a valid regular expression",
+		},
+		{
+			name: "Tests an invalid operand path",
+			policy: `
+rules:
+  - name: foo
+    assert:
+      - path: spec.partitions
+        gt:
+          path: spec..replicationFactor
+`,
+			wantErr: "rules[0].assert[0]: gt: invalid path \"spec..replicationFactor\": empty key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.policy)); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -95,3 +95,67 @@ var ACLExporter = ComposeHarness{
 	BrokerPort:       brokerPort + 10700,
 	Brokers:          1,
 }
+
+// QuotaApplier represents the compose harness for the quota applier tests.
+var QuotaApplier = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 10800,
+	BrokerPort:       brokerPort + 10800,
+	Brokers:          1,
+}
+
+// QuotaExporter represents the compose harness for the quota exporter tests.
+var QuotaExporter = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 10900,
+	BrokerPort:       brokerPort + 10900,
+	Brokers:          1,
+}
+
+// UserApplier represents the compose harness for the user applier tests.
+var UserApplier = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 11000,
+	BrokerPort:       brokerPort + 11000,
+	Brokers:          1,
+}
+
+// UserExporter represents the compose harness for the user exporter tests.
+var UserExporter = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 11100,
+	BrokerPort:       brokerPort + 11100,
+	Brokers:          1,
+}
+
+// ConsumerGroupApplier represents the compose harness for the consumer group applier tests.
+var ConsumerGroupApplier = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 11200,
+	BrokerPort:       brokerPort + 11200,
+	Brokers:          1,
+}
+
+// ConsumerGroupExporter represents the compose harness for the consumer group exporter tests.
+var ConsumerGroupExporter = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 11300,
+	BrokerPort:       brokerPort + 11300,
+	Brokers:          1,
+}
+
+// BrokerLoggerApplier represents the compose harness for the broker logger applier tests.
+var BrokerLoggerApplier = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 11400,
+	BrokerPort:       brokerPort + 11400,
+	Brokers:          1,
+}
+
+// BrokerLoggerExporter represents the compose harness for the broker logger exporter tests.
+var BrokerLoggerExporter = ComposeHarness{
+	ComposeFilePaths: []string{"../../test/fixtures/compose/1-broker-plaintext-compose.yml"},
+	ZookeeperPort:    zookeeperPort + 11500,
+	BrokerPort:       brokerPort + 11500,
+	Brokers:          1,
+}
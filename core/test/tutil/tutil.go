@@ -10,8 +10,8 @@ import (
 	"testing"
 
 	"github.com/peter-evans/kdef/cli/config"
-	"github.com/peter-evans/kdef/cli/ctl/apply/docparse"
 	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/helpers/docparse"
 	"github.com/peter-evans/kdef/core/model/opt"
 )
 
@@ -45,7 +45,11 @@ func FileToYAMLDocs(t *testing.T, path string) []string {
 		t.Errorf("failed to load test fixture %q: %v", path, err)
 		t.FailNow()
 	}
-	return yamlDocs
+	docs := make([]string, len(yamlDocs))
+	for i, doc := range yamlDocs {
+		docs[i] = doc.Content
+	}
+	return docs
 }
 
 // EqualJSON determines if two strings are equal JSON.
@@ -0,0 +1,169 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/operators/acl"
+	"github.com/peter-evans/kdef/core/operators/broker"
+	"github.com/peter-evans/kdef/core/operators/brokerlogger"
+	"github.com/peter-evans/kdef/core/operators/brokers"
+	"github.com/peter-evans/kdef/core/operators/consumergroup"
+	"github.com/peter-evans/kdef/core/operators/quota"
+	"github.com/peter-evans/kdef/core/operators/topic"
+	"github.com/peter-evans/kdef/core/operators/user"
+)
+
+// Applier represents an applier of a definition.
+type Applier interface {
+	Execute(ctx context.Context) *res.ApplyResult
+}
+
+// ApplierOptions represents options to configure an applier.
+type ApplierOptions struct {
+	DryRun            bool
+	ReassAwaitTimeout int
+	Plan              *res.PlannedApply
+	ReadOnly          bool
+	ClusterSnapshot   *meta.ClusterSnapshot
+	Logger            *log.Logger
+	Events            event.Handler
+}
+
+// CheckApplicable returns an error if definitions of a kind cannot be applied.
+func CheckApplicable(kind string) error {
+	switch kind {
+	case def.KindACL, def.KindBroker, def.KindBrokerLogger, def.KindBrokers,
+		def.KindConsumerGroup, def.KindQuota, def.KindTopic, def.KindUser:
+		return nil
+	case def.KindTopicProfile:
+		return fmt.Errorf("%s definitions cannot be applied", kind)
+	}
+	return fmt.Errorf("invalid definition kind %q", kind)
+}
+
+// NewApplier creates an applier for the kind of a definition.
+// Returns an error if definitions of the kind cannot be applied.
+func NewApplier(cl *client.Client, d Definition, opts ApplierOptions) (Applier, error) {
+	if err := CheckApplicable(d.Resource.Kind); err != nil {
+		return nil, err
+	}
+
+	switch d.Resource.Kind {
+	case def.KindACL:
+		return acl.NewApplier(cl, d.Document, acl.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	case def.KindBroker:
+		return broker.NewApplier(cl, d.Document, broker.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	case def.KindBrokerLogger:
+		return brokerlogger.NewApplier(cl, d.Document, brokerlogger.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	case def.KindBrokers:
+		return brokers.NewApplier(cl, d.Document, brokers.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	case def.KindConsumerGroup:
+		return consumergroup.NewApplier(cl, d.Document, consumergroup.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	case def.KindQuota:
+		return quota.NewApplier(cl, d.Document, quota.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	case def.KindTopic:
+		return topic.NewApplier(cl, d.Document, topic.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+			ReassAwaitTimeout:  opts.ReassAwaitTimeout,
+			ClusterSnapshot:    opts.ClusterSnapshot,
+		}), nil
+	case def.KindUser:
+		return user.NewApplier(cl, d.Document, user.ApplierOptions{
+			DefinitionFormat:   d.Format,
+			PropertyOverrides:  d.PropertyOverrides,
+			LenientAPIVersions: d.LenientAPIVersions,
+			DryRun:             opts.DryRun,
+			Plan:               opts.Plan,
+			ReadOnly:           opts.ReadOnly,
+			Logger:             opts.Logger,
+			Events:             opts.Events,
+			Source:             d.Source,
+			Labels:             d.Resource.Metadata.Labels,
+		}), nil
+	}
+	return nil, CheckApplicable(d.Resource.Kind)
+}
@@ -0,0 +1,46 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestNewApplier(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		wantErr string
+	}{
+		{
+			name: "Tests an applicable kind",
+			kind: def.KindTopic,
+		},
+		{
+			name:    "Tests a kind that cannot be applied",
+			kind:    def.KindTopicProfile,
+			wantErr: "topicProfile definitions cannot be applied",
+		},
+		{
+			name:    "Tests an invalid kind",
+			kind:    "foo",
+			wantErr: "invalid definition kind \"foo\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Definition{Resource: def.ResourceDefinition{Kind: tt.kind}}
+			applier, err := NewApplier(nil, d, ApplierOptions{})
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("NewApplier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (applier == nil) != (len(tt.wantErr) > 0) {
+				t.Errorf("NewApplier() applier = %v, wantErr %v", applier, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/audit"
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/event"
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/hooks"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/policy"
+)
+
+// ApplyOptions represents options to apply definitions.
+type ApplyOptions struct {
+	// Options to load definition documents.
+	DefinitionFormat  opt.DefinitionFormat
+	PropertyOverrides []string
+	// LenientAPIVersions are the apiVersions of definitions accepted for every kind with unknown fields ignored,
+	// e.g. definitions written for a newer version of kdef.
+	LenientAPIVersions []string
+	// Selector selects the definitions to apply by their labels, if not nil.
+	Selector selector.Selector
+
+	DryRun            bool
+	ReassAwaitTimeout int
+	ContinueOnError   bool
+	// ReadOnly determines the drift of remote resources from their definitions without applying changes.
+	ReadOnly bool
+	// Parallelism is the maximum number of definitions applied concurrently.
+	// Definitions are applied one at a time if less than or equal to 1.
+	Parallelism int
+	Logger      *log.Logger
+	Events      event.Handler
+	// Hooks run commands and call webhooks on the events of each definition.
+	Hooks []client.HookConfig
+	// AuditLog records applied changes, unless in dry-run or read-only mode.
+	AuditLog *audit.Log
+	// Policy has rules that definitions must comply with before any are applied.
+	// Violations with warning severity are logged.
+	Policy *policy.Policy
+}
+
+// Apply applies the definitions of content containing one or more definition documents.
+// Results are in the order of the definitions.
+func Apply(ctx context.Context, cl *client.Client, content []byte, opts ApplyOptions) (res.ApplyResults, error) {
+	defs, err := LoadDefinitions(content, opts.DefinitionFormat, opts.PropertyOverrides, opts.LenientAPIVersions)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyDefinitions(ctx, cl, SelectDefinitions(defs, opts.Selector), opts)
+}
+
+// ApplyDefinitions applies definitions in order, concurrently if parallelism is greater than 1.
+// Unless continuing on error, no further definitions are applied after an error.
+// Errors applying individual definitions are reported in the results.
+func ApplyDefinitions(ctx context.Context, cl *client.Client, defs []Definition, opts ApplyOptions) (res.ApplyResults, error) {
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("%w: no resource definitions found", ErrInvalidDefinitions)
+	}
+	for _, d := range defs {
+		if err := CheckApplicable(d.Resource.Kind); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDefinitions, d.Source.Errorf("%v", err))
+		}
+	}
+
+	if err := hooks.Validate(opts.Hooks); err != nil {
+		return nil, err
+	}
+
+	if opts.Policy != nil {
+		violations, err := CheckPolicy(opts.Policy, defs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDefinitions, err)
+		}
+		for _, v := range violations {
+			if v.Severity == policy.SeverityWarning {
+				opts.Logger.Warnf("%v", v.Source.Errorf("%s", v))
+			}
+		}
+		if errs := policy.Errors(violations); len(errs) > 0 {
+			return nil, policyViolationsError(errs)
+		}
+	}
+
+	if err := VerifyCluster(ctx, cl); err != nil {
+		return nil, err
+	}
+
+	a := &apply{cl: cl, opts: opts}
+	if UsesClusterSnapshot(defs) {
+		opts.Logger.Infof("Fetching cluster metadata...")
+		var err error
+		a.snapshot, err = kafka.NewService(cl).DescribeClusterSnapshot(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Parallelism > 1 {
+		return a.applyConcurrently(ctx, defs), nil
+	}
+	return a.applySequentially(ctx, defs), nil
+}
+
+// apply represents the apply of definitions sharing a cluster snapshot.
+type apply struct {
+	cl       *client.Client
+	opts     ApplyOptions
+	snapshot *meta.ClusterSnapshot
+}
+
+// applySequentially applies definitions in order.
+func (a *apply) applySequentially(ctx context.Context, defs []Definition) res.ApplyResults {
+	var results res.ApplyResults
+	for _, d := range defs {
+		result := a.execute(ctx, d, a.opts.Logger)
+		results = append(results, result)
+		if result.GetErr() != nil && !a.opts.ContinueOnError {
+			break
+		}
+	}
+	return results
+}
+
+// execute applies a definition and records the apply in the audit log, if any.
+func (a *apply) execute(ctx context.Context, d Definition, logger *log.Logger) *res.ApplyResult {
+	applier, err := NewApplier(a.cl, d, ApplierOptions{
+		DryRun:            a.opts.DryRun,
+		ReassAwaitTimeout: a.opts.ReassAwaitTimeout,
+		Plan:              d.Plan,
+		ReadOnly:          a.opts.ReadOnly,
+		ClusterSnapshot:   a.snapshot,
+		Logger:            logger,
+		Events:            a.events(logger),
+	})
+	if err != nil {
+		err = d.Source.Errorf("%v", err)
+		logger.Error(err)
+		return &res.ApplyResult{Err: err.Error(), Source: d.Source}
+	}
+	result := applier.Execute(ctx)
+	if a.opts.AuditLog != nil && !a.opts.DryRun && !a.opts.ReadOnly {
+		if err := RecordAudit(ctx, a.opts.AuditLog, d, result); err != nil {
+			logger.Error(err)
+			if len(result.Err) == 0 {
+				result.Err = err.Error()
+			}
+		}
+	}
+	return result
+}
+
+// events returns the handler of the events of an applier, running hooks with the logger of the applier.
+func (a *apply) events(logger *log.Logger) event.Handler {
+	if len(a.opts.Hooks) == 0 {
+		return a.opts.Events
+	}
+	runner := hooks.NewRunner(a.opts.Hooks, logger)
+	if a.opts.Events == nil {
+		return runner
+	}
+	return event.HandlerFunc(func(ctx context.Context, e event.Event) {
+		a.opts.Events.Handle(ctx, e)
+		runner.Handle(ctx, e)
+	})
+}
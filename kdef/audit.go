@@ -0,0 +1,23 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+
+	"github.com/peter-evans/kdef/core/audit"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// RecordAudit records the apply of a definition in an audit log if the apply had changes to make.
+// The applies of dry-runs should not be recorded.
+func RecordAudit(ctx context.Context, auditLog *audit.Log, d Definition, result *res.ApplyResult) error {
+	if len(result.Diff) == 0 {
+		return nil
+	}
+	var file string
+	if d.Source != nil {
+		file = d.Source.File
+	}
+	return auditLog.Record(ctx, d.Resource.Kind, d.Resource.Metadata.Name, file, result)
+}
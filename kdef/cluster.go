@@ -0,0 +1,68 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/kafka"
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+// ClusterID fetches the ID of the cluster.
+func ClusterID(ctx context.Context, cl *client.Client) (string, error) {
+	metadata, err := kafka.NewService(cl).DescribeMetadata(ctx, []string{}, false)
+	if err != nil {
+		return "", err
+	}
+	return metadata.ClusterID, nil
+}
+
+// VerifyCluster verifies the cluster is the expected cluster of the client configuration, if any.
+func VerifyCluster(ctx context.Context, cl *client.Client) error {
+	expectedClusterID := cl.ExpectedClusterID()
+	if len(expectedClusterID) == 0 {
+		return nil
+	}
+
+	clusterID, err := ClusterID(ctx, cl)
+	if err != nil {
+		return err
+	}
+
+	return CheckClusterID(clusterID, expectedClusterID)
+}
+
+// CheckClusterID checks that a cluster ID matches the expected cluster ID.
+func CheckClusterID(clusterID string, expectedClusterID string) error {
+	if len(clusterID) == 0 {
+		return fmt.Errorf("cluster id is unavailable but expected cluster id %q is configured", expectedClusterID)
+	}
+	if clusterID != expectedClusterID {
+		return fmt.Errorf("cluster id %q does not match the expected cluster id %q", clusterID, expectedClusterID)
+	}
+	return nil
+}
+
+// UsesClusterSnapshot determines if any topic definition selects brokers by cluster use,
+// in which case a cluster snapshot should be shared between the topic appliers.
+func UsesClusterSnapshot(defs []Definition) bool {
+	for _, d := range defs {
+		if d.Resource.Kind != def.KindTopic {
+			continue
+		}
+		topicDef, err := def.LoadTopicDefinition(d.Document, d.Format, d.PropertyOverrides, d.LenientAPIVersions)
+		if err != nil {
+			// Invalid definitions are reported by the applier.
+			continue
+		}
+		if !topicDef.Spec.IsAbsent() &&
+			topicDef.Spec.HasManagedAssignments() &&
+			topicDef.Spec.ManagedAssignments.Selection == def.SelectionTopicClusterUse {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestCheckClusterID(t *testing.T) {
+	tests := []struct {
+		name              string
+		clusterID         string
+		expectedClusterID string
+		wantErr           string
+	}{
+		{
+			name:              "Tests a matching cluster id",
+			clusterID:         "dJ0nYwX3TqCYJbDDrHbYxA",
+			expectedClusterID: "dJ0nYwX3TqCYJbDDrHbYxA",
+			wantErr:           "",
+		},
+		{
+			name:              "Tests a mismatched cluster id",
+			clusterID:         "dJ0nYwX3TqCYJbDDrHbYxA",
+			expectedClusterID: "q6jDxWUvR3ulVvJ0SBbUrw",
+			wantErr:           "cluster id \"dJ0nYwX3TqCYJbDDrHbYxA\" does not match the expected cluster id \"q6jDxWUvR3ulVvJ0SBbUrw\"",
+		},
+		{
+			name:              "Tests an unavailable cluster id",
+			clusterID:         "",
+			expectedClusterID: "q6jDxWUvR3ulVvJ0SBbUrw",
+			wantErr:           "cluster id is unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckClusterID(tt.clusterID, tt.expectedClusterID); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("CheckClusterID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsesClusterSnapshot(t *testing.T) {
+	newTopicDef := func(spec string) Definition {
+		return Definition{
+			Document: "apiVersion: v1\nkind: topic\nmetadata:\n  name: foo\nspec:\n" + spec,
+			Resource: def.ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       def.KindTopic,
+				Metadata: def.ResourceMetadataDefinition{
+					Name: "foo",
+				},
+			},
+			Format: opt.YAMLFormat,
+		}
+	}
+
+	tests := []struct {
+		name string
+		defs []Definition
+		want bool
+	}{
+		{
+			name: "Tests no definitions",
+			defs: nil,
+			want: false,
+		},
+		{
+			name: "Tests a topic with default managed assignments",
+			defs: []Definition{
+				newTopicDef("  partitions: 3\n  replicationFactor: 2\n"),
+			},
+			want: true,
+		},
+		{
+			name: "Tests a topic selecting brokers by topic use",
+			defs: []Definition{
+				newTopicDef("  partitions: 3\n  replicationFactor: 2\n  managedAssignments:\n    selection: topic-use\n"),
+			},
+			want: false,
+		},
+		{
+			name: "Tests a topic declared absent",
+			defs: []Definition{
+				newTopicDef("  state: absent\n"),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UsesClusterSnapshot(tt.defs); got != tt.want {
+				t.Errorf("UsesClusterSnapshot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// applyConcurrently applies batches of independent definitions with a bounded pool of appliers.
+// The log output of each applier is buffered and flushed in the order of the definitions.
+func (a *apply) applyConcurrently(ctx context.Context, defs []Definition) res.ApplyResults {
+	var results res.ApplyResults
+	for _, batch := range independentBatches(defs) {
+		batchResults, stopped := a.applyBatch(ctx, batch)
+		results = append(results, batchResults...)
+		if stopped {
+			break
+		}
+	}
+	return results
+}
+
+// applyBatch concurrently applies a batch of independent definitions.
+// Unless continuing on error, no further appliers are started after an error and true is returned.
+func (a *apply) applyBatch(ctx context.Context, defs []Definition) (res.ApplyResults, bool) {
+	results := make(res.ApplyResults, len(defs))
+	buffers := make([]*log.Buffer, len(defs))
+
+	var mu sync.Mutex
+	var stopped bool
+	flushed := 0
+	// flush flushes the log buffers of completed appliers in order. Must be called while holding mu.
+	flush := func() {
+		for flushed < len(defs) && results[flushed] != nil {
+			buffers[flushed].Flush(a.opts.Logger)
+			flushed++
+		}
+	}
+
+	sem := make(chan struct{}, a.opts.Parallelism)
+	var wg sync.WaitGroup
+	started := 0
+	for i, d := range defs {
+		sem <- struct{}{}
+
+		mu.Lock()
+		stop := stopped
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		buffers[i] = log.NewBuffer()
+		started++
+		wg.Add(1)
+		go func(i int, d Definition) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res := a.execute(ctx, d, buffers[i].Logger())
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = res
+			if res.GetErr() != nil && !a.opts.ContinueOnError {
+				stopped = true
+			}
+			flush()
+		}(i, d)
+	}
+	wg.Wait()
+
+	// Appliers are started in order, so the results of started appliers are contiguous.
+	return results[:started], stopped
+}
+
+// independentBatches splits definitions into consecutive batches that can be applied concurrently.
+// A new batch is started for a definition of a resource already in the batch, and for a consumer
+// group definition following topic definitions, because committing offsets requires the topic.
+func independentBatches(defs []Definition) [][]Definition {
+	var batches [][]Definition
+	start := 0
+	keys := map[string]bool{}
+	containsTopic := false
+	for i, d := range defs {
+		key := resourceKey(d.Resource)
+		kind := d.Resource.Kind
+		if keys[key] || (kind == def.KindConsumerGroup && containsTopic) {
+			batches = append(batches, defs[start:i])
+			start = i
+			keys = map[string]bool{}
+			containsTopic = false
+		}
+		keys[key] = true
+		if kind == def.KindTopic {
+			containsTopic = true
+		}
+	}
+	if start < len(defs) {
+		batches = append(batches, defs[start:])
+	}
+	return batches
+}
+
+// resourceKey returns a key identifying the resource of a definition.
+func resourceKey(resourceDef def.ResourceDefinition) string {
+	// Broker logger definitions for all brokers overlap with definitions for a single broker.
+	if resourceDef.Kind == def.KindBrokerLogger {
+		return resourceDef.Kind
+	}
+	return fmt.Sprintf(
+		"%s/%s/%s/%s",
+		resourceDef.Kind,
+		resourceDef.Metadata.Type,
+		resourceDef.Metadata.ResourcePatternType,
+		resourceDef.Metadata.Name,
+	)
+}
@@ -0,0 +1,101 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+func Test_independentBatches(t *testing.T) {
+	newDef := func(kind string, name string) Definition {
+		return Definition{
+			Resource: def.ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       kind,
+				Metadata: def.ResourceMetadataDefinition{
+					Name: name,
+				},
+			},
+		}
+	}
+	names := func(batches [][]Definition) [][]string {
+		var got [][]string
+		for _, batch := range batches {
+			var batchNames []string
+			for _, d := range batch {
+				batchNames = append(batchNames, d.Resource.Kind+"/"+d.Resource.Metadata.Name)
+			}
+			got = append(got, batchNames)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name string
+		defs []Definition
+		want [][]string
+	}{
+		{
+			name: "Tests no definitions",
+			defs: nil,
+			want: nil,
+		},
+		{
+			name: "Tests independent definitions",
+			defs: []Definition{
+				newDef(def.KindTopic, "foo"),
+				newDef(def.KindTopic, "bar"),
+				newDef(def.KindACL, "foo"),
+			},
+			want: [][]string{
+				{"topic/foo", "topic/bar", "acl/foo"},
+			},
+		},
+		{
+			name: "Tests definitions of the same resource",
+			defs: []Definition{
+				newDef(def.KindTopic, "foo"),
+				newDef(def.KindTopic, "bar"),
+				newDef(def.KindTopic, "foo"),
+			},
+			want: [][]string{
+				{"topic/foo", "topic/bar"},
+				{"topic/foo"},
+			},
+		},
+		{
+			name: "Tests broker logger definitions",
+			defs: []Definition{
+				newDef(def.KindBrokerLogger, "all"),
+				newDef(def.KindBrokerLogger, "1"),
+			},
+			want: [][]string{
+				{"brokerLogger/all"},
+				{"brokerLogger/1"},
+			},
+		},
+		{
+			name: "Tests consumer group definitions following topic definitions",
+			defs: []Definition{
+				newDef(def.KindConsumerGroup, "baz"),
+				newDef(def.KindTopic, "foo"),
+				newDef(def.KindConsumerGroup, "foo"),
+				newDef(def.KindConsumerGroup, "bar"),
+			},
+			want: [][]string{
+				{"consumerGroup/baz", "topic/foo"},
+				{"consumerGroup/foo", "consumerGroup/bar"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(independentBatches(tt.defs)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("independentBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
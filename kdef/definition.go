@@ -0,0 +1,116 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+//
+// Operations return typed results and log to the sink of a log.Logger rather than
+// writing to standard output or standard error.
+package kdef
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/helpers/docparse"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// ErrInvalidDefinitions is returned when definition documents cannot be loaded.
+var ErrInvalidDefinitions = errors.New("invalid definitions")
+
+// Definition represents a resource definition document.
+type Definition struct {
+	Document          string
+	Resource          def.ResourceDefinition
+	Format            opt.DefinitionFormat
+	PropertyOverrides []string
+	// LenientAPIVersions are the apiVersions of definitions accepted for every kind with unknown fields ignored.
+	LenientAPIVersions []string
+	// Source is the position of the definition document in its source, if known.
+	Source *def.Source
+	// Plan is the planned apply of the definition, if applying a plan.
+	Plan *res.PlannedApply
+}
+
+// LoadDefinitions loads the definitions of content containing one or more definition documents.
+// The topic profiles referenced by topic definitions are resolved.
+func LoadDefinitions(
+	content []byte,
+	format opt.DefinitionFormat,
+	propOverrides []string,
+	lenientAPIVersions []string,
+) ([]Definition, error) {
+	docs, err := docparse.FromBytes(content, docparse.Format(format))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read definition(s): %v", ErrInvalidDefinitions, err)
+	}
+
+	defs, err := DocumentDefinitions(docs, "", format, propOverrides, lenientAPIVersions)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDefinitions, err)
+	}
+
+	if defs, err = ResolveProfiles(defs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDefinitions, err)
+	}
+
+	return defs, nil
+}
+
+// DocumentDefinitions creates the definitions of parsed definition documents loaded from a file, if any.
+// The definitions are sourced from the positions of the documents.
+func DocumentDefinitions(
+	docs []docparse.Document,
+	file string,
+	format opt.DefinitionFormat,
+	propOverrides []string,
+	lenientAPIVersions []string,
+) ([]Definition, error) {
+	defs := make([]Definition, len(docs))
+	for i, doc := range docs {
+		source := &def.Source{
+			File: file,
+			Doc:  doc.Index + 1,
+			Line: doc.Line,
+		}
+		resourceDefs, err := ResourceDefinitions([]string{doc.Content}, format, lenientAPIVersions)
+		if err != nil {
+			return nil, source.Errorf("invalid resource definition: %v", err)
+		}
+		defs[i] = Definition{
+			Document:           doc.Content,
+			Resource:           resourceDefs[0],
+			Format:             format,
+			PropertyOverrides:  propOverrides,
+			LenientAPIVersions: lenientAPIVersions,
+			Source:             source,
+		}
+	}
+
+	return defs, nil
+}
+
+// ResourceDefinitions parses and validates the resource definitions of definition documents.
+// Definitions of the lenient apiVersions are accepted for every kind.
+func ResourceDefinitions(
+	defDocs []string,
+	format opt.DefinitionFormat,
+	lenientAPIVersions []string,
+) ([]def.ResourceDefinition, error) {
+	kinds := make([]def.ResourceDefinition, len(defDocs))
+
+	for i, defDoc := range defDocs {
+		resourceDef, err := def.LoadResourceDefinition(defDoc, format, lenientAPIVersions)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := resourceDef.ValidateResource(); err != nil {
+			return nil, err
+		}
+
+		kinds[i] = resourceDef
+	}
+
+	return kinds, nil
+}
@@ -1,16 +1,17 @@
-// Package apply implements the apply controller.
-package apply
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
 
 import (
 	"reflect"
 	"testing"
 
-	"github.com/peter-evans/kdef/cli/test/tutil"
 	"github.com/peter-evans/kdef/core/model/def"
 	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
 )
 
-func Test_getResourceDefinitions(t *testing.T) {
+func TestResourceDefinitions(t *testing.T) {
 	type args struct {
 		defDocs []string
 		format  opt.DefinitionFormat
@@ -168,14 +169,50 @@ func Test_getResourceDefinitions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getResourceDefinitions(tt.args.defDocs, tt.args.format)
+			got, err := ResourceDefinitions(tt.args.defDocs, tt.args.format, nil)
 			if !tutil.ErrorContains(err, tt.wantErr) {
-				t.Errorf("getResourceDefinitions() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ResourceDefinitions() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("getResourceDefinitions() = %v, want %v", got, tt.want)
+				t.Errorf("ResourceDefinitions() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestLoadDefinitions(t *testing.T) {
+	content := []byte(`apiVersion: v1
+kind: topic
+metadata:
+  name: foo
+---
+# Topic bar
+apiVersion: v1
+kind: topic
+metadata:
+  name: bar
+`)
+
+	defs, err := LoadDefinitions(content, opt.YAMLFormat, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadDefinitions() error = %v", err)
+	}
+	var got []def.Source
+	for _, d := range defs {
+		got = append(got, *d.Source)
+	}
+	want := []def.Source{
+		{Doc: 1, Line: 1},
+		{Doc: 2, Line: 7},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadDefinitions() sources = %v, want %v", got, want)
+	}
+
+	content = append(content, []byte("---\napiVersion: v1\nkind: foo\nmetadata:\n  name: baz\n")...)
+	wantErr := "line 12 (document 3): invalid resource definition: invalid definition kind \"foo\""
+	if _, err := LoadDefinitions(content, opt.YAMLFormat, nil, nil); !tutil.ErrorContains(err, wantErr) {
+		t.Errorf("LoadDefinitions() error = %v, wantErr %v", err, wantErr)
+	}
+}
@@ -0,0 +1,95 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/log"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/operators/acl"
+	"github.com/peter-evans/kdef/core/operators/broker"
+	"github.com/peter-evans/kdef/core/operators/brokerlogger"
+	"github.com/peter-evans/kdef/core/operators/brokers"
+	"github.com/peter-evans/kdef/core/operators/consumergroup"
+	"github.com/peter-evans/kdef/core/operators/quota"
+	"github.com/peter-evans/kdef/core/operators/topic"
+	"github.com/peter-evans/kdef/core/operators/user"
+)
+
+// Exporter represents an exporter of resources.
+type Exporter interface {
+	Execute(ctx context.Context) (res.ExportResults, error)
+}
+
+// ExporterOptions represents options to configure an exporter.
+type ExporterOptions struct {
+	// Options for topic/acl/consumer group definitions.
+	Match   string
+	Exclude string
+
+	// Options for topic definitions.
+	TopicIncludeInternal bool
+	TopicAssignments     opt.Assignments
+	TopicInferProfiles   bool
+
+	// Options for acl definitions.
+	ACLResourceType string
+	ACLAutoGroup    bool
+
+	Logger *log.Logger
+}
+
+// NewExporter creates an exporter for resources of a kind.
+// Returns nil if the kind is not supported.
+func NewExporter(cl *client.Client, kind string, opts ExporterOptions) Exporter {
+	switch kind {
+	case def.KindACL:
+		return acl.NewExporter(cl, acl.ExporterOptions{
+			Match:        opts.Match,
+			Exclude:      opts.Exclude,
+			ResourceType: opts.ACLResourceType,
+			AutoGroup:    opts.ACLAutoGroup,
+			Logger:       opts.Logger,
+		})
+	case def.KindBroker:
+		return broker.NewExporter(cl, broker.ExporterOptions{Logger: opts.Logger})
+	case def.KindBrokerLogger:
+		return brokerlogger.NewExporter(cl, brokerlogger.ExporterOptions{Logger: opts.Logger})
+	case def.KindBrokers:
+		return brokers.NewExporter(cl, brokers.ExporterOptions{Logger: opts.Logger})
+	case def.KindConsumerGroup:
+		return consumergroup.NewExporter(cl, consumergroup.ExporterOptions{
+			Match:   opts.Match,
+			Exclude: opts.Exclude,
+			Logger:  opts.Logger,
+		})
+	case def.KindQuota:
+		return quota.NewExporter(cl, quota.ExporterOptions{Logger: opts.Logger})
+	case def.KindTopic:
+		return topic.NewExporter(cl, topic.ExporterOptions{
+			Match:           opts.Match,
+			Exclude:         opts.Exclude,
+			IncludeInternal: opts.TopicIncludeInternal,
+			Assignments:     opts.TopicAssignments,
+			InferProfiles:   opts.TopicInferProfiles,
+			Logger:          opts.Logger,
+		})
+	case def.KindUser:
+		return user.NewExporter(cl, user.ExporterOptions{Logger: opts.Logger})
+	}
+	return nil
+}
+
+// Export exports the resources of a kind to definitions.
+func Export(ctx context.Context, cl *client.Client, kind string, opts ExporterOptions) (res.ExportResults, error) {
+	exporter := NewExporter(cl, kind, opts)
+	if exporter == nil {
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+	return exporter.Execute(ctx)
+}
@@ -0,0 +1,126 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/client"
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+)
+
+// Plan creates a plan from the definitions of content containing one or more definition documents.
+// Definitions are applied in dry-run mode, and the plan is not created if any definition fails.
+func Plan(ctx context.Context, cl *client.Client, content []byte, opts ApplyOptions) (*res.Plan, res.ApplyResults, error) {
+	defs, err := LoadDefinitions(content, opts.DefinitionFormat, opts.PropertyOverrides, opts.LenientAPIVersions)
+	if err != nil {
+		return nil, nil, err
+	}
+	defs = SelectDefinitions(defs, opts.Selector)
+
+	opts.DryRun = true
+	results, err := ApplyDefinitions(ctx, cl, defs, opts)
+	if err != nil {
+		return nil, results, err
+	}
+	if results.ContainsErr() {
+		return nil, results, fmt.Errorf("planning completed with errors")
+	}
+
+	plan, err := NewPlan(ctx, cl, defs, results)
+	if err != nil {
+		return nil, results, err
+	}
+
+	return plan, results, nil
+}
+
+// NewPlan creates a plan for the cluster from definitions and their dry-run apply results.
+func NewPlan(ctx context.Context, cl *client.Client, defs []Definition, results res.ApplyResults) (*res.Plan, error) {
+	clusterID, err := ClusterID(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &res.Plan{
+		Version:   res.PlanVersion,
+		ClusterID: clusterID,
+		Applies:   make([]res.PlannedApply, len(results)),
+	}
+	for i, result := range results {
+		if plan.Applies[i], err = NewPlannedApply(defs[i], result); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// NewPlannedApply creates a planned apply from a definition and its apply result.
+func NewPlannedApply(d Definition, result *res.ApplyResult) (res.PlannedApply, error) {
+	ops, err := json.Marshal(result.Operations)
+	if err != nil {
+		return res.PlannedApply{}, fmt.Errorf("failed to marshal operations: %v", err)
+	}
+
+	return res.PlannedApply{
+		Kind:               d.Resource.Kind,
+		Name:               d.Resource.Metadata.Name,
+		Format:             d.Format.String(),
+		Definition:         d.Document,
+		PropertyOverrides:  d.PropertyOverrides,
+		LenientAPIVersions: d.LenientAPIVersions,
+		Fingerprint:        result.Fingerprint,
+		Operations:         ops,
+		Diff:               result.Diff,
+		Source:             d.Source,
+	}, nil
+}
+
+// PlanDefinitions creates the definitions of the planned applies of a plan loaded from a file, if any.
+// Applying the definitions executes the planned operations.
+func PlanDefinitions(plan *res.Plan, file string) ([]Definition, error) {
+	defs := make([]Definition, len(plan.Applies))
+	for i := range plan.Applies {
+		planned := &plan.Applies[i]
+		format := opt.ParseDefinitionFormat(planned.Format)
+		resourceDefs, err := ResourceDefinitions([]string{planned.Definition}, format, planned.LenientAPIVersions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource definition in plan: %v", err)
+		}
+		if err := CheckApplicable(resourceDefs[0].Kind); err != nil {
+			return nil, fmt.Errorf("invalid resource definition in plan: %v", err)
+		}
+		// Plans saved without sources are sourced from the position of the apply in the plan.
+		source := planned.Source
+		if source == nil {
+			source = &def.Source{File: file, Doc: i + 1}
+		}
+		defs[i] = Definition{
+			Document:           planned.Definition,
+			Resource:           resourceDefs[0],
+			Format:             format,
+			PropertyOverrides:  planned.PropertyOverrides,
+			LenientAPIVersions: planned.LenientAPIVersions,
+			Source:             source,
+			Plan:               planned,
+		}
+	}
+	return defs, nil
+}
+
+// VerifyPlanCluster verifies the cluster is the cluster a plan was created for.
+func VerifyPlanCluster(ctx context.Context, cl *client.Client, plan *res.Plan) error {
+	clusterID, err := ClusterID(ctx, cl)
+	if err != nil {
+		return err
+	}
+	if plan.ClusterID != clusterID {
+		return fmt.Errorf("plan was created for cluster %q but the target cluster is %q", plan.ClusterID, clusterID)
+	}
+	return nil
+}
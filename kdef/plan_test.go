@@ -0,0 +1,153 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/model/res"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestNewPlannedApply(t *testing.T) {
+	defDoc := `apiVersion: v1
+kind: topic
+metadata:
+  name: store.foo
+spec:
+  partitions: 3
+  replicationFactor: 1
+`
+	d := Definition{
+		Document: defDoc,
+		Resource: def.ResourceDefinition{
+			APIVersion: "v1",
+			Kind:       def.KindTopic,
+			Metadata: def.ResourceMetadataDefinition{
+				Name: "store.foo",
+			},
+		},
+		Format:            opt.YAMLFormat,
+		PropertyOverrides: []string{"topic.spec.managedAssignments.balance=all"},
+	}
+	result := &res.ApplyResult{
+		Diff:        "+ partitions",
+		Fingerprint: "abc123",
+		Operations: struct {
+			Create bool `json:"create"`
+		}{Create: true},
+	}
+
+	got, err := NewPlannedApply(d, result)
+	if err != nil {
+		t.Errorf("NewPlannedApply() error = %v", err)
+		return
+	}
+
+	want := res.PlannedApply{
+		Kind:              def.KindTopic,
+		Name:              "store.foo",
+		Format:            "yaml",
+		Definition:        defDoc,
+		PropertyOverrides: []string{"topic.spec.managedAssignments.balance=all"},
+		Fingerprint:       "abc123",
+		Operations:        []byte(`{"create":true}`),
+		Diff:              "+ partitions",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewPlannedApply() = %v, want %v", got, want)
+		return
+	}
+
+	// The plan must survive a round trip through a plan file.
+	path := filepath.Join(t.TempDir(), "plan.json")
+	plan := res.Plan{
+		Version:   res.PlanVersion,
+		ClusterID: "foo",
+		Applies:   []res.PlannedApply{got},
+	}
+	if err := plan.Save(path); err != nil {
+		t.Errorf("res.Plan.Save() error = %v", err)
+		return
+	}
+	loaded, err := res.LoadPlan(path)
+	if err != nil {
+		t.Errorf("res.LoadPlan() error = %v", err)
+		return
+	}
+	if loaded.ClusterID != plan.ClusterID || len(loaded.Applies) != 1 {
+		t.Errorf("res.LoadPlan() = %v, want %v", loaded, plan)
+		return
+	}
+
+	topicDef, err := def.LoadTopicDefinition(
+		loaded.Applies[0].Definition,
+		opt.ParseDefinitionFormat(loaded.Applies[0].Format),
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Errorf("def.LoadTopicDefinition() error = %v", err)
+		return
+	}
+	if topicDef.Spec.Partitions != 3 {
+		t.Errorf("topicDef.Spec.Partitions = %v, want %v", topicDef.Spec.Partitions, 3)
+	}
+}
+
+func TestPlanDefinitions(t *testing.T) {
+	topicDoc := "apiVersion: v1\nkind: topic\nmetadata:\n  name: store.foo\nspec:\n  partitions: 3\n  replicationFactor: 1\n"
+
+	tests := []struct {
+		name       string
+		defDoc     string
+		wantSource *def.Source
+		wantErr    string
+	}{
+		{
+			name:       "Tests a plan of a topic sourced from its position in the plan",
+			defDoc:     topicDoc,
+			wantSource: &def.Source{File: "plan.json", Doc: 1},
+		},
+		{
+			name:    "Tests a plan of a kind that cannot be applied",
+			defDoc:  "apiVersion: v1\nkind: topicProfile\nmetadata:\n  name: compacted\n",
+			wantErr: "invalid resource definition in plan: topicProfile definitions cannot be applied",
+		},
+		{
+			name:    "Tests a plan of an invalid kind",
+			defDoc:  "apiVersion: v1\nkind: foo\nmetadata:\n  name: compacted\n",
+			wantErr: "invalid resource definition in plan: invalid definition kind \"foo\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &res.Plan{
+				Version:   res.PlanVersion,
+				ClusterID: "foo",
+				Applies: []res.PlannedApply{
+					{Format: "yaml", Definition: tt.defDoc},
+				},
+			}
+			got, err := PlanDefinitions(plan, "plan.json")
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("PlanDefinitions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != 1 || got[0].Plan != &plan.Applies[0] {
+				t.Errorf("PlanDefinitions() = %v, want the planned apply of each definition", got)
+				return
+			}
+			if !reflect.DeepEqual(got[0].Source, tt.wantSource) {
+				t.Errorf("PlanDefinitions() source = %v, want %v", got[0].Source, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/policy"
+)
+
+// ErrPolicyViolations is returned when definitions violate the rules of a policy.
+var ErrPolicyViolations = errors.New("policy violations")
+
+// CheckPolicy evaluates definitions against the rules of a policy and returns all violations.
+func CheckPolicy(p *policy.Policy, defs []Definition) ([]policy.Violation, error) {
+	var violations []policy.Violation
+	for _, d := range defs {
+		var doc interface{}
+		switch d.Format {
+		case opt.YAMLFormat:
+			if err := yaml.Unmarshal([]byte(d.Document), &doc); err != nil {
+				return nil, err
+			}
+		case opt.JSONFormat:
+			if err := json.Unmarshal([]byte(d.Document), &doc); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported format")
+		}
+		for _, v := range p.Evaluate(doc) {
+			v.Source = d.Source
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// policyViolationsError returns an error describing policy violations.
+func policyViolationsError(violations []policy.Violation) error {
+	descs := make([]string, len(violations))
+	for i, v := range violations {
+		descs[i] = v.Source.Errorf("%s", v).Error()
+	}
+	return fmt.Errorf("%w: %s", ErrPolicyViolations, strings.Join(descs, "; "))
+}
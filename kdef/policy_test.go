@@ -0,0 +1,65 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/policy"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	p, err := policy.Parse([]byte(`
+rules:
+  - name: partitions
+    kinds: [topic]
+    assert:
+      - path: spec.partitions
+        gte: 3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defs := []Definition{
+		{
+			Document: "apiVersion: v1\nkind: topic\nmetadata:\n  name: foo\nspec:\n  partitions: 1\n",
+			Format:   opt.YAMLFormat,
+		},
+		{
+			Document: `{"apiVersion":"v1","kind":"topic","metadata":{"name":"bar"},"spec":{"partitions":3}}`,
+			Format:   opt.JSONFormat,
+		},
+		{
+			Document: `{"apiVersion":"v1","kind":"topic","metadata":{"name":"baz"},"spec":{"partitions":2}}`,
+			Format:   opt.JSONFormat,
+			Source:   &def.Source{File: "topics.json", Doc: 3, Line: 9},
+		},
+	}
+
+	got, err := CheckPolicy(p, defs)
+	if err != nil {
+		t.Fatalf("CheckPolicy() error = %v", err)
+	}
+	want := []policy.Violation{
+		{Rule: "partitions", Severity: policy.SeverityError, Kind: "topic", Name: "foo", Message: "spec.partitions must be greater than or equal to 3 but is 1"},
+		{Rule: "partitions", Severity: policy.SeverityError, Kind: "topic", Name: "baz", Message: "spec.partitions must be greater than or equal to 3 but is 2", Source: &def.Source{File: "topics.json", Doc: 3, Line: 9}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckPolicy() = %v, want %v", got, want)
+	}
+
+	err = policyViolationsError(got)
+	if !errors.Is(err, ErrPolicyViolations) {
+		t.Errorf("policyViolationsError() error = %v, want %v", err, ErrPolicyViolations)
+	}
+	wantErr := "topics.json:9 (document 3): topic definition \"baz\" violates policy rule \"partitions\""
+	if !tutil.ErrorContains(err, wantErr) {
+		t.Errorf("policyViolationsError() error = %v, wantErr %v", err, wantErr)
+	}
+}
@@ -0,0 +1,126 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/opt"
+)
+
+// ResolveProfiles resolves the topic profiles referenced by topic definitions.
+// Topic profile definitions are not applied to the cluster, and are not included in the resolved definitions.
+func ResolveProfiles(defs []Definition) ([]Definition, error) {
+	profiles, err := TopicProfiles(defs)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []Definition
+	for _, d := range defs {
+		if d.Resource.Kind == def.KindTopicProfile {
+			continue
+		}
+		r, err := ResolveTopicProfiles(d, profiles)
+		if err != nil {
+			return nil, d.Source.Errorf("%v", err)
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
+}
+
+// TopicProfiles loads and validates the topic profile definitions of definitions, mapped by name.
+func TopicProfiles(defs []Definition) (map[string]def.TopicProfileDefinition, error) {
+	profiles := map[string]def.TopicProfileDefinition{}
+	sources := map[string]*def.Source{}
+	for _, d := range defs {
+		if d.Resource.Kind != def.KindTopicProfile {
+			continue
+		}
+		profileDef, err := def.LoadTopicProfileDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return nil, d.Source.Errorf("%v", err)
+		}
+		if err := profileDef.Validate(); err != nil {
+			return nil, d.Source.Errorf("%v", err)
+		}
+		name := profileDef.Metadata.Name
+		if _, ok := profiles[name]; ok {
+			return nil, d.Source.Errorf("%s %q is already defined in %s", def.KindTopicProfile, name, sources[name])
+		}
+		profiles[name] = profileDef
+		sources[name] = d.Source
+	}
+	return profiles, nil
+}
+
+// ResolveTopicProfiles resolves the topic profiles referenced by a topic definition.
+// The document of the resolved definition defines the resolved configs, partitions and replication factor
+// in place of the profiles. Definitions that do not reference profiles are returned unchanged.
+func ResolveTopicProfiles(d Definition, profiles map[string]def.TopicProfileDefinition) (Definition, error) {
+	if d.Resource.Kind != def.KindTopic {
+		return d, nil
+	}
+
+	topicDef, err := def.LoadTopicDefinition(d.Document, d.Format, nil, d.LenientAPIVersions)
+	if err != nil || len(topicDef.Spec.Profiles) == 0 {
+		// Invalid definitions are reported when they are applied or validated.
+		return d, nil
+	}
+	if err := topicDef.ResolveProfiles(profiles); err != nil {
+		return d, err
+	}
+
+	doc, err := resolvedTopicDocument(d.Document, d.Format, topicDef.Spec)
+	if err != nil {
+		return d, fmt.Errorf("failed to resolve profiles: %v", err)
+	}
+	d.Document = doc
+	return d, nil
+}
+
+// resolvedTopicDocument returns a topic definition document with the resolved properties of a spec
+// in place of its profiles.
+func resolvedTopicDocument(defDoc string, format opt.DefinitionFormat, spec def.TopicSpecDefinition) (string, error) {
+	j := []byte(defDoc)
+	if format == opt.YAMLFormat {
+		var err error
+		if j, err = yaml.YAMLToJSON(j); err != nil {
+			return "", err
+		}
+	}
+
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	// Decoding numbers as json.Number preserves their original representation.
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return "", err
+	}
+
+	specDoc, _ := doc["spec"].(map[string]interface{})
+	delete(specDoc, "profiles")
+	if len(spec.Configs) > 0 {
+		specDoc["configs"] = spec.Configs
+	}
+	specDoc["partitions"] = spec.Partitions
+	specDoc["replicationFactor"] = spec.ReplicationFactor
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if format == opt.YAMLFormat {
+		if out, err = yaml.JSONToYAML(out); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,136 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestLoadDefinitions_profiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  opt.DefinitionFormat
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "Tests resolving profiles (YAML)",
+			content: `apiVersion: v1
+kind: topicProfile
+metadata:
+  name: compacted
+spec:
+  configs:
+    cleanup.policy: compact
+    min.insync.replicas: "2"
+  replicationFactor: 3
+---
+# Orders topic
+apiVersion: v1
+kind: topic
+metadata:
+  name: store.orders
+spec:
+  profiles: [compacted]
+  configs:
+    min.insync.replicas: "1"
+  partitions: 6
+---
+apiVersion: v1
+kind: topic
+metadata:
+  name: store.events
+spec:
+  partitions: 3
+  replicationFactor: 1`,
+			format: opt.YAMLFormat,
+			want: []string{
+				`apiVersion: v1
+kind: topic
+metadata:
+  name: store.orders
+spec:
+  configs:
+    cleanup.policy: compact
+    min.insync.replicas: "1"
+  partitions: 6
+  replicationFactor: 3`,
+				`apiVersion: v1
+kind: topic
+metadata:
+  name: store.events
+spec:
+  partitions: 3
+  replicationFactor: 1`,
+			},
+		},
+		{
+			name: "Tests resolving profiles (JSON)",
+			content: `[
+  {"apiVersion": "v1", "kind": "topic", "metadata": {"name": "store.orders"}, "spec": {"profiles": ["large"], "replicationFactor": 2}},
+  {"apiVersion": "v1", "kind": "topicProfile", "metadata": {"name": "large"}, "spec": {"partitions": 24}}
+]`,
+			format: opt.JSONFormat,
+			want: []string{
+				`{
+  "apiVersion": "v1",
+  "kind": "topic",
+  "metadata": {
+    "name": "store.orders"
+  },
+  "spec": {
+    "partitions": 24,
+    "replicationFactor": 2
+  }
+}`,
+			},
+		},
+		{
+			name:    "Tests an undefined profile",
+			content: "apiVersion: v1\nkind: topic\nmetadata:\n  name: foo\nspec:\n  profiles: [compacted]\n",
+			format:  opt.YAMLFormat,
+			wantErr: "invalid definitions: line 1 (document 1): topic profile \"compacted\" is not defined",
+		},
+		{
+			name:    "Tests an invalid profile",
+			content: "apiVersion: v1\nkind: topicProfile\nmetadata:\n  name: compacted\nspec:\n  partitions: -1\n",
+			format:  opt.YAMLFormat,
+			wantErr: "line 1 (document 1): partitions must not be negative",
+		},
+		{
+			name: "Tests a duplicate profile",
+			content: `apiVersion: v1
+kind: topicProfile
+metadata:
+  name: compacted
+---
+apiVersion: v1
+kind: topicProfile
+metadata:
+  name: compacted`,
+			format:  opt.YAMLFormat,
+			wantErr: "line 6 (document 2): topicProfile \"compacted\" is already defined in line 1 (document 1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs, err := LoadDefinitions([]byte(tt.content), tt.format, nil, nil)
+			if !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("LoadDefinitions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(defs) != len(tt.want) {
+				t.Fatalf("LoadDefinitions() returned %d definitions, want %d", len(defs), len(tt.want))
+			}
+			for i, d := range defs {
+				if d.Document != tt.want[i] {
+					t.Errorf("LoadDefinitions() document %d = %q, want %q", i, d.Document, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"github.com/peter-evans/kdef/core/helpers/selector"
+)
+
+// SelectDefinitions returns the definitions with labels matching a label selector.
+// All definitions are returned if the selector is nil.
+func SelectDefinitions(defs []Definition, sel selector.Selector) []Definition {
+	if sel == nil {
+		return defs
+	}
+
+	var selected []Definition
+	for _, d := range defs {
+		if sel.Matches(d.Resource.Metadata.Labels) {
+			selected = append(selected, d)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,76 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/helpers/selector"
+	"github.com/peter-evans/kdef/core/model/def"
+)
+
+func TestSelectDefinitions(t *testing.T) {
+	definition := func(name string, labels def.ResourceMetadataLabels) Definition {
+		return Definition{
+			Resource: def.ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       def.KindTopic,
+				Metadata: def.ResourceMetadataDefinition{
+					Name:   name,
+					Labels: labels,
+				},
+			},
+		}
+	}
+	defs := []Definition{
+		definition("payments.events", def.ResourceMetadataLabels{"team": "payments"}),
+		definition("payments.test", def.ResourceMetadataLabels{"team": "payments", "tier": "test"}),
+		definition("orders.events", def.ResourceMetadataLabels{"team": "orders"}),
+		definition("unlabelled", nil),
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		want     []string
+	}{
+		{
+			name: "Tests no selector",
+			want: []string{"payments.events", "payments.test", "orders.events", "unlabelled"},
+		},
+		{
+			name:     "Tests selecting by label value",
+			selector: "team=payments,tier!=test",
+			want:     []string{"payments.events"},
+		},
+		{
+			name:     "Tests selecting by label existence",
+			selector: "!team",
+			want:     []string{"unlabelled"},
+		},
+		{
+			name:     "Tests a selector matching no definitions",
+			selector: "team=finance",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sel selector.Selector
+			if len(tt.selector) > 0 {
+				var err error
+				if sel, err = selector.Parse(tt.selector); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var got []string
+			for _, d := range SelectDefinitions(defs, sel) {
+				got = append(got, d.Resource.Metadata.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectDefinitions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"fmt"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/meta"
+)
+
+// ValidateDefinition validates a definition without a cluster connection.
+// If brokers are supplied, definitions are further validated using the broker metadata.
+func ValidateDefinition(d Definition, brokers meta.Brokers) error {
+	switch d.Resource.Kind {
+	case def.KindACL:
+		localDef, err := def.LoadACLDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		return localDef.Validate()
+	case def.KindBroker:
+		localDef, err := def.LoadBrokerDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		if err := localDef.Validate(); err != nil {
+			return err
+		}
+		if brokers != nil {
+			return localDef.ValidateWithMetadata(brokers)
+		}
+	case def.KindBrokerLogger:
+		localDef, err := def.LoadBrokerLoggerDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		if err := localDef.Validate(); err != nil {
+			return err
+		}
+		if brokers != nil {
+			return localDef.ValidateWithMetadata(brokers)
+		}
+	case def.KindBrokers:
+		localDef, err := def.LoadBrokersDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		return localDef.Validate()
+	case def.KindConsumerGroup:
+		localDef, err := def.LoadConsumerGroupDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		return localDef.Validate()
+	case def.KindQuota:
+		localDef, err := def.LoadQuotaDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		return localDef.Validate()
+	case def.KindTopic:
+		localDef, err := def.LoadTopicDefinition(d.Document, d.Format, d.PropertyOverrides, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		if err := localDef.Validate(); err != nil {
+			return err
+		}
+		if brokers != nil && !localDef.Spec.IsAbsent() {
+			return localDef.ValidateWithMetadata(brokers)
+		}
+	case def.KindTopicProfile:
+		localDef, err := def.LoadTopicProfileDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		return localDef.Validate()
+	case def.KindUser:
+		localDef, err := def.LoadUserDefinition(d.Document, d.Format, d.LenientAPIVersions)
+		if err != nil {
+			return err
+		}
+		return localDef.Validate()
+	default:
+		return fmt.Errorf("invalid definition kind %q", d.Resource.Kind)
+	}
+
+	return nil
+}
+
+// DuplicateDefinitions returns the indices of definitions of resources already defined by a previous definition,
+// mapped to the index of the previous definition.
+func DuplicateDefinitions(defs []Definition) map[int]int {
+	type resource struct {
+		kind                string
+		name                string
+		resourceType        string
+		resourcePatternType string
+	}
+
+	firsts := map[resource]int{}
+	duplicates := map[int]int{}
+	for i, d := range defs {
+		r := resource{
+			kind:                d.Resource.Kind,
+			name:                d.Resource.Metadata.Name,
+			resourceType:        d.Resource.Metadata.Type,
+			resourcePatternType: d.Resource.Metadata.ResourcePatternType,
+		}
+		if first, ok := firsts[r]; ok {
+			duplicates[i] = first
+		} else {
+			firsts[r] = i
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,92 @@
+// Package kdef implements an API to apply resource definitions to a Kafka cluster
+// and export cluster resources to definitions.
+package kdef
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peter-evans/kdef/core/model/def"
+	"github.com/peter-evans/kdef/core/model/meta"
+	"github.com/peter-evans/kdef/core/model/opt"
+	"github.com/peter-evans/kdef/core/test/tutil"
+)
+
+func TestValidateDefinition(t *testing.T) {
+	newTopicDef := func(spec string) Definition {
+		return Definition{
+			Document: "apiVersion: v1\nkind: topic\nmetadata:\n  name: foo\nspec:\n" + spec,
+			Resource: def.ResourceDefinition{
+				APIVersion: "v1",
+				Kind:       def.KindTopic,
+				Metadata:   def.ResourceMetadataDefinition{Name: "foo"},
+			},
+			Format: opt.YAMLFormat,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		d       Definition
+		brokers meta.Brokers
+		wantErr string
+	}{
+		{
+			name:    "Tests a valid definition",
+			d:       newTopicDef("  partitions: 3\n  replicationFactor: 2\n"),
+			wantErr: "",
+		},
+		{
+			name:    "Tests an invalid definition",
+			d:       newTopicDef("  partitions: 3\n  replicationFactor: 0\n"),
+			wantErr: "replication factor must be greater than 0",
+		},
+		{
+			name:    "Tests an unknown field",
+			d:       newTopicDef("  partitions: 3\n  replicationFactor: 2\n  maintainLeader: true\n"),
+			wantErr: "unknown field \"spec.maintainLeader\"",
+		},
+		{
+			name:    "Tests a definition valid with broker metadata",
+			d:       newTopicDef("  partitions: 3\n  replicationFactor: 2\n"),
+			brokers: meta.Brokers{{ID: 1}, {ID: 2}},
+			wantErr: "",
+		},
+		{
+			name:    "Tests a definition invalid with broker metadata",
+			d:       newTopicDef("  partitions: 3\n  replicationFactor: 2\n"),
+			brokers: meta.Brokers{{ID: 1}},
+			wantErr: "replication factor cannot exceed the number of available brokers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDefinition(tt.d, tt.brokers); !tutil.ErrorContains(err, tt.wantErr) {
+				t.Errorf("ValidateDefinition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDuplicateDefinitions(t *testing.T) {
+	newDef := func(kind string, name string, resourceType string) Definition {
+		return Definition{
+			Resource: def.ResourceDefinition{
+				Kind:     kind,
+				Metadata: def.ResourceMetadataDefinition{Name: name, Type: resourceType},
+			},
+		}
+	}
+
+	defs := []Definition{
+		newDef(def.KindTopic, "foo", ""),
+		newDef(def.KindACL, "foo", "topic"),
+		newDef(def.KindACL, "foo", "group"),
+		newDef(def.KindTopic, "foo", ""),
+		newDef(def.KindACL, "foo", "group"),
+	}
+	want := map[int]int{3: 0, 4: 2}
+	if got := DuplicateDefinitions(defs); !reflect.DeepEqual(got, want) {
+		t.Errorf("DuplicateDefinitions() = %v, want %v", got, want)
+	}
+}